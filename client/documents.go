@@ -0,0 +1,35 @@
+// Copyright 2022-2023 Tigris Data, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package client
+
+import (
+	"encoding/json"
+
+	"github.com/tigrisdata/tigris-client-go/driver"
+)
+
+// Documents converts the documents to the driver documents.
+// Only the slice headers are converted, the content of the documents is not copied.
+// Unlike reinterpreting the slice with unsafe.Pointer, the explicit conversion
+// fails to compile if the driver.Document type is changed incompatibly.
+func Documents(docs []json.RawMessage) []driver.Document {
+	res := make([]driver.Document, 0, len(docs))
+
+	for _, doc := range docs {
+		res = append(res, driver.Document(doc))
+	}
+
+	return res
+}
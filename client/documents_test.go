@@ -0,0 +1,42 @@
+// Copyright 2022-2023 Tigris Data, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package client
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/tigrisdata/tigris-client-go/driver"
+)
+
+func TestDocuments(t *testing.T) {
+	docs := []json.RawMessage{
+		json.RawMessage(`{"id":1}`),
+		json.RawMessage(`{"id":2,"name":"b"}`),
+	}
+
+	res := Documents(docs)
+
+	assert.Equal(t, []driver.Document{
+		driver.Document(`{"id":1}`),
+		driver.Document(`{"id":2,"name":"b"}`),
+	}, res)
+
+	// content is shared, not copied
+	assert.Same(t, &docs[1][0], &res[1][0])
+
+	assert.Empty(t, Documents(nil))
+}
@@ -49,7 +49,11 @@ func initConfig(inCfg *config.Config) {
 		SkipLocalTLS: inCfg.SkipLocalTLS,
 	}
 
-	if !cfg.SkipLocalTLS && (inCfg.UseTLS || (cfg.URL == "" && cfg.Protocol == "") ||
+	if isAutoProtocol(cfg.Protocol) && autoProtocol != "" {
+		cfg.Protocol = autoProtocol
+	}
+
+	if !cfg.SkipLocalTLS && (inCfg.UseTLS || (cfg.URL == "" && (cfg.Protocol == "" || isAutoProtocol(inCfg.Protocol))) ||
 		strings.HasSuffix(cfg.URL, config.Domain)) {
 		cfg.TLS = &tls.Config{MinVersion: tls.VersionTLS12}
 	}
@@ -79,10 +83,20 @@ func Init(inCfg *config.Config) error {
 
 func InitLow() error {
 	if D == nil {
-		ctx, cancel := util.GetContext(context.Background())
-		defer cancel()
+		var (
+			drv driver.Driver
+			err error
+		)
+
+		if isAutoProtocol(cfg.Protocol) {
+			drv, err = probeProtocol(context.Background(), cfg)
+		} else {
+			ctx, cancel := util.GetContext(context.Background())
+			defer cancel()
+
+			drv, err = driver.NewDriver(ctx, cfg)
+		}
 
-		drv, err := driver.NewDriver(ctx, cfg)
 		if err != nil {
 			return err
 		}
@@ -116,6 +130,9 @@ func ManagementGet() driver.Management {
 	initConfig(&config.DefaultConfig)
 
 	if M == nil {
+		err := resolveProtocol(context.Background())
+		util.Fatal(err, "tigris protocol selection")
+
 		ctx, cancel := util.GetContext(context.Background())
 		defer cancel()
 
@@ -132,6 +149,9 @@ func ObservabilityGet() driver.Observability {
 	initConfig(&config.DefaultConfig)
 
 	if O == nil {
+		err := resolveProtocol(context.Background())
+		util.Fatal(err, "tigris protocol selection")
+
 		ctx, cancel := util.GetContext(context.Background())
 		defer cancel()
 
@@ -0,0 +1,109 @@
+// Copyright 2022-2023 Tigris Data, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package client
+
+import (
+	"context"
+	"strings"
+
+	"github.com/rs/zerolog/log"
+	"github.com/tigrisdata/tigris-cli/util"
+	cconfig "github.com/tigrisdata/tigris-client-go/config"
+	"github.com/tigrisdata/tigris-client-go/driver"
+)
+
+// ProtocolAuto selects the protocol by probing the server with gRPC first, then with HTTP.
+const ProtocolAuto = "auto"
+
+var (
+	// autoProtocols are the protocols probed in the auto mode, in the order.
+	autoProtocols = []string{driver.GRPC, driver.HTTP}
+
+	// autoProtocol is the protocol selected in the auto mode,
+	// cached, so as the server is probed once per run.
+	autoProtocol string
+
+	newDriver = driver.NewDriver
+)
+
+func isAutoProtocol(proto string) bool {
+	return strings.EqualFold(proto, ProtocolAuto)
+}
+
+// probeProtocol returns the driver connected using the first protocol,
+// the server responds to the health check with.
+// The error of the first protocol is returned when none of the protocols work.
+// The ctx should not carry a deadline, each probe sets its own timeout.
+func probeProtocol(ctx context.Context, cfg *cconfig.Driver) (driver.Driver, error) {
+	var firstErr error
+
+	for _, proto := range autoProtocols {
+		drv, err := probe(ctx, cfg, proto)
+		if err == nil {
+			log.Debug().Str("protocol", proto).Str("url", cfg.URL).Msg("auto selected protocol")
+
+			autoProtocol = proto
+			cfg.Protocol = proto
+
+			return drv, nil
+		}
+
+		log.Debug().Err(err).Str("protocol", proto).Msg("protocol probe failed")
+
+		if firstErr == nil {
+			firstErr = err
+		}
+	}
+
+	return nil, firstErr
+}
+
+// probe connects using the given protocol and checks the server health.
+// Every probe gets its own timeout, so a blocking dial of one protocol
+// doesn't eat the time left for the next one.
+func probe(ctx context.Context, cfg *cconfig.Driver, proto string) (driver.Driver, error) {
+	ctx, cancel := util.GetContext(ctx)
+	defer cancel()
+
+	c := *cfg
+	c.Protocol = proto
+
+	drv, err := newDriver(ctx, &c)
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err = drv.Health(ctx); err != nil {
+		_ = drv.Close()
+
+		return nil, err
+	}
+
+	return drv, nil
+}
+
+// resolveProtocol probes the server in the auto mode, if the protocol has not been selected yet.
+func resolveProtocol(ctx context.Context) error {
+	if !isAutoProtocol(cfg.Protocol) {
+		return nil
+	}
+
+	drv, err := probeProtocol(ctx, cfg)
+	if err != nil {
+		return err
+	}
+
+	return drv.Close()
+}
@@ -0,0 +1,127 @@
+// Copyright 2022-2023 Tigris Data, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package client
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tigrisdata/tigris-cli/config"
+	cconfig "github.com/tigrisdata/tigris-client-go/config"
+	"github.com/tigrisdata/tigris-client-go/driver"
+)
+
+var errProbe = fmt.Errorf("connection refused")
+
+type probeDriver struct {
+	driver.Driver
+
+	healthy bool
+	closed  bool
+}
+
+func (d *probeDriver) Health(_ context.Context) (*driver.HealthResponse, error) {
+	if !d.healthy {
+		return nil, errProbe
+	}
+
+	return &driver.HealthResponse{}, nil
+}
+
+func (d *probeDriver) Close() error {
+	d.closed = true
+
+	return nil
+}
+
+func TestProbeProtocol(t *testing.T) {
+	defer func() {
+		newDriver = driver.NewDriver
+		autoProtocol = ""
+	}()
+
+	drivers := map[string]*probeDriver{}
+	healthy := map[string]bool{}
+
+	newDriver = func(_ context.Context, cfg *cconfig.Driver) (driver.Driver, error) {
+		d := &probeDriver{healthy: healthy[cfg.Protocol]}
+		drivers[cfg.Protocol] = d
+
+		return d, nil
+	}
+
+	// falls back to http
+	healthy[driver.HTTP] = true
+
+	cfg := &cconfig.Driver{Protocol: ProtocolAuto}
+
+	drv, err := probeProtocol(context.Background(), cfg)
+	require.NoError(t, err)
+	assert.Same(t, drivers[driver.HTTP], drv)
+	assert.True(t, drivers[driver.GRPC].closed)
+	assert.Equal(t, driver.HTTP, cfg.Protocol)
+	assert.Equal(t, driver.HTTP, autoProtocol)
+
+	// grpc is preferred
+	healthy[driver.GRPC] = true
+
+	cfg = &cconfig.Driver{Protocol: ProtocolAuto}
+
+	drv, err = probeProtocol(context.Background(), cfg)
+	require.NoError(t, err)
+	assert.Same(t, drivers[driver.GRPC], drv)
+	assert.Equal(t, driver.GRPC, autoProtocol)
+
+	// error of the first protocol is returned
+	healthy = map[string]bool{}
+
+	_, err = probeProtocol(context.Background(), &cconfig.Driver{Protocol: ProtocolAuto})
+	require.ErrorIs(t, err, errProbe)
+}
+
+func TestProbeProtocolTimeout(t *testing.T) {
+	defer func() {
+		newDriver = driver.NewDriver
+		autoProtocol = ""
+		config.DefaultConfig.Timeout = 0
+	}()
+
+	config.DefaultConfig.Timeout = 50 * time.Millisecond
+
+	// blocking grpc dial must not leave http with an expired context
+	newDriver = func(ctx context.Context, cfg *cconfig.Driver) (driver.Driver, error) {
+		if cfg.Protocol == driver.GRPC {
+			<-ctx.Done()
+
+			return nil, ctx.Err()
+		}
+
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+
+		return &probeDriver{healthy: true}, nil
+	}
+
+	cfg := &cconfig.Driver{Protocol: ProtocolAuto}
+
+	_, err := probeProtocol(context.Background(), cfg)
+	require.NoError(t, err)
+	assert.Equal(t, driver.HTTP, cfg.Protocol)
+}
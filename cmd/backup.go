@@ -17,6 +17,7 @@ package cmd
 import (
 	"bufio"
 	"context"
+	"encoding/json"
 	"fmt"
 	"os"
 	"time"
@@ -37,8 +38,53 @@ var (
 	verboseBackup       bool
 	backupFileExtension = "backup"
 	schemaFileExtension = "schema"
+
+	timeField   string
+	backupSince string
+	backupUntil string
+
+	ErrTimeFieldRequired = fmt.Errorf("--since and --until require --time-field")
 )
 
+// backupFilter returns the filter which selects the documents with the timeField
+// in the [since, until) range. Empty filter is returned if no time range is specified.
+func backupFilter(now time.Time) (driver.Filter, error) {
+	if backupSince == "" && backupUntil == "" {
+		return driver.Filter(`{}`), nil
+	}
+
+	if timeField == "" {
+		return nil, ErrTimeFieldRequired
+	}
+
+	conds := make([]map[string]any, 0, 2)
+
+	for _, v := range []struct{ op, value string }{{"$gte", backupSince}, {"$lt", backupUntil}} {
+		if v.value == "" {
+			continue
+		}
+
+		t, err := util.ParseTime(v.value, now)
+		if err != nil {
+			return nil, err
+		}
+
+		conds = append(conds, map[string]any{timeField: map[string]any{v.op: t.UTC().Format(time.RFC3339Nano)}})
+	}
+
+	var filter any = conds[0]
+	if len(conds) > 1 {
+		filter = map[string]any{"$and": conds}
+	}
+
+	b, err := json.Marshal(filter)
+	if err != nil {
+		return nil, err
+	}
+
+	return b, nil
+}
+
 // listProjects returns the projects/databases available in Tigris as a string array
 // but filters the output using the filters specified via the command line.
 func listProjects(ctx context.Context) ([]string, error) {
@@ -78,16 +124,17 @@ func listCollections(ctx context.Context, dbName string) ([]string, error) {
 }
 
 // writeCollection downloads the data of a collection from Tigris and stores it
-// in a file post-fixed with backupFileExtension. The function returns the bytes
+// in a file post-fixed with backupFileExtension. Only the documents matching the filter
+// are stored. The function returns the bytes
 // written and an error, if applicable.
-func writeCollection(ctx context.Context, db, collection, file string) (int, error) {
+func writeCollection(ctx context.Context, db, collection, file string, filter driver.Filter) (int, error) {
 	var (
 		doc   driver.Document
 		bytes int
 	)
 
 	it, err := client.Get().UseDatabase(db).Read(ctx, collection,
-		driver.Filter(`{}`),
+		filter,
 		driver.Projection(`{}`),
 	)
 	if err != nil {
@@ -174,8 +221,20 @@ var backupCmd = &cobra.Command{
 	Long: `Dumps documents and schemas to JSON files into the directory specified in the argument.
 
 	If a project name filter is provided it only dumps the schemas of the projects specified.
-	Likewise, collection filters will limit the output to matching collection names.`,
+	Likewise, collection filters will limit the output to matching collection names.
+
+	Incremental backup of recent changes is possible with --since and --until,
+	which select the documents by the timestamp field specified by --time-field.
+	The range includes --since and excludes --until. The values are RFC3339 time
+	or duration relative to the current time, like -24h. Documents without the field are skipped.`,
+	Example: fmt.Sprintf(`
+  # Dump the documents updated during the last day
+  %[1]s backup --time-field=updated_at --since=-24h
+`, rootCmd.Root().Name()),
 	Run: func(cmd *cobra.Command, args []string) {
+		filter, err := backupFilter(time.Now())
+		util.Fatal(err, "time filter")
+
 		login.Ensure(cmd.Context(), func(_ context.Context) error {
 			util.Stdoutf(" [i] using timeout %d\n", backupTimeout)
 			ctx, cancel := context.WithTimeout(context.Background(), time.Duration(backupTimeout)*time.Second)
@@ -208,7 +267,7 @@ var backupCmd = &cobra.Command{
 					start := time.Now()
 					path := fmt.Sprintf("%s/%s.%s.%s", destDir, db, collection, backupFileExtension)
 					util.Stdoutf(" [*] %s\n", path)
-					bytes, err := writeCollection(ctx, db, collection, path)
+					bytes, err := writeCollection(ctx, db, collection, path, filter)
 					if err != nil {
 						return util.Error(err, "failed to write collection")
 					}
@@ -232,5 +291,11 @@ func init() {
 		"timeout specification in seconds")
 	backupCmd.Flags().BoolVarP(&verboseBackup, "verbose", "v", false,
 		"verbose output")
+	backupCmd.Flags().StringVar(&timeField, "time-field", "",
+		"timestamp field to filter documents by with --since and --until")
+	backupCmd.Flags().StringVar(&backupSince, "since", "",
+		"dump documents with --time-field at or after the time: RFC3339 time or relative duration, like -24h")
+	backupCmd.Flags().StringVar(&backupUntil, "until", "",
+		"dump documents with --time-field before the time: RFC3339 time or relative duration, like -1h")
 	rootCmd.AddCommand(backupCmd)
 }
@@ -18,6 +18,9 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"os"
+	"strconv"
+	"strings"
 
 	"github.com/spf13/cobra"
 	"github.com/tigrisdata/tigris-cli/client"
@@ -85,21 +88,67 @@ var describeCollectionCmd = &cobra.Command{
 	},
 }
 
+// collectionDetails is the output of list collections --detailed.
+type collectionDetails struct {
+	Collection string `json:"collection"`
+	Size       int64  `json:"size"`
+	Documents  int64  `json:"documents"`
+}
+
+func describeCollectionDetails(ctx context.Context, coll string) (*collectionDetails, error) {
+	desc, err := client.GetDB().DescribeCollection(ctx, coll)
+	if err != nil {
+		return nil, err
+	}
+
+	cnt, err := client.GetDB().Count(ctx, coll, driver.Filter("{}"))
+	if err != nil {
+		return nil, err
+	}
+
+	return &collectionDetails{Collection: coll, Size: desc.Size, Documents: cnt}, nil
+}
+
 var listCollectionsCmd = &cobra.Command{
 	Use:   "collections",
 	Short: "Lists project collections",
+	Long: `Lists project collections.
+With --detailed flag outputs size in bytes and number of documents of every collection.
+With --output=json outputs JSON array of the collections.`,
 	Run: func(cmd *cobra.Command, args []string) {
+		util.Fatal(util.ValidateOutput(), "output")
+
 		login.Ensure(cmd.Context(), func(ctx context.Context) error {
 			resp, err := client.GetDB().ListCollections(ctx)
 			if err != nil {
 				return util.Error(err, "list collections")
 			}
 
+			if !detailed {
+				rows := make([][]string, 0, len(resp))
+				for _, v := range resp {
+					rows = append(rows, []string{v})
+				}
+
+				return util.Error(util.WriteList(os.Stdout, nil, rows, resp), "list collections output")
+			}
+
+			items := make([]*collectionDetails, 0, len(resp))
+			rows := make([][]string, 0, len(resp))
+
 			for _, v := range resp {
-				util.Stdoutf("%s\n", v)
+				d, err := describeCollectionDetails(ctx, v)
+				if err != nil {
+					return util.Error(err, "describe collection: %s", v)
+				}
+
+				items = append(items, d)
+				rows = append(rows, []string{d.Collection,
+					strconv.FormatInt(d.Size, 10), strconv.FormatInt(d.Documents, 10)})
 			}
 
-			return nil
+			return util.Error(util.WriteList(os.Stdout, []string{"COLLECTION", "SIZE", "DOCUMENTS"}, rows, items),
+				"list collections output")
 		})
 	},
 }
@@ -173,16 +222,53 @@ var createCollectionCmd = &cobra.Command{
 	},
 }
 
+// collectionExists checks the existence of the collection outside of the transaction,
+// so as the missing collection doesn't fail the transaction, which drops the others.
+func collectionExists(ctx context.Context, coll string) (bool, error) {
+	_, err := client.GetDB().DescribeCollection(ctx, coll)
+	if isErrorCode(err, api.Code_NOT_FOUND) {
+		return false, nil
+	}
+
+	return err == nil, err
+}
+
 var dropCollectionCmd = &cobra.Command{
-	Use:   "collection",
+	Use:   "collection {collection}...|-",
 	Short: "Drops collection",
-	Args:  cobra.MinimumNArgs(1),
+	Long: `Drops the collections and all their documents.
+When run interactively, asks for confirmation, unless --yes is specified
+or the names of the collections are read from the standard input, by "-".
+With --if-exists collections which don't exist are skipped, instead of failing the command.`,
+	Example: fmt.Sprintf(`
+  %[1]s drop collection --project=myproj users
+  %[1]s drop collection --project=myproj --yes --if-exists users orders
+`, rootCmd.Root().Name()),
+	Args: cobra.MinimumNArgs(1),
 	Run: func(cmd *cobra.Command, args []string) {
+		if !DropYes {
+			util.Fatal(util.ConfirmArgs(args, "Drop collection(s) %s in project %s?", strings.Join(args, ", "),
+				config.GetProjectName()), "drop collection")
+		}
+
 		login.Ensure(cmd.Context(), func(ctx context.Context) error {
 			return client.Transact(ctx, config.GetProjectName(), func(ctx context.Context, tx driver.Tx) error {
 				return iterate.Input(ctx, cmd, 0, args, func(ctx context.Context, args []string, docs []json.RawMessage) error {
 					for _, v := range docs {
-						if err := tx.DropCollection(ctx, string(v)); err != nil {
+						if DropIfExists {
+							exists, err := collectionExists(ctx, string(v))
+							if err != nil {
+								return util.Error(err, "describe collection")
+							}
+
+							if !exists {
+								util.Infof("collection doesn't exist: %s", string(v))
+								continue
+							}
+						}
+
+						err := tx.DropCollection(ctx, string(v))
+						if err != nil {
 							return util.Error(err, "drop collection")
 						}
 						util.Infof("dropped collection: %s", string(v))
@@ -245,9 +331,18 @@ func init() {
 	addProjectFlag(alterCollectionCmd)
 	addProjectFlag(describeCollectionCmd)
 
+	listCollectionsCmd.Flags().StringVar(&util.Output, "output", util.OutputText,
+		"Output format: text, json")
+	listCollectionsCmd.Flags().BoolVar(&detailed, "detailed", false,
+		"Output collections size and number of documents")
 	describeCollectionCmd.Flags().StringVarP(&format, "format", "f", "",
 		"output schema in the requested format: go, typescript, java")
 
+	dropCollectionCmd.Flags().BoolVarP(&DropYes, "yes", "y", false,
+		"Don't ask for confirmation")
+	dropCollectionCmd.Flags().BoolVar(&DropIfExists, "if-exists", false,
+		"Don't fail if the collection doesn't exist")
+
 	dropCmd.AddCommand(dropCollectionCmd)
 	createCmd.AddCommand(createCollectionCmd)
 	listCmd.AddCommand(listCollectionsCmd)
@@ -15,6 +15,8 @@
 package cmd
 
 import (
+	"fmt"
+
 	"github.com/spf13/cobra"
 	"github.com/tigrisdata/tigris-cli/config"
 	"github.com/tigrisdata/tigris-cli/util"
@@ -24,6 +26,33 @@ import (
 var configCmd = &cobra.Command{
 	Use:   "config",
 	Short: "Configuration commands",
+	Long: `Configuration commands.
+
+The config file tigris-cli.yaml, .yml, .json or .toml is looked up in
+/etc/tigris/, $HOME/.tigris/, ./config/ and the current directory, the first found is loaded.
+The values of the config file are overridden by TIGRIS_ prefixed environment variables,
+for example TIGRIS_URL, TIGRIS_PROJECT or TIGRIS_LOG_LEVEL.
+
+The default project is set by the project key of the config file,
+or by TIGRISDB_PROJECT environment variable, which takes precedence.
+TIGRIS_PROJECT environment variable takes precedence over TIGRISDB_PROJECT.
+The --project flag overrides all of them.
+
+The protocol key of the config file, or TIGRIS_PROTOCOL environment variable,
+is grpc (default), http or auto. With auto the server is probed with gRPC first,
+falling back to HTTP, when the server doesn't respond to gRPC health check.
+The selected protocol is used for the rest of the run and logged at debug level.
+
+The authentication token can be read from the file, set by the token_file key
+of the config file, TIGRIS_TOKEN_FILE environment variable or --token-file flag,
+for example from the mounted Kubernetes secret. Surrounding whitespace is trimmed.
+The file is read again, when the token is rejected by the server,
+so the rotated token is picked up. The token read from the file is not saved to the config file.
+
+Set TIGRISDB_NO_CONFIG_FILE=1 to skip the lookup of the config file entirely,
+so the config is loaded from the defaults and the environment variables only,
+for example in containerized deployments. The config file is still written
+by the commands which save the config, like login or branch.`,
 }
 
 var showConfigCmd = &cobra.Command{
@@ -41,7 +70,46 @@ var showConfigCmd = &cobra.Command{
 	},
 }
 
+var migrateDryRun bool
+
+var migrateConfigCmd = &cobra.Command{
+	Use:   "migrate",
+	Short: "Upgrades the config file to the current layout",
+	Long: `Transforms deprecated keys of the config file to their current equivalents.
+
+The deprecated key is removed if the current key is already set.
+The original file is backed up to the file with .bak extension,
+the migrated config is written back in the same format.
+Comments of the config file are not preserved.`,
+	Example: fmt.Sprintf(`
+  # Show the transformations without modifying the config file
+  %[1]s config migrate --dry-run
+`, rootCmd.Root().Name()),
+	Args: cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		file, res, err := config.Migrate(config.DefaultName, migrateDryRun)
+		util.Fatal(err, "migrate config")
+
+		for _, m := range res {
+			util.Stdoutf("%s\n", m)
+		}
+
+		switch {
+		case len(res) == 0:
+			util.Stdoutf("%s is up to date\n", file)
+		case migrateDryRun:
+			util.Stdoutf("%s would be migrated\n", file)
+		default:
+			util.Stdoutf("%s migrated, backup saved to %s.bak\n", file, file)
+		}
+	},
+}
+
 func init() {
+	migrateConfigCmd.Flags().BoolVar(&migrateDryRun, "dry-run", false,
+		"Report the transformations without modifying the config file")
+
 	configCmd.AddCommand(showConfigCmd)
+	configCmd.AddCommand(migrateConfigCmd)
 	rootCmd.AddCommand(configCmd)
 }
@@ -0,0 +1,178 @@
+// Copyright 2022-2023 Tigris Data, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+
+	"github.com/spf13/cobra"
+	"github.com/tigrisdata/tigris-cli/client"
+	"github.com/tigrisdata/tigris-cli/iterate"
+	"github.com/tigrisdata/tigris-cli/login"
+	"github.com/tigrisdata/tigris-cli/schema"
+	"github.com/tigrisdata/tigris-cli/util"
+	"github.com/tigrisdata/tigris-client-go/driver"
+)
+
+var (
+	copyFilter     string
+	copyTransforms []string
+
+	ErrCopySameCollection = fmt.Errorf("source and destination collections should be different")
+)
+
+// copyDocs returns the function, which reads the documents of the source collection one by one,
+// applying the renames, until io.EOF.
+func copyDocs(it driver.Iterator, rn util.Renames) func() (json.RawMessage, error) {
+	return func() (json.RawMessage, error) {
+		var doc driver.Document
+
+		if !it.Next(&doc) {
+			if err := it.Err(); err != nil {
+				return nil, err
+			}
+
+			return nil, io.EOF
+		}
+
+		b, err := rn.Apply(append([]byte(nil), doc...))
+		if err != nil {
+			return nil, err
+		}
+
+		return b, nil
+	}
+}
+
+// openCopyDestination opens the importer of the destination collection.
+// Without transforms the destination is created or updated with the schema of the source collection,
+// otherwise the schema is inferred from the copied documents.
+func openCopyDestination(ctx context.Context, srcDB, srcColl, dstDB, dstColl string, transform bool,
+) (*importer, error) {
+	imp := newImporter(dstDB, dstColl)
+
+	if imp.loadSchema(ctx) {
+		if !imp.append {
+			util.Fatal(ErrNoAppend, "describe destination collection")
+		}
+	} else {
+		imp.creating = true
+	}
+
+	if transform {
+		return imp, nil
+	}
+
+	resp, err := client.Get().UseDatabase(srcDB).DescribeCollection(ctx, srcColl)
+	if err != nil {
+		return nil, util.Error(err, "describe source collection")
+	}
+
+	b, err := schema.WithTitle(resp.Schema, dstColl)
+	if err != nil {
+		return nil, util.Error(err, "source collection schema")
+	}
+
+	if err = imp.createWithSchema(ctx, b, "source collection: "+srcColl); err != nil {
+		return nil, err
+	}
+
+	return imp, nil
+}
+
+var copyCmd = &cobra.Command{
+	Use:   "copy {src-project} {src-collection} {dst-project} {dst-collection}",
+	Short: "Copies documents from one collection to another",
+	Long: `Copies the documents of the source collection to the destination collection,
+which is created with the schema of the source collection, if it doesn't exist.
+The documents are streamed from the source to the destination in batches,
+without storing them locally. The projects can be the same or different.
+
+Only the documents matching --filter are copied.
+The fields of the copied documents are renamed with --transform old=new,
+nested fields are specified using dot notation. In this case the schema
+of the destination collection is inferred from the copied documents, like by the import.
+
+Copying into existing collection requires --append, the schema of the destination
+is updated to the schema of the source, unless --transform is used.
+The documents with the primary key which already exists in the destination
+are handled according to --on-conflict.`,
+	Example: fmt.Sprintf(`
+  # Copy all the users to the staging project
+  %[1]s copy myproj users staging users
+
+  # Copy active users to the new collection, renaming the name field
+  %[1]s copy myproj users myproj active_users --filter '{"status": "active"}' --transform name=full_name
+`, rootCmd.Root().Name()),
+	Args: cobra.ExactArgs(4),
+	Run: func(cmd *cobra.Command, args []string) {
+		srcDB, srcColl, dstDB, dstColl := args[0], args[1], args[2], args[3]
+
+		if srcDB == dstDB && srcColl == dstColl {
+			util.Fatal(ErrCopySameCollection, "copy")
+		}
+
+		util.Fatal(checkOnConflict(), "on conflict")
+
+		rn, err := util.ParseRenames(copyTransforms)
+		util.Fatal(err, "parse transforms")
+
+		login.Ensure(cmd.Context(), func(ctx context.Context) error {
+			imp, err := openCopyDestination(ctx, srcDB, srcColl, dstDB, dstColl, len(rn) > 0)
+			if err != nil {
+				return err
+			}
+
+			// the source is read as long as the copy goes, so the context without timeout is used
+			it, err := client.Get().UseDatabase(srcDB).Read(cmd.Context(), srcColl,
+				driver.Filter(copyFilter), driver.Projection("{}"))
+			if err != nil {
+				return util.Error(err, "read source collection")
+			}
+
+			defer it.Close()
+
+			err = iterate.DocsInput(cmd.Context(), args, copyDocs(it, rn),
+				func(ctx context.Context, args []string, docs []json.RawMessage) error {
+					return imp.insertWithInference(ctx, docs)
+				})
+			if err != nil {
+				return err
+			}
+
+			util.Infof("Copied %d document(s) from %s.%s to %s.%s", imp.inserted, srcDB, srcColl, dstDB, dstColl)
+
+			return imp.finish()
+		})
+	},
+}
+
+func init() {
+	copyCmd.Flags().StringVar(&copyFilter, "filter", "{}",
+		"Copy only the documents matching the filter, like: {\"status\": \"active\"}")
+	copyCmd.Flags().StringSliceVar(&copyTransforms, "transform", nil,
+		"Rename the fields of the copied documents: old=new. Nested fields are specified using dot notation")
+	copyCmd.Flags().Int32VarP(&iterate.BatchSize, "batch-size", "b", iterate.BatchSize, "set batch size")
+	copyCmd.Flags().DurationVar(&iterate.BatchTimeout, "batch-timeout", 0,
+		"Timeout of a single batch request, every batch gets its own deadline. Default: no timeout")
+	copyCmd.Flags().BoolVarP(&Append, "append", "a", false,
+		"Copy into existing collection")
+	addOnConflictFlag(copyCmd)
+
+	rootCmd.AddCommand(copyCmd)
+}
@@ -18,6 +18,11 @@ import (
 	"github.com/spf13/cobra"
 )
 
+var (
+	DropYes      bool
+	DropIfExists bool
+)
+
 var dropCmd = &cobra.Command{
 	Use:   "drop",
 	Short: "Drops collection or application",
@@ -0,0 +1,118 @@
+// Copyright 2022-2023 Tigris Data, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/spf13/cobra"
+	"github.com/tigrisdata/tigris-cli/iterate"
+	"github.com/tigrisdata/tigris-cli/util"
+)
+
+var (
+	headLines int32 = 10
+
+	errHeadDone = fmt.Errorf("head done")
+)
+
+var headCmd = &cobra.Command{
+	Use:   "head [{source}]",
+	Short: "Previews first documents of the import source",
+	Long: `Parses and pretty prints the first documents of the source, without contacting the server.
+Source is a file name, http://, https:// or gs:// URL, or "-" for the standard input, which is the default.
+Gzip compressed gs:// sources are decompressed automatically.
+
+The format of the source is detected the same way as in the import command:
+CSV, JSON array or stream of JSON documents.
+MessagePack stream is read with --format=msgpack.`,
+	Example: fmt.Sprintf(`
+  %[1]s head users.json
+  %[1]s head -n 3 https://example.com/users.csv
+  cat users.json | %[1]s head
+`, rootCmd.Root().Name()),
+	Args: cobra.MaximumNArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		source := "-"
+		if len(args) > 0 {
+			source = args[0]
+		}
+
+		if headLines <= 0 {
+			return
+		}
+
+		util.Fatal(iterate.ValidateFormat(), "input format")
+
+		err := iterate.CSVConfigure(CSVDelimiter, CSVComment, CSVTrimLeadingSpace, false)
+		util.Fatal(err, "csv configure")
+
+		// read no more documents than requested
+		iterate.BatchSize = headLines
+		iterate.NoProgress = true
+
+		var printed int32
+
+		err = iterate.SourceInput(cmd.Context(), args, source,
+			func(ctx context.Context, args []string, docs []json.RawMessage) error {
+				for _, doc := range docs {
+					if printed >= headLines {
+						return errHeadDone
+					}
+
+					if err := util.PrettyJSON(doc); err != nil {
+						return err
+					}
+
+					printed++
+				}
+
+				if printed >= headLines {
+					return errHeadDone
+				}
+
+				return nil
+			})
+		if err != nil && !errors.Is(err, errHeadDone) {
+			util.Fatal(err, "head")
+		}
+	},
+}
+
+func init() {
+	headCmd.Flags().Int32VarP(&headLines, "lines", "n", headLines, "Number of documents to print")
+	headCmd.Flags().StringVar(&CSVDelimiter, "csv-delimiter", "",
+		"CSV delimiter. Escape sequences, like \\t, are interpreted. "+
+			"Multi-character delimiters, like ||, are supported at the cost of slower import")
+	headCmd.Flags().BoolVar(&CSVTrimLeadingSpace, "csv-trim-leading-space", true,
+		"Trim leading space in the fields")
+	headCmd.Flags().StringVar(&CSVComment, "csv-comment", "",
+		"CSV comment")
+	headCmd.Flags().StringArrayVar(&iterate.CSVArrayFields, "csv-array-field", []string{},
+		"Split the CSV column into array by the delimiter: name:delimiter, for example tags:;. Can be repeated")
+	headCmd.Flags().StringVar(&iterate.Format, "format", "",
+		"Format of the input: json, csv, msgpack. JSON and CSV are detected automatically if not set. "+
+			"MessagePack binary values are converted to base64 strings and timestamps to RFC3339 strings")
+	headCmd.Flags().StringVar(&iterate.InputEncoding, "input-encoding", "",
+		"Character encoding of the input, which is converted to UTF-8 before parsing. "+
+			"For example: latin1, windows-1252, utf-16le. Default: utf-8")
+	headCmd.Flags().StringVar(&iterate.GCSProject, "gcs-project", "",
+		"Google Cloud project billed for reading gs:// sources from requester pays buckets")
+
+	rootCmd.AddCommand(headCmd)
+}
@@ -0,0 +1,386 @@
+// Copyright 2022-2023 Tigris Data, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"github.com/spf13/cobra"
+	"github.com/tigrisdata/tigris-cli/config"
+	"github.com/tigrisdata/tigris-cli/iterate"
+	"github.com/tigrisdata/tigris-cli/login"
+	"github.com/tigrisdata/tigris-cli/schema"
+	"github.com/tigrisdata/tigris-cli/util"
+	"gopkg.in/yaml.v2"
+)
+
+var (
+	// ManifestParallel is the number of the collections of the manifest imported concurrently.
+	ManifestParallel int
+
+	// importEntryFn imports single collection of the manifest, replaced in tests.
+	importEntryFn = importEntry
+
+	ErrManifestEmpty      = fmt.Errorf("manifest has no collections")
+	ErrManifestCollection = fmt.Errorf("manifest entry should have collection name")
+	ErrManifestSources    = fmt.Errorf("manifest entry should have at least one source")
+	ErrManifestParallel   = fmt.Errorf("--parallel should be at least 1")
+)
+
+// manifestEntry describes the import of a single collection.
+// Options have the same meaning as the flags of the import command.
+type manifestEntry struct {
+	Collection string   `yaml:"collection"`
+	Sources    []string `yaml:"sources"`
+
+	PrimaryKey     []string `yaml:"primary_key"`
+	AutoGenerate   []string `yaml:"autogenerate"`
+	SecondaryIndex []string `yaml:"secondary_index"`
+	SchemaFile     string   `yaml:"schema_file"`
+
+	Append     bool   `yaml:"append"`
+	NoCreate   bool   `yaml:"no_create_collection"`
+	OnConflict string `yaml:"on_conflict"`
+
+	CSVDelimiter        string `yaml:"csv_delimiter"`
+	CSVComment          string `yaml:"csv_comment"`
+	CSVTrimLeadingSpace *bool  `yaml:"csv_trim_leading_space"`
+	CSVNoHeader         bool   `yaml:"csv_no_header"`
+}
+
+// manifest describes the imports of multiple collections, run by import-manifest command.
+type manifest struct {
+	Project     string          `yaml:"project"`
+	Collections []manifestEntry `yaml:"collections"`
+}
+
+// manifestResult is the per collection summary of the manifest import.
+type manifestResult struct {
+	collection string
+	inserted   int64
+	skipped    int64
+	failed     int64
+	err        error
+}
+
+// resolvePath makes the local path relative to the manifest directory.
+func resolvePath(dir string, path string) string {
+	if path == "" || path == "-" || strings.Contains(path, "://") || filepath.IsAbs(path) {
+		return path
+	}
+
+	return filepath.Join(dir, path)
+}
+
+// loadManifest reads and validates the manifest file.
+// Relative paths of the sources and schema files are resolved against the directory of the manifest.
+func loadManifest(name string) (*manifest, error) {
+	b, err := os.ReadFile(name)
+	if err != nil {
+		return nil, err
+	}
+
+	var m manifest
+
+	if err = yaml.UnmarshalStrict(b, &m); err != nil {
+		return nil, err
+	}
+
+	if len(m.Collections) == 0 {
+		return nil, ErrManifestEmpty
+	}
+
+	dir := filepath.Dir(name)
+
+	for k := range m.Collections {
+		e := &m.Collections[k]
+
+		if e.Collection == "" {
+			return nil, fmt.Errorf("%w: entry %d", ErrManifestCollection, k+1)
+		}
+
+		if len(e.Sources) == 0 {
+			return nil, fmt.Errorf("%w: %s", ErrManifestSources, e.Collection)
+		}
+
+		for i := range e.Sources {
+			e.Sources[i] = resolvePath(dir, e.Sources[i])
+		}
+
+		e.SchemaFile = resolvePath(dir, e.SchemaFile)
+	}
+
+	return &m, nil
+}
+
+// importer returns the importer of the collection with the options of the manifest entry.
+// Every entry has its own run of the input, so the documents and the CSV options
+// of the collections imported concurrently are independent of each other.
+func (e *manifestEntry) importer(db string) (*importer, error) {
+	onConflict := e.OnConflict
+	if onConflict == "" {
+		onConflict = util.OnConflictError
+	}
+
+	if err := util.CheckOnConflict(onConflict); err != nil {
+		return nil, err
+	}
+
+	imp := newImporter(db, e.Collection)
+
+	imp.importOptions = importOptions{
+		primaryKey:     e.PrimaryKey,
+		autoGenerate:   e.AutoGenerate,
+		secondaryIndex: e.SecondaryIndex,
+		schemaFile:     e.SchemaFile,
+		append:         e.Append,
+		noCreate:       e.NoCreate,
+		onConflict:     onConflict,
+		csvNoHeader:    e.CSVNoHeader,
+	}
+	imp.run = iterate.NewRun()
+
+	// leading space is trimmed by default, same as --csv-trim-leading-space
+	trimLeadingSpace := defaultCSVTrimLeadingSpace
+	if e.CSVTrimLeadingSpace != nil {
+		trimLeadingSpace = *e.CSVTrimLeadingSpace
+	}
+
+	if err := imp.run.CSV.Configure(e.CSVDelimiter, e.CSVComment, trimLeadingSpace, e.CSVNoHeader); err != nil {
+		return nil, err
+	}
+
+	return imp, nil
+}
+
+// importEntry runs the import of a single collection of the manifest
+// through the same pipeline as the import command.
+// The collection is opened within the request timeout, while the documents are streamed
+// with the context of the command, so the imports of the previous entries don't eat
+// into the deadline of the next ones.
+func importEntry(ctx context.Context, db string, e *manifestEntry) *manifestResult {
+	res := &manifestResult{collection: e.Collection}
+
+	imp, err := e.importer(db)
+	if err != nil {
+		res.err = err
+		return res
+	}
+
+	ctx = iterate.WithRun(ctx, imp.run)
+
+	octx, cancel := util.GetContext(ctx)
+	res.err = imp.open(octx)
+
+	cancel()
+
+	if res.err != nil {
+		return res
+	}
+
+	res.err = iterate.SourcesInput(ctx, []string{e.Collection}, e.Sources,
+		func(ctx context.Context, args []string, docs []json.RawMessage) error {
+			return imp.insertWithInference(ctx, docs)
+		})
+
+	if res.err == nil {
+		res.err = imp.finish()
+	}
+
+	res.inserted = imp.inserted
+	res.skipped = imp.skippedExisting
+	res.failed = imp.run.Skipped
+
+	return res
+}
+
+// importEntries imports the collections of the manifest by ManifestParallel goroutines,
+// in the order of the manifest. No more collections are started after the first failed one.
+// Returns the results in the order of the manifest, for the collections which have been started.
+func importEntries(ctx context.Context, db string, m *manifest) []*manifestResult {
+	var (
+		mu      sync.Mutex
+		wg      sync.WaitGroup
+		failed  bool
+		next    int
+		results = make([]*manifestResult, len(m.Collections))
+	)
+
+	worker := func() {
+		defer wg.Done()
+
+		for {
+			mu.Lock()
+
+			if failed || next >= len(m.Collections) {
+				mu.Unlock()
+				return
+			}
+
+			k := next
+			next++
+
+			mu.Unlock()
+
+			res := importEntryFn(ctx, db, &m.Collections[k])
+
+			mu.Lock()
+
+			results[k] = res
+			failed = failed || res.err != nil
+
+			mu.Unlock()
+		}
+	}
+
+	for i := 0; i < ManifestParallel && i < len(m.Collections); i++ {
+		wg.Add(1)
+
+		go worker()
+	}
+
+	wg.Wait()
+
+	started := make([]*manifestResult, 0, len(results))
+
+	for _, res := range results {
+		if res != nil {
+			started = append(started, res)
+		}
+	}
+
+	return started
+}
+
+// printManifestSummary prints the results of the collections.
+func printManifestSummary(results []*manifestResult) {
+	width := len("COLLECTION")
+
+	for _, r := range results {
+		if len(r.collection) > width {
+			width = len(r.collection)
+		}
+	}
+
+	util.Stdoutf("%-*s %10s %10s %10s  %s\n", width, "COLLECTION", "INSERTED", "SKIPPED", "FAILED", "STATUS")
+
+	for _, r := range results {
+		status := "ok"
+		if r.err != nil {
+			status = r.err.Error()
+		}
+
+		util.Stdoutf("%-*s %10d %10d %10d  %s\n", width, r.collection, r.inserted, r.skipped, r.failed, status)
+	}
+}
+
+var importManifestCmd = &cobra.Command{
+	Use:   "import-manifest {manifest-file}",
+	Short: "Import documents into multiple collections described by the manifest file",
+	Long: `Imports documents into multiple collections described by the YAML manifest file.
+Every collection is imported the same way as by the import command,
+with the options of the manifest entry, which have the same meaning as the flags of the import command.
+Collections are imported in the order of the manifest, the import stops at the first failed collection.
+With --parallel=N up to N collections are imported concurrently, every collection
+with its own schema state, options and counters of the documents.
+Output of the concurrent imports is interleaved. No more collections are started
+after the first failed one, the collections already started are completed.
+Summary of the import of every collection is printed at the end.
+
+Relative paths of the sources and schema files are relative to the directory of the manifest.
+The project from the manifest is used, unless --project is specified.
+
+Manifest format:
+  project: myproj
+  collections:
+    - collection: users
+      sources: [users.json, https://example.com/users2.json]
+      primary_key: [id]
+      autogenerate: [id]
+      secondary_index: [email]
+      schema_file: users.schema.json
+      append: false
+      no_create_collection: false
+      on_conflict: error
+      csv_delimiter: ","
+      csv_comment: "#"
+      csv_trim_leading_space: true
+      csv_no_header: false
+`,
+	Example: fmt.Sprintf(`
+  %[1]s import-manifest migration.yaml
+`, rootCmd.Root().Name()),
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		util.Fatal(schema.ValidatePrintSchema(), "print schema")
+		util.Fatal(schema.ValidateFieldTypeReport(), "field type report")
+
+		m, err := loadManifest(args[0])
+		util.Fatal(err, "load manifest")
+
+		if m.Project != "" && !cmd.Flags().Changed("project") {
+			config.DefaultConfig.Project = m.Project
+		}
+
+		if ManifestParallel < 1 {
+			util.Fatal(ErrManifestParallel, "parallel")
+		}
+
+		login.Ensure(cmd.Context(), func(_ context.Context) error {
+			results := importEntries(cmd.Context(), config.GetProjectName(), m)
+
+			printManifestSummary(results)
+
+			for _, res := range results {
+				if res.err != nil {
+					return util.Error(res.err, "import collection: %s", res.collection)
+				}
+			}
+
+			return nil
+		})
+	},
+}
+
+func init() {
+	importManifestCmd.Flags().Int32VarP(&iterate.BatchSize, "batch-size", "b", iterate.BatchSize, "set batch size")
+	importManifestCmd.Flags().DurationVar(&iterate.BatchTimeout, "batch-timeout", 0,
+		"Timeout of a single batch request, every batch gets its own deadline. Default: no timeout")
+	importManifestCmd.Flags().Int64Var(&iterate.MaxRequestBytes, "max-request-bytes", 0,
+		"Split batches so that a single request doesn't exceed this size in bytes. "+
+			"Useful with HTTP protocol, where very large requests may fail. Default: no limit")
+	importManifestCmd.Flags().Int64Var(&iterate.MaxDocSize, "max-doc-size", 0,
+		"Reject the documents larger than this size in bytes, reporting their numbers in the input. "+
+			"The documents are skipped with --skip-errors, otherwise the import is aborted. Default: no limit")
+	importManifestCmd.Flags().BoolVar(&iterate.AdaptiveRate, "adaptive-rate", false,
+		"Slow down when the server throttles the requests and speed back up when throttling clears")
+	importManifestCmd.Flags().BoolVar(&CleanUpNULLs, "cleanup-null-values", true,
+		"Remove NULL values and empty arrays from the documents before importing")
+	importManifestCmd.Flags().BoolVar(&iterate.SkipErrors, "skip-errors", false,
+		"Continue import if some documents failed to be inserted. "+
+			"The number of the failed documents by the error class is printed at the end")
+	addPrintSchemaFlag(importManifestCmd)
+	importManifestCmd.Flags().IntVar(&ManifestParallel, "parallel", 1,
+		"Number of the collections imported concurrently")
+
+	addProjectFlag(importManifestCmd)
+	rootCmd.AddCommand(importManifestCmd)
+}
@@ -0,0 +1,175 @@
+// Copyright 2022-2023 Tigris Data, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tigrisdata/tigris-cli/iterate"
+	"github.com/tigrisdata/tigris-cli/util"
+)
+
+func TestManifestCSVTrimLeadingSpace(t *testing.T) {
+	name := filepath.Join(t.TempDir(), "manifest.yaml")
+
+	require.NoError(t, os.WriteFile(name, []byte(`
+collections:
+  - collection: c1
+    sources: [c1.csv]
+  - collection: c2
+    sources: [c2.csv]
+    csv_trim_leading_space: false
+  - collection: c3
+    sources: [c3.csv]
+    csv_trim_leading_space: true
+`), 0o600))
+
+	m, err := loadManifest(name)
+	require.NoError(t, err)
+	require.Len(t, m.Collections, 3)
+
+	assert.Equal(t, filepath.Join(filepath.Dir(name), "c1.csv"), m.Collections[0].Sources[0])
+
+	for k, exp := range []bool{true, false, true} {
+		imp, err := m.Collections[k].importer("db1")
+		require.NoError(t, err)
+		assert.Equal(t, exp, imp.run.CSV.TrimLeadingSpace, m.Collections[k].Collection)
+	}
+}
+
+func TestManifestEntryImporter(t *testing.T) {
+	entries := []manifestEntry{
+		{
+			Collection:   "c1",
+			PrimaryKey:   []string{"id"},
+			OnConflict:   util.OnConflictSkip,
+			CSVDelimiter: "||",
+		},
+		{
+			Collection: "c2",
+			Append:     true,
+		},
+	}
+
+	imps := make([]*importer, 0, len(entries))
+
+	for k := range entries {
+		imp, err := entries[k].importer("db1")
+		require.NoError(t, err)
+
+		imps = append(imps, imp)
+	}
+
+	assert.Equal(t, []string{"id"}, imps[0].primaryKey)
+	assert.Equal(t, util.OnConflictSkip, imps[0].onConflict)
+	assert.False(t, imps[0].append)
+	assert.Equal(t, '\x1f', imps[0].run.CSV.Delimiter)
+
+	assert.Empty(t, imps[1].primaryKey)
+	assert.Equal(t, util.OnConflictError, imps[1].onConflict)
+	assert.True(t, imps[1].append)
+	assert.Equal(t, rune(0), imps[1].run.CSV.Delimiter)
+
+	// every entry counts its documents in its own run, the flags are not changed
+	assert.NotSame(t, imps[0].run, imps[1].run)
+	assert.NotSame(t, iterate.DefaultRun(), imps[0].run)
+	assert.Equal(t, util.OnConflictError, util.OnConflict)
+	assert.Empty(t, PrimaryKey)
+
+	_, err := (&manifestEntry{Collection: "c3", OnConflict: "ignore"}).importer("db1")
+	require.ErrorIs(t, err, util.ErrInvalidOnConflict)
+}
+
+func TestImportEntriesParallel(t *testing.T) {
+	defer func() {
+		importEntryFn = importEntry
+		ManifestParallel = 0
+	}()
+
+	ManifestParallel = 3
+
+	errImport := fmt.Errorf("import failed")
+
+	var (
+		mu             sync.Mutex
+		active, maxAct int
+	)
+
+	// fail is the collection which fails immediately, others take a while
+	fail := ""
+
+	importEntryFn = func(ctx context.Context, db string, e *manifestEntry) *manifestResult {
+		mu.Lock()
+
+		active++
+		if active > maxAct {
+			maxAct = active
+		}
+
+		mu.Unlock()
+
+		defer func() {
+			mu.Lock()
+			active--
+			mu.Unlock()
+		}()
+
+		if e.Collection == fail {
+			return &manifestResult{collection: e.Collection, err: errImport}
+		}
+
+		time.Sleep(20 * time.Millisecond)
+
+		return &manifestResult{collection: e.Collection, inserted: 1}
+	}
+
+	m := &manifest{}
+
+	for i := 0; i < 6; i++ {
+		m.Collections = append(m.Collections, manifestEntry{Collection: fmt.Sprintf("c%d", i)})
+	}
+
+	collections := func(results []*manifestResult) []string {
+		var res []string
+
+		for _, r := range results {
+			res = append(res, r.collection)
+		}
+
+		return res
+	}
+
+	results := importEntries(context.Background(), "db1", m)
+	assert.Equal(t, []string{"c0", "c1", "c2", "c3", "c4", "c5"}, collections(results))
+	assert.LessOrEqual(t, maxAct, ManifestParallel)
+	assert.Greater(t, maxAct, 1)
+
+	// entries after the concurrently running ones are not started after the failure
+	fail = "c0"
+
+	results = importEntries(context.Background(), "db1", m)
+	require.NotEmpty(t, results)
+	require.ErrorIs(t, results[0].err, errImport)
+	assert.Subset(t, []string{"c0", "c1", "c2"}, collections(results))
+	assert.IsIncreasing(t, collections(results))
+}
@@ -15,11 +15,12 @@
 package cmd
 
 import (
+	"bytes"
 	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
-	"unsafe"
+	"time"
 
 	"github.com/rs/zerolog/log"
 	"github.com/spf13/cobra"
@@ -32,15 +33,35 @@ import (
 	api "github.com/tigrisdata/tigris-client-go/api/server/v1"
 	errcode "github.com/tigrisdata/tigris-client-go/code"
 	"github.com/tigrisdata/tigris-client-go/driver"
-	cschema "github.com/tigrisdata/tigris-client-go/schema"
 )
 
+// defaultCSVTrimLeadingSpace is the default of --csv-trim-leading-space
+// and of csv_trim_leading_space of the import manifest.
+const defaultCSVTrimLeadingSpace = true
+
 var (
 	Append         bool
 	NoCreate       bool
 	InferenceDepth int32
 	PrimaryKey     []string
 	AutoGenerate   []string
+	SkipExisting   bool
+	SecondaryIndex []string
+	SchemaFile     string
+
+	// InferFrom is the file with the sample documents, the schema is inferred from,
+	// instead of the imported documents.
+	InferFrom string
+
+	// UpdateSchema continues the inference from the imported documents after InferFrom.
+	UpdateSchema bool
+
+	// SchemaOnCreateOnly infers the schema only when the collection is created by the import,
+	// the schema of the existing or just created collection is not evolved.
+	SchemaOnCreateOnly bool
+
+	// Clipboard imports the documents from the system clipboard.
+	Clipboard bool
 
 	CleanUpNULLs = true
 
@@ -50,41 +71,399 @@ var (
 
 	CSVNoHeader bool
 
-	sch cschema.Schema // Accumulate inferred schema across batches
-
 	ErrCollectionShouldExist = fmt.Errorf("collection should exist to import CSV with no field names")
 	ErrNoAppend              = fmt.Errorf(
 		"collection exists. use --append if you need to add documents to existing collection")
 
 	ErrNoRecordsExpected = fmt.Errorf("no records expected in the collection after fixing numbers")
 
-	FirstRecord = true
+	ErrSkipExistingNoPrimaryKey = fmt.Errorf("--on-conflict=skip requires --primary-key for the new collection")
+	ErrTTLExistingCollection    = fmt.Errorf("--ttl is ignored, as it's applied only when the collection is created")
+
+	ErrDescriptionExistingCollection = fmt.Errorf(
+		"--collection-description is ignored, as it's applied only when the collection is created")
+	ErrDescriptionNotRetained = fmt.Errorf("the server didn't retain the description of the collection")
+
+	ErrInferFromSchemaFile = fmt.Errorf("--infer-from can't be used with --schema-file")
+	ErrEmptySample         = fmt.Errorf("no documents in the sample file")
+
+	ErrClipboardWithDocs = fmt.Errorf("--clipboard can't be used with the documents in the command line")
+	ErrClipboardEmpty    = fmt.Errorf("clipboard is empty")
+
+	ErrUpdateSchemaOnCreate = fmt.Errorf("--update-schema can't be used with --schema-on-create-only")
 )
 
-func evolveSchema(ctx context.Context, db string, coll string, docs []json.RawMessage) error {
+// importOptions are the options of the import of the collection,
+// set by the flags of the command or by the entry of the import manifest.
+type importOptions struct {
+	primaryKey     []string
+	autoGenerate   []string
+	secondaryIndex []string
+	schemaFile     string
+	append         bool
+	noCreate       bool
+	onConflict     string
+	csvNoHeader    bool
+}
+
+// flagImportOptions returns the import options set by the flags.
+func flagImportOptions() importOptions {
+	return importOptions{
+		primaryKey:     PrimaryKey,
+		autoGenerate:   AutoGenerate,
+		secondaryIndex: SecondaryIndex,
+		schemaFile:     SchemaFile,
+		append:         Append,
+		noCreate:       NoCreate,
+		onConflict:     util.OnConflict,
+		csvNoHeader:    CSVNoHeader,
+	}
+}
+
+// importer holds the state of a single collection import run.
+type importer struct {
+	importOptions
+
+	db   string
+	coll string
+
+	// run counts the documents of the input, failed ones included,
+	// and holds the options of the CSV input
+	run *iterate.Run
+
+	sch          *schema.Accumulator // Accumulate inferred schema across batches
+	prevSchema   []byte              // Last schema sent to the server
+	serverSchema []byte              // Schema of the existing collection
+
+	firstRecord bool
+	fixedSchema bool // Schema is provided by --schema-file, inference is disabled
+	creating    bool // The collection is created by the import, collection creation options apply
+
+	inserted        int64
+	skippedExisting int64
+}
+
+// newImporter returns the importer with the options set by the flags,
+// counting the documents in the default run of the input.
+func newImporter(db string, coll string) *importer {
+	return trackImporter(&importer{
+		importOptions: flagImportOptions(),
+		db:            db,
+		coll:          coll,
+		run:           iterate.DefaultRun(),
+		sch:           schema.NewAccumulator(),
+		firstRecord:   true,
+	})
+}
+
+// cacheKey identifies the schema of the collection in the schema cache.
+func (imp *importer) cacheKey() []string {
+	return []string{"collection", config.DefaultConfig.URL, imp.db, imp.coll}
+}
+
+// loadSchema initializes the importer with the schema of existing collection.
+// The schema is taken from the schema cache, when it's enabled.
+// Returns false if the collection doesn't exist.
+func (imp *importer) loadSchema(ctx context.Context) bool {
+	if b := schema.CacheGet(imp.cacheKey()...); b != nil {
+		err := imp.sch.Load(b)
+		util.Fatal(err, "unmarshal cached collection schema")
+
+		imp.serverSchema = b
+
+		return true
+	}
+
+	resp, err := client.Get().UseDatabase(imp.db).DescribeCollection(ctx, imp.coll)
+	if err != nil {
+		return false
+	}
+
+	err = imp.sch.Load(resp.Schema)
+	util.Fatal(err, "unmarshal collection schema")
+
+	imp.serverSchema = resp.Schema
+
+	schema.CachePut(resp.Schema, imp.cacheKey()...)
+
+	return true
+}
+
+func (imp *importer) evolveSchema(ctx context.Context, docs []json.RawMessage) error {
 	// Allow to reduce inference depth in the case of huge batches
 	id := len(docs)
 	if InferenceDepth > 0 {
 		id = int(InferenceDepth)
 	}
 
-	err := schema.Infer(&sch, coll, docs, PrimaryKey, AutoGenerate, id)
+	b, err := imp.sch.Infer(imp.coll, docs, imp.primaryKey, imp.autoGenerate, id)
 	util.Fatal(err, "infer schema")
 
-	b, err := json.Marshal(sch)
-	util.Fatal(err, "marshal schema: %s", string(b))
+	if b, err = imp.withCreateOptions(b); err != nil {
+		return util.Error(err, "collection creation options")
+	}
+
+	if bytes.Equal(b, imp.prevSchema) {
+		log.Debug().Msg("schema is not changed, skipping collection update")
+		return nil
+	}
+
+	if err = imp.checkCompat(b); err != nil {
+		return err
+	}
+
+	err = client.Get().UseDatabase(imp.db).CreateOrUpdateCollection(ctx, imp.coll, b)
+	if err != nil {
+		schema.CacheInvalidate(imp.cacheKey()...)
+		return util.Error(err, "create or update collection")
+	}
+
+	schema.CachePut(b, imp.cacheKey()...)
+
+	if err = imp.checkCreated(ctx); err != nil {
+		return err
+	}
+
+	imp.prevSchema = b
+
+	// the schema of the collection, created by the first batch, is not evolved anymore
+	if SchemaOnCreateOnly {
+		imp.fixedSchema = true
+	}
+
+	return util.Error(schema.PrintChanged(b), "print schema")
+}
+
+// checkCompat checks the compatibility of the schema change
+// against the last schema sent to the server or the schema of the existing collection.
+func (imp *importer) checkCompat(b []byte) error {
+	base := imp.prevSchema
+	if base == nil {
+		base = imp.serverSchema
+	}
+
+	return util.Error(schema.CheckCompat(imp.coll, base, b), "schema compatibility")
+}
+
+// withCreateOptions sets the TTL policy and the description
+// in the schema of the collection created by the import.
+func (imp *importer) withCreateOptions(b []byte) ([]byte, error) {
+	if !imp.creating {
+		return b, nil
+	}
+
+	b, err := schema.WithTTL(b)
+	if err != nil {
+		return nil, err
+	}
+
+	return schema.WithDescription(b)
+}
+
+// checkCreated checks that the server retained the description of the collection
+// just created by the import, as servers may drop unsupported schema metadata.
+func (imp *importer) checkCreated(ctx context.Context) error {
+	if !imp.creating || imp.prevSchema != nil || schema.CollectionDescription == "" {
+		return nil
+	}
+
+	resp, err := client.Get().UseDatabase(imp.db).DescribeCollection(ctx, imp.coll)
+	if err != nil {
+		return util.Error(err, "describe collection")
+	}
+
+	if d, err := schema.Description(resp.Schema); err != nil || d != schema.CollectionDescription {
+		return util.Warning(ErrDescriptionNotRetained)
+	}
+
+	return nil
+}
+
+// createFromFile creates or updates the collection with the schema from the file
+// and disables inference.
+func (imp *importer) createFromFile(ctx context.Context, name string) error {
+	b, err := schema.ReadFile(name, imp.coll)
+	if err != nil {
+		return util.Error(err, "read schema file: %s", name)
+	}
+
+	return imp.createWithSchema(ctx, b, "schema file: "+name)
+}
+
+// createWithSchema creates or updates the collection with the given schema
+// and disables inference. Source describes the origin of the schema in the errors.
+func (imp *importer) createWithSchema(ctx context.Context, b []byte, source string) error {
+	b, err := imp.withCreateOptions(b)
+	if err != nil {
+		return util.Error(err, "collection creation options")
+	}
+
+	if err = imp.checkCompat(b); err != nil {
+		return err
+	}
+
+	err = client.Get().UseDatabase(imp.db).CreateOrUpdateCollection(ctx, imp.coll, b)
+	if err != nil {
+		schema.CacheInvalidate(imp.cacheKey()...)
+		return util.Error(err, "create or update collection from %s", source)
+	}
+
+	schema.CachePut(b, imp.cacheKey()...)
+
+	if err = imp.checkCreated(ctx); err != nil {
+		return err
+	}
+
+	if schema.CoerceValues {
+		err = imp.sch.Load(b)
+		util.Fatal(err, "unmarshal %s", source)
+	}
+
+	imp.prevSchema = b
+	imp.fixedSchema = true
+
+	return util.Error(schema.PrintChanged(b), "print schema")
+}
+
+// inferFromSample creates or updates the collection with the schema inferred
+// from the documents of the sample file. Inference from the imported documents
+// is disabled, unless UpdateSchema is set.
+func (imp *importer) inferFromSample(ctx context.Context, name string) error {
+	var b []byte
+
+	err := iterate.SourceInput(ctx, nil, name, func(_ context.Context, _ []string, docs []json.RawMessage) error {
+		id := len(docs)
+		if InferenceDepth > 0 {
+			id = int(InferenceDepth)
+		}
+
+		var err error
+
+		b, err = imp.sch.Infer(imp.coll, docs, imp.primaryKey, imp.autoGenerate, id)
+
+		return util.Error(err, "infer schema from sample file: %s", name)
+	})
+	if err != nil {
+		return err
+	}
+
+	if b == nil {
+		return util.Error(ErrEmptySample, "infer schema from sample file: %s", name)
+	}
+
+	if err = imp.createWithSchema(ctx, b, "sample file: "+name); err != nil {
+		return err
+	}
+
+	imp.fixedSchema = !UpdateSchema
+
+	return nil
+}
+
+// checkInferFrom validates the options of the inference from the sample file.
+func checkInferFrom() error {
+	if InferFrom != "" && SchemaFile != "" {
+		return ErrInferFromSchemaFile
+	}
+
+	if UpdateSchema && SchemaOnCreateOnly {
+		return ErrUpdateSchemaOnCreate
+	}
+
+	return nil
+}
+
+// openImporter creates the importer for the collection with the options set by the flags
+// and checks the import options against the existence of the collection.
+func openImporter(ctx context.Context, db string, coll string) (*importer, error) {
+	imp := newImporter(db, coll)
+
+	if err := imp.open(ctx); err != nil {
+		return nil, err
+	}
+
+	return imp, nil
+}
+
+// open checks the import options against the existence of the collection
+// and creates the collection from the schema file or the sample file, if requested.
+func (imp *importer) open(ctx context.Context) error {
+	if Schemaless {
+		imp.fixedSchema = true
+		return nil
+	}
+
+	found := imp.loadSchema(ctx)
+
+	if found {
+		if !imp.append {
+			return util.Error(ErrNoAppend, "describe collection")
+		}
+
+		// the schema of the existing collection is used as is
+		imp.fixedSchema = SchemaOnCreateOnly
+	} else if imp.csvNoHeader && imp.schemaFile == "" {
+		return util.Error(ErrCollectionShouldExist, "describe collection")
+	} else if imp.onConflict == util.OnConflictSkip && len(imp.primaryKey) == 0 && imp.schemaFile == "" {
+		return util.Error(ErrSkipExistingNoPrimaryKey, "skip existing")
+	} else {
+		imp.sch.SetSecondaryIndex(imp.secondaryIndex)
+
+		imp.creating = true
+	}
+
+	if found && schema.TTLField != "" {
+		if err := util.Warning(ErrTTLExistingCollection); err != nil {
+			return err
+		}
+	}
+
+	if found && schema.CollectionDescription != "" {
+		if err := util.Warning(ErrDescriptionExistingCollection); err != nil {
+			return err
+		}
+	}
+
+	if imp.schemaFile != "" && !imp.fixedSchema && (found || !imp.noCreate) {
+		if err := imp.createFromFile(ctx, imp.schemaFile); err != nil {
+			return err
+		}
+	}
 
-	err = client.Get().UseDatabase(db).CreateOrUpdateCollection(ctx, coll, b)
+	if InferFrom != "" && !imp.fixedSchema && (found || !imp.noCreate) {
+		if err := imp.inferFromSample(ctx, InferFrom); err != nil {
+			return err
+		}
+	}
 
-	return util.Error(err, "create or update collection")
+	return nil
 }
 
-func writeInitRecord(ctx context.Context, coll string, docs []json.RawMessage) {
-	if !FirstRecord {
+// finish reports the results of the import.
+func (imp *importer) finish() error {
+	if err := schema.PrintFinal(imp.prevSchema); err != nil {
+		return util.Error(err, "print schema")
+	}
+
+	if err := schema.PrintFieldTypes(imp.prevSchema); err != nil {
+		return util.Error(err, "print field types")
+	}
+
+	if imp.onConflict == util.OnConflictSkip {
+		util.Infof("Inserted %d document(s), skipped %d existing document(s)", imp.inserted, imp.skippedExisting)
+	}
+
+	return nil
+}
+
+func (imp *importer) writeInitRecord(ctx context.Context, docs []json.RawMessage) {
+	if !imp.firstRecord {
 		return
 	}
 
-	cnt, err := client.GetDB().Count(ctx, coll, driver.Filter("{}"))
+	coll := imp.coll
+
+	cnt, err := client.Get().UseDatabase(imp.db).Count(ctx, coll, driver.Filter("{}"))
 	if err != nil {
 		var ep *driver.Error
 		if errors.As(err, &ep) && ep.Code == api.Code_NOT_FOUND {
@@ -98,17 +477,17 @@ func writeInitRecord(ctx context.Context, coll string, docs []json.RawMessage) {
 	if cnt != 0 {
 		log.Debug().Msg("collection is not empty, skipping init record")
 
-		FirstRecord = false
+		imp.firstRecord = false
 
 		return
 	}
 
-	initDoc, err := schema.GenerateInitDoc(&sch, docs[0])
+	initDoc, err := imp.sch.GenerateInitDoc(docs[0])
 	log.Debug().Interface("initDoc", string(initDoc)).Msg("generating init record")
 
 	util.Fatal(err, "init record generation")
 
-	err = client.Transact(ctx, config.GetProjectName(), func(ctx context.Context, tx driver.Tx) error {
+	err = client.Transact(ctx, imp.db, func(ctx context.Context, tx driver.Tx) error {
 		_, err = tx.Insert(ctx, coll, []driver.Document{initDoc})
 		util.Fatal(err, "insert init record")
 
@@ -119,17 +498,109 @@ func writeInitRecord(ctx context.Context, coll string, docs []json.RawMessage) {
 	})
 	util.Fatal(err, "init record transaction")
 
-	FirstRecord = false
+	imp.firstRecord = false
 }
 
-func insertWithInference(ctx context.Context, coll string, docs []json.RawMessage) error {
-	// FIXME: This is temporary fix, should moved to server ASAP
-	writeInitRecord(ctx, coll, docs)
+func isErrorCode(err error, code api.Code) bool {
+	// FIXME: errors.As(err, &ep) doesn't work
+	//nolint:golint,errorlint
+	ep, ok := err.(*driver.Error)
 
-	ptr := unsafe.Pointer(&docs)
+	return ok && ep.Code == code
+}
+
+// insert inserts the batch of documents according to the conflict policy.
+// With util.OnConflictReplace, existing documents are replaced.
+// With util.OnConflictSkip, batch containing existing documents is retried
+// document by document, skipping the ones which already exist.
+// The values of the byte fields are converted from --byte-encoding before insert.
+func (imp *importer) insert(ctx context.Context, db driver.Database, docs []json.RawMessage) error {
+	docs, err := imp.sch.EncodeBytes(docs)
+	if err != nil {
+		return util.Error(err, "encode byte fields")
+	}
+
+	if imp.onConflict == util.OnConflictReplace {
+		_, err = db.Replace(ctx, imp.coll, client.Documents(docs))
+	} else {
+		_, err = db.Insert(ctx, imp.coll, client.Documents(docs))
+	}
 
-	_, err := client.GetDB().Insert(ctx, coll, *(*[]driver.Document)(ptr))
 	if err == nil {
+		imp.inserted += int64(len(docs))
+		util.Fatal(iterate.TeeDocs(docs), "tee")
+
+		return nil
+	}
+
+	if imp.onConflict != util.OnConflictSkip || !isErrorCode(err, api.Code_ALREADY_EXISTS) {
+		return err
+	}
+
+	log.Debug().Msg("batch contains existing documents, inserting one by one")
+
+	for _, doc := range docs {
+		if _, err = db.Insert(ctx, imp.coll, []driver.Document{driver.Document(doc)}); err != nil {
+			if !isErrorCode(err, api.Code_ALREADY_EXISTS) {
+				return err
+			}
+
+			imp.skippedExisting++
+
+			continue
+		}
+
+		imp.inserted++
+		util.Fatal(iterate.TeeDocs([]json.RawMessage{doc}), "tee")
+	}
+
+	return nil
+}
+
+// insertFixed inserts the documents into the collection with the schema provided by the user.
+func (imp *importer) insertFixed(ctx context.Context, docs []json.RawMessage) error {
+	db := client.Get().UseDatabase(imp.db)
+
+	err := imp.insert(ctx, db, docs)
+	if err == nil || !CleanUpNULLs {
+		return util.Error(err, "import documents")
+	}
+
+	for k := range docs {
+		docs[k] = util.CleanupNULLValues(docs[k])
+	}
+
+	return util.Error(imp.insert(ctx, db, docs), "import documents")
+}
+
+// coerceDocs converts the values of the documents to the types of the known fields of the collection.
+func (imp *importer) coerceDocs(docs []json.RawMessage) ([]json.RawMessage, error) {
+	var skip func(json.RawMessage, error) error
+	if iterate.SkipErrors {
+		skip = imp.run.SkipDoc
+	}
+
+	docs, err := imp.sch.CoerceDocs(docs, skip)
+
+	return docs, util.Error(err, "coerce values")
+}
+
+func (imp *importer) insertWithInference(ctx context.Context, docs []json.RawMessage) error {
+	docs, err := imp.coerceDocs(docs)
+	if err != nil || len(docs) == 0 {
+		return err
+	}
+
+	if imp.fixedSchema {
+		return imp.insertFixed(ctx, docs)
+	}
+
+	// FIXME: This is temporary fix, should moved to server ASAP
+	imp.writeInitRecord(ctx, docs)
+
+	db := client.Get().UseDatabase(imp.db)
+
+	if err = imp.insert(ctx, db, docs); err == nil {
 		return nil // successfully inserted batch
 	}
 
@@ -137,17 +608,16 @@ func insertWithInference(ctx context.Context, coll string, docs []json.RawMessag
 	//nolint:golint,errorlint
 	ep, ok := err.(*driver.Error)
 	if !ok || (ep.Code != api.Code_NOT_FOUND && ep.Code != errcode.InvalidArgument) ||
-		ep.Code == api.Code_NOT_FOUND && NoCreate {
+		ep.Code == api.Code_NOT_FOUND && imp.noCreate {
 		return util.Error(err, "import documents (initial)")
 	}
 
-	if err = evolveSchema(ctx, config.GetProjectName(), coll, docs); err != nil {
+	if err = imp.evolveSchema(ctx, docs); err != nil {
 		return err
 	}
 
 	// retry after schema update
-	_, err = client.GetDB().Insert(ctx, coll, *(*[]driver.Document)(ptr))
-	if err == nil {
+	if err = imp.insert(ctx, db, docs); err == nil {
 		return nil
 	}
 
@@ -157,7 +627,7 @@ func insertWithInference(ctx context.Context, coll string, docs []json.RawMessag
 		}
 	}
 
-	_, err = client.GetDB().Insert(ctx, coll, *(*[]driver.Document)(ptr))
+	err = imp.insert(ctx, db, docs)
 
 	log.Debug().Interface("docs", docs).Msg("import")
 
@@ -169,11 +639,152 @@ var importCmd = &cobra.Command{
 	Short: "Import documents into collection",
 	Long: `Imports documents into the collection.
 Input is a stream or array of JSON documents to import.
+Documents of the stream are separated by newlines, other whitespace or nothing at all, like {...}{...}.
+Documents passed as arguments can be mixed with "-", which stands for the standard input,
+and with http://, https:// and gs:// URLs, documents are imported in the order of the sources
+in the command line. Gzip compressed gs:// sources are decompressed automatically.
+With --clipboard the JSON documents are taken from the system clipboard,
+for quick ad-hoc imports during development.
+The standard input and named pipes (FIFOs) are read as unbounded streams:
+the documents are imported as they arrive, without buffering the whole input,
+and the progress is reported as the number of the documents imported so far.
+
+The format of the input, JSON or CSV, is detected automatically.
+MessagePack stream of maps is imported with --format=msgpack.
+Top-level object, mapping the keys to the documents, like {"id1": {...}, "id2": {...}},
+is imported with --object-map-key=id, the key is set to the id field of the document.
+
+With --where only the documents matching the predicate are imported,
+and the schema is inferred from the matching documents only.
+The predicate compares the fields of the document with the values:
+  * operators: ==, !=, <, <=, >, >=, && (and), || (or), ! (not) and parentheses
+  * fields: name or dot separated path of the nested field, absent field is null
+  * values: "double-quoted strings", numbers, true, false and null
+Field alone matches when it's true. Ordering of the values of different types is false.
+For example: --where 'status == "active" && (age >= 18 || admin)'
+
+Incremental import:
+  With --watermark-field and --state-file the maximum value of the field, imported so far,
+  is persisted in the state file, and the subsequent runs import only the documents
+  with the greater value of the field. The values are assumed to grow monotonically
+  in the order of the source, like an auto-increment id or a modification timestamp:
+  the watermark advances after every successfully imported batch, so the documents
+  with the smaller value coming later in the source are skipped by the next run.
+  Numbers are compared numerically and strings lexicographically, so the timestamps
+  should be in the same format and time zone, like RFC3339 UTC.
+  The documents without the field are always imported.
+
+Google Cloud Storage sources use application default credentials,
+set up by 'gcloud auth application-default login' or GOOGLE_APPLICATION_CREDENTIALS.
 
 Automatically:
   * Detect the schema of the documents
   * Create collection with inferred schema
   * Evolve the schema as soon as it's backward compatible
+
+Schema compatibility:
+  With --require-compat=backward|forward|full the change of the schema of the existing
+  collection, evolved by the inference, --schema-file or --infer-from, is classified
+  before it's applied, and the import fails when the change is not compatible as required:
+  * full - adding an optional field
+  * backward - the new schema reads the old data: widening the type, like integer
+    to number or date-time to string, removing the field or making it optional
+  * forward - the old schema reads the new data: narrowing the type,
+    adding the required field or making the field required
+  * breaking - any other type change, changing the primary key,
+    or the changes of both backward and forward classes
+  The classification is reported with the changes of the fields.
+
+Field type report:
+  When the import is finished the fields of the final schema and their types
+  are listed in a compact table on stderr. The table is printed only when stderr
+  is a terminal and --quiet is not set. --field-type-report=json prints the report
+  as a JSON array for machine consumption, --field-type-report=none disables it.
+
+With --schema-on-create-only the schema is inferred from the first batch only to create
+the collection, and it's not evolved by the subsequent batches, so the schema is predictable
+and stable. The schema of the existing collection is not updated. Documents with the fields
+not matching the schema are rejected by the server.
+
+Collection creation options, applied only when the collection is created by the import:
+  * --primary-key, --autogenerate
+  * --secondary-index - fields to build secondary index on
+  * --collection-description - human-readable description of the collection, stored in the schema.
+    It's checked that the server returns the description after the collection is created.
+  * --ttl, --ttl-field - expire the documents after --ttl counted from the date-time --ttl-field.
+    Requires server with TTL support, servers without it reject the schema.
+    The policy is kept when the schema of the new collection evolves during the import.
+    The options are ignored with a warning when importing into existing collection.
+
+With --coerce-values the values of the documents are converted to the types of the fields
+of the existing collection, the --schema-file or the schema inferred from the previous documents,
+like "42" to 42 for the integer field, "true" to true for the boolean field
+and numbers and booleans to strings for the string field. Documents with the values,
+which can't be converted, fail the import, or skipped with --skip-errors.
+
+With --schema-cache the schema of the existing collection is cached on disk
+for --schema-cache-ttl, so the scripts running many small imports skip
+describing the collection on every run. The cached schema is replaced
+when the import evolves the schema of the collection.
+
+Before reading the input, the connection to the server, the authentication
+and the existence of the project are checked, to fail fast. Use --no-preflight to skip the check.
+
+With --report-file the complete report of the run: CLI version, flags, counts,
+timings of the batches, errors and the final schema, is written as JSON to the file
+on completion or failure.
+
+With --webhook the summary of the import: number of documents read, inserted, skipped
+and failed, duration and the error, if any, is posted as JSON to the URL on completion.
+
+Number of shards is managed by the server and can't be configured.
+
+Documents with primary key which already exists in the collection are handled
+according to --on-conflict:
+  * error - fail the import (default)
+  * skip - leave existing documents untouched, requires --primary-key for the new collection
+  * replace - replace existing documents with the imported ones
+
+With --partition-by the documents are routed to the collections named by the value
+of the field, prefixed by --partition-prefix. The collection argument is omitted in this case.
+Every collection gets its own inferred schema. The field should be non-empty string or number.
+A high cardinality field would create a collection per document,
+so the number of collections is limited by --max-partitions.
+
+When the exact schema is known, it can be provided with --schema-file.
+In this case schema inference is disabled and the documents are validated by the server.
+
+With --infer-from=sample.jsonl the schema is inferred from the representative sample file
+and the collection is created or updated with it before the import, so the schema
+doesn't depend on the documents which happen to come first in the imported data.
+The imported documents are not inferred from, unless --update-schema is set.
+
+Schemaless collections:
+  With --schemaless the schema inference and the collection creation are skipped entirely,
+  the documents are inserted as is, into existing collection. --append is implied.
+  Use it with the servers which support schemaless collections, or when the schema
+  of the collection is managed elsewhere. Tradeoffs: the documents are not checked
+  against the inferred schema, so type mismatches are only reported by the server,
+  and the fields are not narrowed to date-time, UUID or byte types.
+  Schema related options, like --primary-key or --detect-times, are ignored with a warning.
+
+Field-level encryption:
+  Values of the fields listed by --encrypt-field are encrypted on the client before insert,
+  the server stores only the ciphertext. The key is 32 bytes, hex or base64 encoded,
+  provided by --encryption-key, --encryption-key-file or TIGRIS_ENCRYPTION_KEY environment
+  variable, in this order of precedence. Encrypted value is a string:
+    enc:v1:<base64 of 12 bytes nonce followed by AES-256-GCM sealed JSON of the value>
+  With --encrypt-mode=random (default) equal values have different ciphertext.
+  With --encrypt-mode=deterministic the nonce is derived from HMAC-SHA256 of the value,
+  so equal values can be matched by equality filters, at the cost of revealing
+  which documents have equal values. Absent and null fields are not encrypted.
+  Encrypted fields are inferred as strings. Use 'read --decrypt-field' to decrypt.
+
+Fields order:
+  Documents modified by the CLI, for example by --cleanup-null-values or --add-field,
+  keep the original order of the fields with --preserve-order. Added fields go last.
+  Fields of CSV documents follow the order of the header.
+  The order of the fields of the documents stored and returned by the server is not guaranteed.
 `,
 	Example: fmt.Sprintf(`
   %[1]s import --project=myproj users --primary-key=id \
@@ -181,63 +792,366 @@ Automatically:
     {"id": 20, "name": "Jania McGrory"},
     {"id": 21, "name": "Bunny Instone"}
   ]'
+
+  # Stamp the documents with the import time
+  %[1]s import --project=myproj users --add-field='imported_at=now()' <users.json
+
+  # Prepend a document to the documents read from the standard input
+  %[1]s import --project=myproj users '{"id": 1, "name": "Admin"}' - <users.json
+
+  # Import into orders_books, orders_music, ... collections by the category field
+  %[1]s import --project=myproj --partition-by=category --partition-prefix=orders_ <orders.json
+
+  # Encrypt the email field of the documents with the key from the file
+  %[1]s import --project=myproj users --encrypt-field=email --encryption-key-file=key.txt <users.json
+
+  # Import only the active users
+  %[1]s import --project=myproj users --where 'status == "active"' <users.json
+
+  # Import from Google Cloud Storage
+  %[1]s import --project=myproj users gs://my-bucket/users.json.gz
 `, rootCmd.Root().Name()),
-	Args: cobra.MinimumNArgs(1),
+	Args: importArgs,
 	Run: func(cmd *cobra.Command, args []string) {
+		util.Fatal(schema.ValidatePrintSchema(), "print schema")
+		util.Fatal(schema.ValidateFieldTypeReport(), "field type report")
+		util.Fatal(schema.OpenInferenceLog(), "open inference log")
+		util.Fatal(checkOnConflict(), "on conflict")
+		util.Fatal(checkSchemaless(cmd), "schemaless")
+		util.Fatal(checkInferFrom(), "infer from")
+		util.Fatal(schema.ValidateTTL(), "ttl")
+		util.Fatal(iterate.ValidateFormat(), "input format")
+		util.Fatal(iterate.ValidateInputBufferSize(), "input buffer size")
+		util.Fatal(schema.ValidateEnums(), "enums")
+		util.Fatal(util.CheckOptimize(), "optimize")
+		util.Fatal(util.CheckShardKey(), "shard key")
+		util.Fatal(schema.ValidateRequireCompat(), "require compat")
+		util.Fatal(schema.ValidateByteEncoding(), "byte encoding")
+
+		schema.DateTimeFields = iterate.TimestampFields()
+
+		enableReport()
+
 		login.Ensure(cmd.Context(), func(ctx context.Context) error {
-			resp, err := client.GetDB().DescribeCollection(ctx, args[0])
-			if err == nil {
-				if !Append {
-					util.Fatal(ErrNoAppend, "describe collection")
-				}
-				err = json.Unmarshal(resp.Schema, &sch)
-				util.Fatal(err, "unmarshal collection schema")
-			} else if CSVNoHeader {
-				util.Fatal(ErrCollectionShouldExist, "describe collection")
+			start := time.Now()
+
+			finish := func(imps []*importer, err error) {
+				notifyWebhook("import", imps, start, err)
+				writeReport(cmd, imps, start, err)
 			}
 
-			err = iterate.CSVConfigure(CSVDelimiter, CSVComment, CSVTrimLeadingSpace, CSVNoHeader)
-			util.Fatal(err, "csv configure")
+			cancel := onImportExit(finish)
+
+			imps, err := runImport(ctx, cmd, args)
+
+			cancel()
+			finish(imps, err)
 
-			return iterate.Input(cmd.Context(), cmd, 1, args,
-				func(ctx context.Context, args []string, docs []json.RawMessage) error {
-					return insertWithInference(ctx, args[0], docs)
-				})
+			return err
 		})
 	},
 }
 
+// clipboardArgs appends the content of the clipboard to the arguments,
+// as the documents passed in the command line.
+func clipboardArgs(ctx context.Context, args []string) ([]string, error) {
+	docsPosition := 1
+	if PartitionBy != "" {
+		docsPosition = 0
+	}
+
+	if len(args) > docsPosition {
+		return nil, ErrClipboardWithDocs
+	}
+
+	b, err := util.ReadClipboard(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(bytes.TrimSpace(b)) == 0 {
+		return nil, ErrClipboardEmpty
+	}
+
+	return append(args, string(b)), nil
+}
+
+// runImport imports the documents and returns the importers of the collections,
+// which have been imported into, even if the import failed.
+func runImport(ctx context.Context, cmd *cobra.Command, args []string) ([]*importer, error) {
+	if err := preflight(ctx, config.GetProjectName()); err != nil {
+		return nil, util.Error(err, "preflight")
+	}
+
+	err := iterate.CSVConfigure(CSVDelimiter, CSVComment, CSVTrimLeadingSpace, CSVNoHeader)
+	util.Fatal(err, "csv configure")
+
+	if Clipboard {
+		if args, err = clipboardArgs(ctx, args); err != nil {
+			return nil, util.Error(err, "read clipboard")
+		}
+	}
+
+	if PartitionBy != "" {
+		return importPartitioned(cmd, args)
+	}
+
+	imp, err := openImporter(ctx, config.GetProjectName(), args[0])
+	if err != nil {
+		return nil, err
+	}
+
+	err = iterate.Input(cmd.Context(), cmd, 1, args,
+		func(ctx context.Context, args []string, docs []json.RawMessage) error {
+			return imp.insertWithInference(ctx, docs)
+		})
+	if err == nil {
+		err = imp.finish()
+	}
+
+	return []*importer{imp}, err
+}
+
+// addOnConflictFlag adds --on-conflict flag and deprecated --skip-existing alias of --on-conflict=skip.
+func addOnConflictFlag(cmd *cobra.Command) {
+	cmd.Flags().StringVar(&util.OnConflict, "on-conflict", util.OnConflictError,
+		"What to do with documents with primary key which already exists in the collection: "+
+			"error - fail the import, skip - leave existing documents untouched, replace - replace existing documents")
+	cmd.Flags().BoolVar(&SkipExisting, "skip-existing", false,
+		"Skip documents with primary key which already exists in the collection, leaving existing documents untouched")
+	_ = cmd.Flags().MarkDeprecated("skip-existing", "use --on-conflict=skip")
+}
+
+// checkOnConflict applies deprecated --skip-existing and validates --on-conflict.
+func checkOnConflict() error {
+	if SkipExisting {
+		util.OnConflict = util.OnConflictSkip
+	}
+
+	return util.ValidateOnConflict()
+}
+
+func addPrintSchemaFlag(cmd *cobra.Command) {
+	cmd.Flags().StringVar(&schema.PrintSchema, "print-schema", "",
+		"Print the inferred schema to stderr: each - on every schema change, final - when the import is finished. "+
+			"--print-schema is equivalent to --print-schema=each")
+	cmd.Flags().Lookup("print-schema").NoOptDefVal = schema.PrintSchemaEach
+	addFieldTypeReportFlag(cmd)
+}
+
+func addFieldTypeReportFlag(cmd *cobra.Command) {
+	cmd.Flags().StringVar(&schema.FieldTypeReport, "field-type-report", "",
+		"Print the fields of the final schema and their types to stderr: table, json, none. "+
+			"By default the table is printed when stderr is a terminal and --quiet is not set")
+	cmd.Flags().Lookup("field-type-report").NoOptDefVal = schema.FieldTypeReportTable
+	cmd.Flags().BoolVarP(&util.Quiet, "quiet", "q", false,
+		"Suppress informational messages")
+}
+
 func init() {
 	importCmd.Flags().Int32VarP(&iterate.BatchSize, "batch-size", "b", iterate.BatchSize, "set batch size")
+	importCmd.Flags().DurationVar(&iterate.BatchTimeout, "batch-timeout", 0,
+		"Timeout of a single batch request, every batch gets its own deadline. Default: no timeout")
+	importCmd.Flags().DurationVar(&iterate.FlushInterval, "flush-interval", 0,
+		"Flush partially filled batch after the interval, so the documents of slow streams are processed without waiting for the batch to fill up")
+	importCmd.Flags().Int64Var(&iterate.MaxRequestBytes, "max-request-bytes", 0,
+		"Split batches so that a single request doesn't exceed this size in bytes. "+
+			"Useful with HTTP protocol, where very large requests may fail. Default: no limit")
+	importCmd.Flags().Int64Var(&iterate.MaxDocSize, "max-doc-size", 0,
+		"Reject the documents larger than this size in bytes, reporting their numbers in the input. "+
+			"The documents are skipped with --skip-errors, otherwise the import is aborted. Default: no limit")
+	importCmd.Flags().BoolVar(&iterate.AdaptiveRate, "adaptive-rate", false,
+		"Slow down when the server throttles the requests and speed back up when throttling clears")
 	importCmd.Flags().BoolVarP(&Append, "append", "a", false,
 		"Force append to existing collection")
+	addOnConflictFlag(importCmd)
+	addWebhookFlags(importCmd)
+	addReportFileFlag(importCmd)
+	importCmd.Flags().BoolVar(&schema.Cache, "schema-cache", false,
+		"Cache the schema of the existing collection on disk, to skip describing it on the repeated imports")
+	importCmd.Flags().DurationVar(&schema.CacheTTL, "schema-cache-ttl", schema.CacheTTL,
+		"Time the cached schema is used before it's fetched from the server again")
+	importCmd.Flags().BoolVar(&NoPreflight, "no-preflight", false,
+		"Skip checking the connection, the authentication and the existence of the project before reading the input")
 	importCmd.Flags().BoolVar(&NoCreate, "no-create-collection", false,
 		"Do not create collection automatically if it doesn't exist")
+	importCmd.Flags().BoolVar(&Schemaless, "schemaless", false,
+		"Skip schema inference and collection creation, insert the documents as is into existing collection")
 	importCmd.Flags().Int32VarP(&InferenceDepth, "inference-depth", "d", 0,
 		"Number of records in the beginning of the stream to detect field types. It's equal to batch size if not set")
 	importCmd.Flags().StringSliceVar(&PrimaryKey, "primary-key", []string{},
 		"Comma separated list of field names which constitutes collection's primary key (only top level keys supported)")
 	importCmd.Flags().StringSliceVar(&AutoGenerate, "autogenerate", []string{},
 		"Comma separated list of autogenerated fields (only top level keys supported)")
+	importCmd.Flags().StringVar(&SchemaFile, "schema-file", "",
+		"Create or update the collection with the schema from the file and disable schema inference")
+	importCmd.Flags().StringVar(&schema.RequireCompat, "require-compat", "",
+		"Refuse to apply the evolved schema, which is not compatible in the direction: backward, forward or full. "+
+			"The classification of the schema change is reported")
+	importCmd.Flags().StringVar(&InferFrom, "infer-from", "",
+		"Infer the schema from the documents of the sample file, instead of the imported documents, "+
+			"and create or update the collection with it before the import")
+	importCmd.Flags().BoolVar(&UpdateSchema, "update-schema", false,
+		"Continue to update the schema of the collection from the imported documents after --infer-from")
+	importCmd.Flags().BoolVar(&SchemaOnCreateOnly, "schema-on-create-only", false,
+		"Infer the schema only to create the collection, the schema is not evolved by the subsequent batches. "+
+			"The schema of the existing collection is used as is")
+	importCmd.Flags().StringVar(&PartitionBy, "partition-by", "",
+		"Route documents to the collections named by the value of this field. Nested fields use dot notation")
+	importCmd.Flags().StringVar(&PartitionPrefix, "partition-prefix", "",
+		"Prefix of the collection names created by --partition-by")
+	importCmd.Flags().IntVar(&MaxPartitions, "max-partitions", MaxPartitions,
+		"Maximum number of collections created by --partition-by")
+	importCmd.Flags().StringVar(&schema.CollectionDescription, "collection-description", "",
+		"Description of the collection created by the import, stored in the schema")
+	importCmd.Flags().DurationVar(&schema.TTL, "ttl", 0,
+		"Time to live of the documents of the new collection, like 720h. Requires --ttl-field")
+	importCmd.Flags().StringVar(&schema.TTLField, "ttl-field", "",
+		"Top level date-time field the time to live of the document is counted from")
+	importCmd.Flags().StringSliceVar(&SecondaryIndex, "secondary-index", []string{},
+		"Comma separated list of fields to build secondary index on, when the collection is created. "+
+			"Nested fields are specified using dot notation: address.city. "+
+			"Fields missing in the first documents are indexed once they appear")
 	importCmd.Flags().BoolVar(&CleanUpNULLs, "cleanup-null-values", true,
 		"Remove NULL values and empty arrays from the documents before importing")
+	importCmd.Flags().BoolVar(&iterate.EmptyStringAsNull, "empty-string-as-null", false,
+		"Convert empty string values to null before schema inference, so as they don't force the field to be a string")
+	importCmd.Flags().BoolVar(&iterate.SanitizeKeys, "sanitize-keys", false,
+		"Replace characters not allowed in field names with '_' and prefix names starting with a digit. "+
+			"Renamed fields are reported after the import. Other options refer to the sanitized names")
+	importCmd.Flags().StringVar(&iterate.ObjectMapKey, "object-map-key", "",
+		"Read the input as top-level object, mapping the keys to the documents, like {\"id1\": {...}, \"id2\": {...}}. "+
+			"The key is set to the field with this name")
+	importCmd.Flags().BoolVar(&schema.CoerceValues, "coerce-values", false,
+		"Convert the values of the documents to the types of the fields of the schema, like \"42\" to 42")
+	importCmd.Flags().StringVar(&iterate.RowNumberField, "add-row-number", "",
+		"Set the field with this name to the number of the document in the input, starting from 1. "+
+			"The header of the CSV input is not counted. Helps to find the source rows of the failed documents")
+	importCmd.Flags().StringVar(&iterate.Where, "where", "",
+		"Import only the documents matching the predicate, like: status == \"active\" && age >= 18")
+	importCmd.Flags().StringSliceVar(&iterate.OnlyFields, "only-fields", []string{},
+		"Comma separated list of the fields to import, the other fields of the documents are discarded. "+
+			"Nested fields are specified using dot notation. Absent fields are omitted. "+
+			"--where and --watermark-field see the whole documents, the fields added by the CLI are kept")
+	importCmd.Flags().StringVar(&iterate.WatermarkField, "watermark-field", "",
+		"Import only the documents with the value of the field greater than the maximum value imported by the previous run, "+
+			"which is persisted in --state-file")
+	importCmd.Flags().StringVar(&iterate.StateFile, "state-file", "",
+		"File where the watermark of --watermark-field is persisted between the runs")
+	importCmd.Flags().StringVar(&iterate.NormalizeUnicode, "normalize-unicode", "",
+		"Normalize the string values of the documents to the Unicode normalization form: NFC or NFD. Default: off")
+	importCmd.Flags().BoolVar(&iterate.NormalizeUnicodeKeys, "normalize-unicode-keys", false,
+		"Normalize the field names as well, with --normalize-unicode")
+	importCmd.Flags().BoolVar(&iterate.Compact, "compact", false,
+		"Remove insignificant whitespace from the documents before importing. The order of the fields is preserved")
+	importCmd.Flags().BoolVar(&iterate.WarnDuplicateKeys, "warn-duplicate-keys", false,
+		"Report duplicate keys in the objects of the documents, only the last value of which is retained. "+
+			"The import fails on duplicate keys with --strict")
+	importCmd.Flags().BoolVar(&iterate.StrictJSON, "strict-json", false,
+		"Fail fast on the JSON documents with duplicate keys or invalid UTF-8 and on the data "+
+			"trailing the array of documents, reporting the line and the offset of the invalid document")
+	importCmd.Flags().BoolVar(&util.PreserveOrder, "preserve-order", false,
+		"Preserve the order of the fields of the documents modified by the CLI, like --cleanup-null-values or --add-field. "+
+			"The order of the fields in the documents stored by the server is not guaranteed")
+	importCmd.Flags().BoolVar(&iterate.SkipErrors, "skip-errors", false,
+		"Continue import if some documents failed to be inserted. "+
+			"The number of the failed documents by the error class is printed at the end")
+	importCmd.Flags().StringVar(&iterate.ErrorFile, "error-file", "",
+		"Save documents failed to be inserted to the file, in order to retry them later with reimport command")
+	importCmd.Flags().BoolVar(&Clipboard, "clipboard", false,
+		"Import the JSON documents from the system clipboard. "+
+			"Requires pbpaste on macOS, PowerShell on Windows, wl-paste, xclip or xsel on Linux")
+	importCmd.Flags().StringVar(&iterate.Tee, "tee", "",
+		"Also write the successfully imported documents, after all the processing done by the CLI, "+
+			"to the file as newline delimited JSON. The file is gzip compressed when the name ends with .gz")
+	importCmd.Flags().Int64Var(&iterate.MaxErrors, "max-errors", 0,
+		"Abort the import with exit code 3 when this many documents failed to be inserted. Implies --skip-errors")
+	importCmd.Flags().Float64Var(&iterate.MaxErrorRate, "max-error-rate", 0,
+		"Abort the import with exit code 3 when the percentage of failed documents exceeds this value. "+
+			"Checked after first 100 documents. Implies --skip-errors")
 
 	importCmd.Flags().StringVar(&CSVDelimiter, "csv-delimiter", "",
-		"CSV delimiter")
-	importCmd.Flags().BoolVar(&CSVTrimLeadingSpace, "csv-trim-leading-space", true,
+		"CSV delimiter. Escape sequences, like \\t, are interpreted. "+
+			"Multi-character delimiters, like ||, are supported at the cost of slower import")
+	importCmd.Flags().BoolVar(&CSVTrimLeadingSpace, "csv-trim-leading-space", defaultCSVTrimLeadingSpace,
 		"Trim leading space in the fields")
 	importCmd.Flags().StringVar(&CSVComment, "csv-comment", "",
 		"CSV comment")
+	importCmd.Flags().StringArrayVar(&iterate.CSVArrayFields, "csv-array-field", []string{},
+		"Split the CSV column into array by the delimiter: name:delimiter, for example tags:;. Can be repeated")
+	importCmd.Flags().StringArrayVar(&iterate.Defaults, "default", []string{},
+		"Set the field to the value when it's absent or null: name=value. Can be repeated. "+
+			"The value is parsed as JSON, so numbers, booleans, arrays and objects are supported, "+
+			"other values are used as strings. Nested fields are specified using dot notation")
+	importCmd.Flags().StringVar(&iterate.CreatedAtField, "created-at-field", "",
+		"Set the field to the time of the import, when it's absent or null in the document. "+
+			"The field is inferred as date-time. Nested fields are specified using dot notation")
+	importCmd.Flags().StringVar(&iterate.UpdatedAtField, "updated-at-field", "",
+		"Always set the field to the time of the import. "+
+			"The field is inferred as date-time. Nested fields are specified using dot notation")
+	importCmd.Flags().StringArrayVar(&iterate.EncryptFields, "encrypt-field", []string{},
+		"Encrypt the values of the field before insert. Can be repeated. Nested fields are specified using dot notation")
+	importCmd.Flags().StringVar(&iterate.EncryptMode, "encrypt-mode", util.EncryptRandom,
+		"Encryption mode: random or deterministic. Deterministic mode allows equality filters on encrypted fields")
+	importCmd.Flags().StringVar(&iterate.EncryptionKey, "encryption-key", "",
+		"Hex or base64 encoded 32 bytes encryption key. Defaults to TIGRIS_ENCRYPTION_KEY environment variable")
+	importCmd.Flags().StringVar(&iterate.EncryptionKeyFile, "encryption-key-file", "",
+		"Read the encryption key from the file")
+	importCmd.Flags().StringArrayVar(&iterate.AddFields, "add-field", []string{},
+		"Add computed field to every document: name=expr. Can be repeated. "+
+			"Supported expressions: now(), uuid(), concat(field, 'literal', ...)")
+	importCmd.Flags().StringVar(&iterate.ValidateWith, "validate-with", "",
+		"Validate documents against the JSON Schema file before importing. Invalid documents abort the import, unless --skip-errors is set")
+	importCmd.Flags().BoolVar(&iterate.FieldStats, "field-stats", false,
+		"Report number of documents with null or absent value per field after the import")
+	importCmd.Flags().StringVar(&iterate.Format, "format", "",
+		"Format of the input: json, csv, msgpack. JSON and CSV are detected automatically if not set. "+
+			"MessagePack binary values are converted to base64 strings and timestamps to RFC3339 strings")
+	importCmd.Flags().StringVar(&iterate.InputEncoding, "input-encoding", "",
+		"Character encoding of the input, which is converted to UTF-8 before parsing. "+
+			"For example: latin1, windows-1252, utf-16le. Default: utf-8")
+	importCmd.Flags().IntVar(&iterate.InputBufferSize, "input-buffer-size", iterate.DefaultInputBufferSize,
+		"Size of the input read buffer in bytes. Larger buffers speed up reading of big files, "+
+			"smaller save memory in constrained environments. Minimum: 4096")
+	importCmd.Flags().StringVar(&iterate.GCSProject, "gcs-project", "",
+		"Google Cloud project billed for reading gs:// sources from requester pays buckets")
 
 	importCmd.Flags().BoolVar(&schema.DetectByteArrays, "detect-byte-arrays", false,
 		"Try detect byte arrays fields")
+	importCmd.Flags().StringVar(&schema.ByteEncoding, "byte-encoding", "",
+		"Encoding of the byte array values: base64, hex. The values of the byte fields are validated, "+
+			"and hex values are converted to base64, before insert. "+
+			"--detect-byte-arrays detects the byte fields in this encoding. Default: base64 without validation")
 	importCmd.Flags().BoolVar(&schema.DetectUUIDs, "detect-uuids", true,
 		"Try detect UUID fields")
 	importCmd.Flags().BoolVar(&schema.DetectTimes, "detect-times", true,
 		"Try detect date time fields")
 	importCmd.Flags().BoolVar(&schema.DetectIntegers, "detect-integers", true,
 		"Try detect integer fields")
+	importCmd.Flags().BoolVar(&schema.DetectGeo, "detect-geo", false,
+		"Try detect GeoJSON geometry fields")
+	importCmd.Flags().BoolVar(&schema.DetectEnums, "detect-enums", false,
+		"Emit enum constraint for the string fields with low number of distinct sampled values")
+	importCmd.Flags().IntVar(&schema.EnumThreshold, "enum-threshold", 10,
+		"Maximum number of distinct values of the string field to be detected as enum")
+
+	addPrintSchemaFlag(importCmd)
+	importCmd.Flags().IntVar(&schema.MaxFields, "max-fields", 0,
+		"Warn when the inferred schema exceeds this number of top level fields. Default: no limit")
+	importCmd.Flags().StringVar(&schema.InferenceLog, "inference-log", "",
+		"Write every sample value considered by the schema inference and the type decision, "+
+			"as JSON lines, to the file. Useful to diagnose why a field got a particular type")
+	importCmd.Flags().BoolVar(&util.Strict, "strict", false,
+		"Fail the import instead of printing a warning")
 
+	importCmd.Flags().BoolVar(&util.Optimize, "optimize", false,
+		"Optimize the collection or the search index after the import. "+
+			"No-op for now: the server doesn't expose the optimize operation, so a warning is printed, "+
+			"or the import fails with --strict")
+	importCmd.Flags().StringVar(&util.ShardKey, "shard-key", "",
+		"Route the inserts by the value of the field for better write distribution. "+
+			"No-op for now: neither the server nor the driver exposes shard routing, so a warning is printed, "+
+			"or the import fails with --strict")
 	addProjectFlag(importCmd)
 	rootCmd.AddCommand(importCmd)
 }
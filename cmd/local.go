@@ -187,7 +187,7 @@ func waitServerUp(port string) {
 	util.Fatal(err, "init tigris client")
 
 	if err = pingLow(context.Background(), waitUpTimeout, pingSleepTimeout, true, true,
-		util.IsTTY(os.Stdout) && !util.Quiet); err != nil {
+		util.IsTTY(os.Stderr) && !util.Quiet); err != nil {
 		util.Fatal(err, "tigris initialization failed")
 	}
 
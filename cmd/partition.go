@@ -0,0 +1,188 @@
+// Copyright 2022-2023 Tigris Data, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/tigrisdata/tigris-cli/config"
+	"github.com/tigrisdata/tigris-cli/iterate"
+	"github.com/tigrisdata/tigris-cli/util"
+)
+
+var (
+	PartitionBy     string
+	PartitionPrefix string
+	MaxPartitions   = 100
+
+	ErrPartitionValue    = fmt.Errorf("partition field should be non-empty string or number")
+	ErrMaxPartitions     = fmt.Errorf("number of partitions exceeds --max-partitions")
+	ErrPartitionNoHeader = fmt.Errorf("--partition-by can't be used with --csv-no-header")
+)
+
+// partitionValue returns the value of the PartitionBy field of the document.
+// Nested fields are specified using dot notation.
+func partitionValue(doc json.RawMessage) (string, error) {
+	var m map[string]any
+
+	dec := json.NewDecoder(bytes.NewReader(doc))
+	dec.UseNumber()
+
+	if err := dec.Decode(&m); err != nil {
+		return "", err
+	}
+
+	var v any = m
+
+	for _, p := range strings.Split(PartitionBy, ".") {
+		obj, ok := v.(map[string]any)
+		if !ok {
+			v = nil
+			break
+		}
+
+		v = obj[p]
+	}
+
+	switch val := v.(type) {
+	case string:
+		if val != "" {
+			return val, nil
+		}
+	case json.Number:
+		return val.String(), nil
+	}
+
+	return "", fmt.Errorf("%w: %s", ErrPartitionValue, PartitionBy)
+}
+
+// partitioner routes the documents to the collections named by the value of the partition field.
+// Every collection has its own importer, and so its own inferred schema.
+type partitioner struct {
+	db        string
+	importers map[string]*importer
+	colls     []string // in the order of creation
+}
+
+func (p *partitioner) importer(ctx context.Context, coll string) (*importer, error) {
+	if imp, ok := p.importers[coll]; ok {
+		return imp, nil
+	}
+
+	if len(p.importers) >= MaxPartitions {
+		return nil, fmt.Errorf("%w: %d", ErrMaxPartitions, MaxPartitions)
+	}
+
+	imp, err := openImporter(ctx, p.db, coll)
+	if err != nil {
+		return nil, err
+	}
+
+	p.importers[coll] = imp
+	p.colls = append(p.colls, coll)
+
+	return imp, nil
+}
+
+func (p *partitioner) insert(ctx context.Context, docs []json.RawMessage) error {
+	batches := make(map[string][]json.RawMessage)
+
+	var colls []string // preserve the order of the partitions in the batch
+
+	for _, doc := range docs {
+		v, err := partitionValue(doc)
+		if err != nil {
+			return err
+		}
+
+		coll := PartitionPrefix + v
+		if _, ok := batches[coll]; !ok {
+			colls = append(colls, coll)
+		}
+
+		batches[coll] = append(batches[coll], doc)
+	}
+
+	for _, coll := range colls {
+		imp, err := p.importer(ctx, coll)
+		if err != nil {
+			return err
+		}
+
+		if err = imp.insertWithInference(ctx, batches[coll]); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (p *partitioner) finish() error {
+	for _, coll := range p.colls {
+		imp := p.importers[coll]
+
+		if imp.onConflict == util.OnConflictSkip {
+			util.Infof("Collection %s:", coll)
+		}
+
+		if err := imp.finish(); err != nil {
+			return err
+		}
+	}
+
+	util.Infof("Imported into %d collection(s): %s", len(p.colls), strings.Join(p.colls, ", "))
+
+	return nil
+}
+
+// importPartitioned imports all the positional arguments as documents,
+// routing them to the collections by the value of the partition field.
+func importPartitioned(cmd *cobra.Command, args []string) ([]*importer, error) {
+	if CSVNoHeader {
+		util.Fatal(ErrPartitionNoHeader, "partition by")
+	}
+
+	p := &partitioner{db: config.GetProjectName(), importers: make(map[string]*importer)}
+
+	err := iterate.Input(cmd.Context(), cmd, 0, args,
+		func(ctx context.Context, args []string, docs []json.RawMessage) error {
+			return p.insert(ctx, docs)
+		})
+	if err == nil {
+		err = p.finish()
+	}
+
+	imps := make([]*importer, 0, len(p.colls))
+	for _, coll := range p.colls {
+		imps = append(imps, p.importers[coll])
+	}
+
+	return imps, err
+}
+
+// importArgs validates the positional arguments of the import command.
+// Collection name is not required when partitioning by the field value.
+func importArgs(cmd *cobra.Command, args []string) error {
+	if PartitionBy != "" {
+		return nil
+	}
+
+	return cobra.MinimumNArgs(1)(cmd, args)
+}
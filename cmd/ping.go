@@ -16,8 +16,12 @@ package cmd
 
 import (
 	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
 	"fmt"
 	"math/rand"
+	"net"
 	"os"
 	"strings"
 	"time"
@@ -27,10 +31,71 @@ import (
 	"github.com/tigrisdata/tigris-cli/client"
 	"github.com/tigrisdata/tigris-cli/config"
 	"github.com/tigrisdata/tigris-cli/util"
+	api "github.com/tigrisdata/tigris-client-go/api/server/v1"
 )
 
 var pingTimeout time.Duration
 
+const (
+	pingFailureDNS  = "DNS resolution failed"
+	pingFailureTCP  = "TCP connection failed"
+	pingFailureTLS  = "TLS handshake failed"
+	pingFailureAuth = "authentication failed"
+)
+
+func containsAny(s string, substrs ...string) bool {
+	for _, v := range substrs {
+		if strings.Contains(s, v) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// pingFailure classifies the ping error.
+// Errors returned over gRPC lose their types, so the message is checked as well.
+func pingFailure(err error) string {
+	var (
+		dnsErr  *net.DNSError
+		opErr   *net.OpError
+		hdrErr  tls.RecordHeaderError
+		certErr x509.UnknownAuthorityError
+		hostErr x509.HostnameError
+	)
+
+	msg := err.Error()
+
+	switch {
+	case isErrorCode(err, api.Code_UNAUTHENTICATED) || isErrorCode(err, api.Code_PERMISSION_DENIED):
+		return pingFailureAuth
+	case errors.As(err, &dnsErr) || containsAny(msg, "no such host", "server misbehaving"):
+		return pingFailureDNS
+	case errors.As(err, &hdrErr) || errors.As(err, &certErr) || errors.As(err, &hostErr) ||
+		containsAny(msg, "tls:", "x509:", "handshake"):
+		return pingFailureTLS
+	case errors.As(err, &opErr) || containsAny(msg, "connection refused", "connection reset", "i/o timeout",
+		"no route to host", "network is unreachable"):
+		return pingFailureTCP
+	}
+
+	return ""
+}
+
+// serverVersion returns the version of the server, or empty string if it's not available.
+func serverVersion(cmdCtx context.Context) string {
+	ctx, cancel := util.GetContext(cmdCtx)
+	defer cancel()
+
+	resp, err := client.D.Info(ctx)
+	if err != nil {
+		_ = util.Error(err, "ping server version")
+		return ""
+	}
+
+	return resp.ServerVersion
+}
+
 func pingCall(ctx context.Context, waitAuth bool) error {
 	var err error
 
@@ -69,6 +134,7 @@ func initPingProgressBar(init bool) *progressbar.ProgressBar {
 		progressbar.OptionSpinnerType(int(rand.Int63()%76)), //nolint:gosec
 		progressbar.OptionSetRenderBlankState(true),
 		progressbar.OptionFullWidth(),
+		progressbar.OptionEnableColorCodes(util.ColorEnabled(os.Stderr)),
 	)
 }
 
@@ -130,6 +196,12 @@ func pingLow(cmdCtx context.Context, timeout time.Duration, sleep time.Duration,
 var pingCmd = &cobra.Command{
 	Use:   "ping",
 	Short: "Checks connection to Tigris",
+	Long: `Checks connection to Tigris server using configured URL and protocol.
+Reports server version on success.
+
+On failure exits with non-zero code and reports the failed stage, when it can be determined:
+DNS resolution, TCP connection, TLS handshake or authentication.
+`,
 	Run: func(cmd *cobra.Command, args []string) {
 		var err error
 
@@ -139,12 +211,22 @@ var pingCmd = &cobra.Command{
 			config.DefaultConfig.ClientSecret != "")
 
 		if err = pingLow(cmd.Context(), pingTimeout, 32*time.Millisecond, localURL(config.DefaultConfig.URL),
-			waitForAuth, util.IsTTY(os.Stdout) && !util.Quiet); err == nil {
+			waitForAuth, util.IsTTY(os.Stderr) && !util.Quiet); err == nil {
 			_, _ = fmt.Fprintf(os.Stderr, "OK\n")
+
+			if v := serverVersion(cmd.Context()); v != "" {
+				_, _ = fmt.Fprintf(os.Stderr, "Server version: %s\n", v)
+			}
+
 			return
 		}
 
-		_, _ = fmt.Fprintf(os.Stderr, "FAILED\n")
+		if f := pingFailure(err); f != "" {
+			_, _ = fmt.Fprintf(os.Stderr, "FAILED: %s: %s\n", f, err.Error())
+		} else {
+			_, _ = fmt.Fprintf(os.Stderr, "FAILED: %s\n", err.Error())
+		}
+
 		os.Exit(1) //nolint:revive
 	},
 }
@@ -0,0 +1,56 @@
+// Copyright 2022-2023 Tigris Data, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/tigrisdata/tigris-cli/client"
+	"github.com/tigrisdata/tigris-cli/util"
+	api "github.com/tigrisdata/tigris-client-go/api/server/v1"
+)
+
+var (
+	NoPreflight bool
+
+	ErrProjectNotFound = fmt.Errorf("project doesn't exist")
+)
+
+// preflight checks the connectivity, the authentication and the existence of the project,
+// so the import fails fast, before reading the input.
+func preflight(cmdCtx context.Context, db string) error {
+	if NoPreflight {
+		return nil
+	}
+
+	ctx, cancel := util.GetContext(cmdCtx)
+	defer cancel()
+
+	_, err := client.Get().DescribeDatabase(ctx, db)
+	if err == nil {
+		return nil
+	}
+
+	if isErrorCode(err, api.Code_NOT_FOUND) {
+		return fmt.Errorf("%w: %s", ErrProjectNotFound, db)
+	}
+
+	if f := pingFailure(err); f != "" {
+		return fmt.Errorf("%s: %w", f, err)
+	}
+
+	return err
+}
@@ -37,6 +37,7 @@ var (
 	schemaOnly    bool
 	format        string
 	createEnvFile bool
+	detailed      bool
 )
 
 var listProjectsCmd = &cobra.Command{
@@ -16,18 +16,23 @@ package cmd
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 
 	"github.com/spf13/cobra"
 	"github.com/tigrisdata/tigris-cli/client"
+	"github.com/tigrisdata/tigris-cli/iterate"
 	"github.com/tigrisdata/tigris-cli/login"
 	"github.com/tigrisdata/tigris-cli/util"
 	"github.com/tigrisdata/tigris-client-go/driver"
 )
 
 var (
-	limit int64
-	skip  int64
+	limit   int64
+	skip    int64
+	renames []string
+
+	decryptFields []string
 )
 
 var readCmd = &cobra.Command{
@@ -38,7 +43,14 @@ If filter is not provided or an empty json document {} is passed as a filter,
 all documents in the collection are returned.
 
 If fields are not provided or an empty json document {} is passed as fields,
-all the fields of the documents are selected.`,
+all the fields of the documents are selected.
+
+Fields of the output documents can be renamed with --rename old=new,
+nested fields are specified using dot notation.
+
+Fields encrypted by 'import --encrypt-field' are decrypted with --decrypt-field,
+using the key from --encryption-key, --encryption-key-file or TIGRIS_ENCRYPTION_KEY
+environment variable.`,
 	Example: fmt.Sprintf(`
   # Read a user document where id is 20
   # The output would be 
@@ -58,9 +70,27 @@ all the fields of the documents are selected.`,
   #  {"id": 20, "name": "Jania McGrory"}
   #  {"id": 21, "name": "Bunny Instone"}
   %[1]s read --project=myproj users
+
+  # Rename fields of the output documents
+  # The output would be
+  #  {"id": 20, "full_name": "Jania McGrory"}
+  %[1]s read --project=myproj users '{"id": 20}' --rename name=full_name
+
+  # Decrypt the email field encrypted by the import
+  %[1]s read --project=myproj users --decrypt-field=email --encryption-key-file=key.txt
 `, rootCmd.Root().Name()),
 	Args: cobra.MinimumNArgs(1),
 	Run: func(cmd *cobra.Command, args []string) {
+		rn, err := util.ParseRenames(renames)
+		util.Fatal(err, "parse renames")
+
+		var dec *util.FieldCipher
+
+		if len(decryptFields) > 0 {
+			dec, err = iterate.NewFieldCipher(util.EncryptRandom, decryptFields)
+			util.Fatal(err, "decrypt fields")
+		}
+
 		login.Ensure(cmd.Context(), func(ctx context.Context) error {
 			filter, fields := `{}`, `{}`
 
@@ -84,6 +114,19 @@ all the fields of the documents are selected.`,
 
 			var doc driver.Document
 			for it.Next(&doc) {
+				if dec != nil {
+					b, err := dec.Decrypt(json.RawMessage(doc))
+					if err != nil {
+						return util.Error(err, "decrypt fields")
+					}
+
+					doc = driver.Document(b)
+				}
+
+				if doc, err = rn.Apply(doc); err != nil {
+					return util.Error(err, "rename fields")
+				}
+
 				// Document came through GRPC may have \n at the end already
 				if doc[len(doc)-1] == 0x0A {
 					util.Stdoutf("%s", string(doc))
@@ -101,5 +144,15 @@ func init() {
 	addProjectFlag(readCmd)
 	readCmd.Flags().Int64VarP(&limit, "limit", "l", 0, "limit number of returned results")
 	readCmd.Flags().Int64VarP(&skip, "skip", "s", 0, "skip this many results in the beginning of the result set")
+	readCmd.Flags().StringArrayVar(&renames, "rename", []string{},
+		"Rename field of the output documents: old=new. Can be repeated. Nested fields use dot notation: a.b=a.c")
+	readCmd.Flags().BoolVar(&util.PreserveOrder, "preserve-order", false,
+		"Keep the order of the fields, as returned by the server, in the documents modified by --rename")
+	readCmd.Flags().StringArrayVar(&decryptFields, "decrypt-field", []string{},
+		"Decrypt the values of the field encrypted by the import. Can be repeated")
+	readCmd.Flags().StringVar(&iterate.EncryptionKey, "encryption-key", "",
+		"Hex or base64 encoded 32 bytes encryption key. Defaults to TIGRIS_ENCRYPTION_KEY environment variable")
+	readCmd.Flags().StringVar(&iterate.EncryptionKeyFile, "encryption-key-file", "",
+		"Read the encryption key from the file")
 	rootCmd.AddCommand(readCmd)
 }
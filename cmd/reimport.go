@@ -0,0 +1,113 @@
+// Copyright 2022-2023 Tigris Data, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/tigrisdata/tigris-cli/config"
+	"github.com/tigrisdata/tigris-cli/iterate"
+	"github.com/tigrisdata/tigris-cli/login"
+	"github.com/tigrisdata/tigris-cli/util"
+)
+
+var ErrSameErrorFile = fmt.Errorf("new error file should be different from the file being reimported")
+
+// retryErrorFileName returns the name of the error file for the documents
+// which are still failing after reimport: errors.json -> errors.retry.json.
+func retryErrorFileName(name string) string {
+	ext := filepath.Ext(name)
+
+	return strings.TrimSuffix(name, ext) + ".retry" + ext
+}
+
+var reimportCmd = &cobra.Command{
+	Use:   "reimport {collection} {error-file}",
+	Short: "Retry importing documents from the error file",
+	Long: `Retries importing the documents which previously failed to be imported.
+Error file is produced by the import command with --error-file parameter.
+
+Documents which still fail to be imported are saved to the new error file,
+which by default is the name of the original file with .retry suffix.
+`,
+	Example: fmt.Sprintf(`
+  %[1]s import --project=myproj users --skip-errors --error-file=users.errors.json <users.json
+  # fix the cause of the failure and retry
+  %[1]s reimport --project=myproj users users.errors.json
+`, rootCmd.Root().Name()),
+	Args: cobra.ExactArgs(2),
+	Run: func(cmd *cobra.Command, args []string) {
+		iterate.SkipErrors = true
+
+		if iterate.ErrorFile == "" {
+			iterate.ErrorFile = retryErrorFileName(args[1])
+		}
+
+		if filepath.Clean(iterate.ErrorFile) == filepath.Clean(args[1]) {
+			util.Fatal(ErrSameErrorFile, "reimport")
+		}
+
+		login.Ensure(cmd.Context(), func(ctx context.Context) error {
+			imp := newImporter(config.GetProjectName(), args[0])
+			imp.loadSchema(ctx)
+
+			f, err := os.Open(args[1])
+			util.Fatal(err, "open error file")
+
+			defer func() { _ = f.Close() }()
+
+			return iterate.ErrorFileInput(cmd.Context(), args, f,
+				func(ctx context.Context, args []string, docs []json.RawMessage) error {
+					return imp.insertWithInference(ctx, docs)
+				})
+		})
+	},
+}
+
+func init() {
+	reimportCmd.Flags().Int32VarP(&iterate.BatchSize, "batch-size", "b", iterate.BatchSize, "set batch size")
+	reimportCmd.Flags().DurationVar(&iterate.BatchTimeout, "batch-timeout", 0,
+		"Timeout of a single batch request, every batch gets its own deadline. Default: no timeout")
+	reimportCmd.Flags().Int64Var(&iterate.MaxRequestBytes, "max-request-bytes", 0,
+		"Split batches so that a single request doesn't exceed this size in bytes. "+
+			"Useful with HTTP protocol, where very large requests may fail. Default: no limit")
+	reimportCmd.Flags().Int64Var(&iterate.MaxDocSize, "max-doc-size", 0,
+		"Reject the documents larger than this size in bytes, reporting their numbers in the input. "+
+			"The documents are skipped with --skip-errors, otherwise the import is aborted. Default: no limit")
+	reimportCmd.Flags().BoolVar(&iterate.AdaptiveRate, "adaptive-rate", false,
+		"Slow down when the server throttles the requests and speed back up when throttling clears")
+	reimportCmd.Flags().BoolVar(&NoCreate, "no-create-collection", false,
+		"Do not create collection automatically if it doesn't exist")
+	reimportCmd.Flags().StringSliceVar(&PrimaryKey, "primary-key", []string{},
+		"Comma separated list of field names which constitutes collection's primary key (only top level keys supported)")
+	reimportCmd.Flags().StringSliceVar(&AutoGenerate, "autogenerate", []string{},
+		"Comma separated list of autogenerated fields (only top level keys supported)")
+	reimportCmd.Flags().BoolVar(&CleanUpNULLs, "cleanup-null-values", true,
+		"Remove NULL values and empty arrays from the documents before importing")
+	reimportCmd.Flags().StringVar(&iterate.ErrorFile, "error-file", "",
+		"Save documents which still fail to be inserted to the file. Default: {error-file}.retry")
+	reimportCmd.Flags().StringVar(&iterate.Tee, "tee", "",
+		"Also write the successfully imported documents, after all the processing done by the CLI, "+
+			"to the file as newline delimited JSON. The file is gzip compressed when the name ends with .gz")
+
+	addProjectFlag(reimportCmd)
+	rootCmd.AddCommand(reimportCmd)
+}
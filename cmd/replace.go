@@ -18,14 +18,12 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
-	"unsafe"
 
 	"github.com/spf13/cobra"
 	"github.com/tigrisdata/tigris-cli/client"
 	"github.com/tigrisdata/tigris-cli/iterate"
 	"github.com/tigrisdata/tigris-cli/login"
 	"github.com/tigrisdata/tigris-cli/util"
-	"github.com/tigrisdata/tigris-client-go/driver"
 )
 
 var replaceCmd = &cobra.Command{
@@ -58,8 +56,7 @@ var replaceCmd = &cobra.Command{
 	Run: func(cmd *cobra.Command, args []string) {
 		login.Ensure(cmd.Context(), func(ctx context.Context) error {
 			return iterate.Input(ctx, cmd, 1, args, func(ctx context.Context, args []string, docs []json.RawMessage) error {
-				ptr := unsafe.Pointer(&docs)
-				_, err := client.GetDB().Replace(ctx, args[0], *(*[]driver.Document)(ptr))
+				_, err := client.GetDB().Replace(ctx, args[0], client.Documents(docs))
 
 				return util.Error(err, "replace documents failed")
 			})
@@ -69,6 +66,8 @@ var replaceCmd = &cobra.Command{
 
 func init() {
 	replaceCmd.Flags().Int32VarP(&iterate.BatchSize, "batch-size", "b", iterate.BatchSize, "set batch size")
+	replaceCmd.Flags().DurationVar(&iterate.FlushInterval, "flush-interval", 0,
+		"Flush partially filled batch after the interval, so the documents of slow streams are processed without waiting for the batch to fill up")
 	addProjectFlag(replaceCmd)
 	rootCmd.AddCommand(replaceCmd)
 }
@@ -0,0 +1,145 @@
+// Copyright 2022-2023 Tigris Data, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"encoding/json"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+	"github.com/tigrisdata/tigris-cli/iterate"
+	"github.com/tigrisdata/tigris-cli/util"
+)
+
+// ReportFile is the path of the JSON report of the import run.
+var ReportFile string
+
+// redactedFlags contain credentials, their values are not written to the report.
+// Incoming webhook URLs, like Slack ones, are credentials as well.
+var redactedFlags = map[string]bool{
+	"dsn":            true,
+	"encryption-key": true,
+	"webhook":        true,
+	"webhook-secret": true,
+}
+
+var (
+	importersMu sync.Mutex
+	// importers created by the command, so as they can be reported
+	// when the import exits on a fatal error before returning them.
+	importers []*importer
+)
+
+func trackImporter(imp *importer) *importer {
+	importersMu.Lock()
+	defer importersMu.Unlock()
+
+	importers = append(importers, imp)
+
+	return imp
+}
+
+func trackedImporters() []*importer {
+	importersMu.Lock()
+	defer importersMu.Unlock()
+
+	return append([]*importer{}, importers...)
+}
+
+// onImportExit calls fn with the importers created so far and the error,
+// when the import exits on a fatal error, so as the report and the summary
+// are delivered on failure as well.
+// Returns the function which cancels the hook, once the import has returned.
+func onImportExit(fn func(imps []*importer, err error)) func() {
+	return util.OnExit(func(err error) {
+		fn(trackedImporters(), err)
+	})
+}
+
+// importReport is the complete report of the import run written to the --report-file.
+type importReport struct {
+	Version string            `json:"version"`
+	Args    []string          `json:"args"`
+	Flags   map[string]string `json:"flags"`
+
+	*importSummary
+
+	Batches []iterate.BatchStat        `json:"batches"`
+	Schemas map[string]json.RawMessage `json:"schemas,omitempty"`
+}
+
+func reportFlags(cmd *cobra.Command) map[string]string {
+	flags := map[string]string{}
+
+	cmd.Flags().Visit(func(f *pflag.Flag) {
+		v := f.Value.String()
+
+		if redactedFlags[f.Name] || strings.Contains(f.Name, "secret") ||
+			strings.Contains(f.Name, "password") || strings.Contains(f.Name, "token") {
+			v = "REDACTED"
+		}
+
+		flags[f.Name] = v
+	})
+
+	return flags
+}
+
+// writeReport writes the report of the import run to the ReportFile, if configured.
+// The report is written on failure as well. Failure to write the report is only reported.
+func writeReport(cmd *cobra.Command, imps []*importer, start time.Time, importErr error) {
+	if ReportFile == "" {
+		return
+	}
+
+	r := &importReport{
+		Version:       util.Version,
+		Args:          cmd.Flags().Args(),
+		Flags:         reportFlags(cmd),
+		importSummary: newImportSummary(cmd.Name(), imps, start, importErr),
+		Batches:       iterate.BatchStats(),
+		Schemas:       map[string]json.RawMessage{},
+	}
+
+	for _, imp := range imps {
+		if len(imp.prevSchema) > 0 {
+			r.Schemas[imp.coll] = imp.prevSchema
+		}
+	}
+
+	b, err := json.MarshalIndent(r, "", "  ")
+	if err == nil {
+		err = os.WriteFile(ReportFile, b, 0o644) //nolint:gosec
+	}
+
+	if err != nil {
+		util.Stderrf("warning: failed to write report file: %s\n", err.Error())
+	}
+}
+
+func addReportFileFlag(cmd *cobra.Command) {
+	cmd.Flags().StringVar(&ReportFile, "report-file", "",
+		"Write JSON report of the run: CLI version, flags, counts, timings of the batches, "+
+			"errors and the final schema, to the file on completion or failure")
+}
+
+// enableReport prepares collection of the data for the report.
+func enableReport() {
+	iterate.RecordBatches = ReportFile != ""
+}
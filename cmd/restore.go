@@ -22,7 +22,6 @@ import (
 	"regexp"
 	"strings"
 	"time"
-	"unsafe"
 
 	"github.com/spf13/cobra"
 	"github.com/tigrisdata/tigris-cli/client"
@@ -200,9 +199,7 @@ func restoreCollection(ctx context.Context, db, collection, path string) error {
 			break
 		}
 
-		ptr := unsafe.Pointer(&docs)
-
-		_, err := client.Get().UseDatabase(restoreDB).Insert(ctx, collection, *(*[]driver.Document)(ptr))
+		_, err := client.Get().UseDatabase(restoreDB).Insert(ctx, collection, client.Documents(docs))
 		if err != nil {
 			return util.Error(err, "insert document")
 		}
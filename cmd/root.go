@@ -43,6 +43,37 @@ func Execute() {
 func init() {
 	rootCmd.Flags().BoolVarP(&util.Quiet, "quiet", "q", false,
 		"Suppress informational messages")
+	rootCmd.PersistentFlags().StringVar(&util.Color, "color", util.ColorAuto,
+		"Colorize the output: auto, always, never. Auto mode respects NO_COLOR environment variable")
+	rootCmd.PersistentFlags().StringVar(&config.DefaultConfig.Log.Format, "log-format", "",
+		"Format of the log and the fatal errors on stderr: console, json. "+
+			"In json mode the fatal error is printed as a JSON object with code, message, context and retryable fields. "+
+			"Defaults to console output to terminal and JSON log to pipe")
+	rootCmd.PersistentFlags().StringVar(&config.DefaultConfig.UserAgent, "user-agent", "",
+		"User agent of the HTTP requests of the CLI, to distinguish the tools and the scripts in the logs. "+
+			"Sent with http(s):// and gs:// sources, webhooks and login requests. "+
+			"The requests to the server keep the user agent of the client library, a warning is printed. "+
+			"Defaults to tigris-cli/<version>")
+	rootCmd.PersistentFlags().StringVar(&config.DefaultConfig.TokenFile, "token-file", "",
+		"Read the authentication token from the file, like mounted Kubernetes secret. "+
+			"The token is checked before running the command, if it's rejected by the server "+
+			"the file is read again, in case the token has been rotated. "+
+			"The command fails, without retrying, when the token is rejected while the command runs")
+	rootCmd.PersistentFlags().StringVar(&config.Format, "config-format", "",
+		"Format of the saved config file: yaml, json, toml. Defaults to the format of the existing config file")
+
+	// Logger is configured before command line is parsed,
+	// reconfigure it to apply the --color flag
+	cobra.OnInitialize(func() {
+		util.Fatal(util.ValidateColor(), "color")
+		util.Fatal(util.ValidateLogFormat(config.DefaultConfig.Log.Format), "log format")
+		util.Fatal(util.CheckUserAgent(), "user agent")
+		util.Fatal(config.ValidateFormat(), "config format")
+
+		_, err := config.ReadTokenFile()
+		util.Fatal(err, "read token file")
+		util.LogConfigure(&config.DefaultConfig.Log)
+	})
 
 	rootCmd.AddCommand(search.RootCmd)
 	rootCmd.AddCommand(dbCmd)
@@ -50,7 +81,9 @@ func init() {
 
 func addProjectFlag(cmd *cobra.Command) {
 	cmd.PersistentFlags().StringVarP(&config.DefaultConfig.Project,
-		"project", "p", "", "Specifies project: --project=my_proj1")
+		"project", "p", "",
+		"Specifies project: --project=my_proj1. Defaults to TIGRIS_PROJECT or "+config.ProjectEnv+
+			" environment variable or project in the config file")
 
 	if cmd != branchCmd {
 		cmd.PersistentFlags().StringVar(&config.DefaultConfig.Branch,
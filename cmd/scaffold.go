@@ -43,15 +43,32 @@ var (
 
 	fromExample string
 
+	outFile        string
+	force          bool
+	allCollections bool
+
 	langMap = map[string]string{
 		"ts":         "ts",
 		"golang":     "go",
 		"typescript": "ts",
 		"go":         "go",
 		"java":       "java",
+		"php":        "php",
+		"rust":       "rust",
+		"rs":         "rust",
 	}
 
 	ErrUnknownExample = fmt.Errorf("unknown example name")
+
+	ErrAllCollectionsWithNames = fmt.Errorf("--all-collections cannot be used along with the collection names")
+	ErrNoModelsCollections     = fmt.Errorf("specify the collection names or --all-collections")
+	ErrModelsWithApplication   = fmt.Errorf(
+		"--framework, --example and --schema-template cannot be used along with the project and collection arguments")
+
+	// getCollectionsFn and describeCollectionsFn read the live schemas of the collections,
+	// replaced in tests.
+	getCollectionsFn      = getCollections
+	describeCollectionsFn = describeCollections
 )
 
 func getCollections(ctx context.Context, dbName string, format string) ([]*api.CollectionDescription, error) {
@@ -65,6 +82,26 @@ func getCollections(ctx context.Context, dbName string, format string) ([]*api.C
 	return resp.Collections, nil
 }
 
+// describeCollections returns the live schemas of the given collections of the project.
+func describeCollections(ctx context.Context, dbName string, names []string, format string,
+) ([]*api.CollectionDescription, error) {
+	colls := make([]*api.CollectionDescription, 0, len(names))
+
+	for _, name := range names {
+		log.Debug().Str("project", dbName).Str("collection", name).Msg("describe collection")
+
+		resp, err := client.Get().UseDatabase(dbName).DescribeCollection(ctx, name,
+			&driver.DescribeCollectionOptions{SchemaFormat: format})
+		if err != nil {
+			return nil, util.Error(err, "describe collection: %s", name)
+		}
+
+		colls = append(colls, &api.CollectionDescription{Collection: resp.Collection, Schema: resp.Schema})
+	}
+
+	return colls, nil
+}
+
 func getCollectionNames(colls []*api.CollectionDescription) string {
 	var s string
 
@@ -126,7 +163,7 @@ func scaffoldFramework(ctx context.Context, templatesPath string, examplesPath s
 	util.Infof("Language '%s'", language)
 	util.Infof("Output directory '%s'", filepath.Join(outDir, pName))
 
-	colls, err := getCollections(ctx, pName, language+",json")
+	colls, err := getCollections(ctx, pName, scaffold.SchemaFormat(language))
 	if err != nil {
 		return err
 	}
@@ -155,6 +192,7 @@ func scaffoldFramework(ctx context.Context, templatesPath string, examplesPath s
 		URL:             config.DefaultConfig.URL,
 		ClientID:        clientID,
 		ClientSecret:    clientSecret,
+		Force:           force,
 	})
 
 	return nil
@@ -198,9 +236,16 @@ func scaffoldProject(ctx context.Context) error {
 }
 
 var scaffoldProjectCmd = &cobra.Command{
-	Use:   "scaffold",
-	Short: "Scaffold new application for project",
-	Args:  cobra.NoArgs,
+	Use:   "scaffold [{project} {collection}...]",
+	Short: "Scaffold new application for project or generate models of the collections",
+	Long: `Scaffolds new application for the project.
+
+When the project and the collections are given in the arguments,
+generates the models of the collections in the --language instead,
+from the live schemas of the collections, read from the server.
+Use --all-collections instead of the collection names
+to generate the models of all the collections of the project.
+The models are written to the --output-file, which is the standard output by default.`,
 	Example: fmt.Sprintf(`
 	# Create Tigris project with no collections
 	%[1]s %[2]s 
@@ -213,12 +258,122 @@ var scaffoldProjectCmd = &cobra.Command{
 
 	# Both bootstrap collections and scaffold Express application
 	%[1]s %[2]s --schema-template todo --framework=express
+
+	# Output Go model of the users collection of the project
+	%[1]s scaffold proj_name users --language=go
+
+	# Output Rust models of all the collections of the project
+	%[1]s scaffold proj_name --all-collections --language=rust
 `, rootCmd.Root().Name(), "scaffold --project=proj_name"),
 	Run: func(cmd *cobra.Command, args []string) {
+		if len(args) > 0 {
+			if framework != "" || fromExample != "" || schemaTemplate != "" {
+				util.Fatal(ErrModelsWithApplication, "scaffold")
+			}
+
+			runScaffoldModels(cmd, args)
+
+			return
+		}
+
 		login.Ensure(cmd.Context(), scaffoldProject)
 	},
 }
 
+var scaffoldModelsCmd = &cobra.Command{
+	Use:   "models [project] [collection...]",
+	Short: "Generates models of the project collections",
+	Long: `Generates the models of the existing collections in the language,
+from the live schemas of the collections, read from the server.
+The project is taken from the first argument or from the --project flag.
+The models of the collections given in the arguments are generated,
+or of all the collections of the project, when --all-collections is specified.
+The models are written to the --output-file,
+which is the standard output when it's "-" (default).
+Existing file is not overwritten, unless --force is specified.`,
+	Example: fmt.Sprintf(`
+  # Output Go models of all the collections of the project to the standard output
+  %[1]s scaffold models --project=proj_name --all-collections --language=go
+
+  # Output TypeScript model of the users collection of the project
+  %[1]s scaffold models proj_name users --language=typescript
+
+  # Write Java models of the collections to the file, overwriting existing one
+  %[1]s scaffold models proj_name --all-collections --language=java --output-file=Models.java --force
+`, rootCmd.Root().Name()),
+	Run: func(cmd *cobra.Command, args []string) {
+		runScaffoldModels(cmd, args)
+	},
+}
+
+// modelsCollections returns the live schemas of the collections to generate the models of.
+// Only the given collections are described, when the names are given,
+// otherwise --all-collections is required to describe all the collections of the project.
+func modelsCollections(ctx context.Context, pName string, names []string, lang string,
+) ([]*api.CollectionDescription, error) {
+	switch {
+	case allCollections && len(names) > 0:
+		return nil, ErrAllCollectionsWithNames
+	case len(names) > 0:
+		return describeCollectionsFn(ctx, pName, names, scaffold.SchemaFormat(lang))
+	case allCollections:
+		return getCollectionsFn(ctx, pName, scaffold.SchemaFormat(lang))
+	}
+
+	return nil, ErrNoModelsCollections
+}
+
+// scaffoldModels writes the models of the collections of the project to the --output-file.
+func scaffoldModels(ctx context.Context, pName string, names []string, lang string) error {
+	colls, err := modelsCollections(ctx, pName, names, lang)
+	if err != nil {
+		return err
+	}
+
+	w, err := util.CreateOutput(outFile, force)
+	if err != nil {
+		return util.Error(err, "create output file")
+	}
+
+	if err = scaffold.Models(w, colls, lang); err != nil {
+		_ = w.Close()
+		return util.Error(err, "write models")
+	}
+
+	return util.Error(w.Close(), "close output file")
+}
+
+// runScaffoldModels generates the models, the project is taken from the first argument
+// or from the --project flag, the rest of the arguments are the names of the collections.
+func runScaffoldModels(cmd *cobra.Command, args []string) {
+	lang := langMap[strings.ToLower(language)]
+	if lang == "" {
+		util.Fatal(scaffold.ErrUnsupportedFormat, "unsupported language: %s", language)
+	}
+
+	var names []string
+	if len(args) > 1 {
+		names = args[1:]
+	}
+
+	if allCollections && len(names) > 0 {
+		util.Fatal(ErrAllCollectionsWithNames, "scaffold models")
+	}
+
+	if len(names) == 0 && !allCollections {
+		util.Fatal(ErrNoModelsCollections, "scaffold models")
+	}
+
+	login.Ensure(cmd.Context(), func(ctx context.Context) error {
+		pName := config.GetProjectName()
+		if len(args) > 0 {
+			pName = args[0]
+		}
+
+		return scaffoldModels(ctx, pName, names, lang)
+	})
+}
+
 func addScaffoldProjectFlags(cmd *cobra.Command) {
 	cmd.PersistentFlags().StringVarP(&outDir, "output-directory", "o", ".",
 		"Directory where to create the scaffolded application. The project name will be appended to this directory path")
@@ -232,17 +387,34 @@ func addScaffoldProjectFlags(cmd *cobra.Command) {
 	cmd.Flags().StringVarP(&schemaTemplate, "schema-template", "s", "",
 		"Database schema template to use")
 	cmd.Flags().StringVarP(&language, "language", "l", "typescript",
-		"Language to Scaffold the project in. Possible values are: TypeScript, Golang, Java")
+		"Language to Scaffold the project in. Possible values are: TypeScript, Golang, Java, PHP, Rust")
 	cmd.Flags().StringVarP(&framework, "framework", "f", "",
 		"Framework used for scaffolding")
 
 	cmd.Flags().StringSliceVarP(&components, "components", "c", []string{},
 		"Components of the project")
+	cmd.Flags().BoolVar(&force, "force", false,
+		"Scaffold into existing output directory, overwriting the files")
 }
 
 func init() {
 	addProjectFlag(scaffoldProjectCmd)
 	addScaffoldProjectFlags(scaffoldProjectCmd)
 
+	scaffoldProjectCmd.Flags().StringVar(&outFile, "output-file", util.StdoutName,
+		"File to write the models to, when the project and collections are given, - stands for the standard output")
+	scaffoldProjectCmd.Flags().BoolVar(&allCollections, "all-collections", false,
+		"Generate models of all the collections of the project given in the argument")
+
+	scaffoldModelsCmd.Flags().StringVarP(&language, "language", "l", "typescript",
+		"Language of the models. Possible values are: TypeScript, Golang, Java, PHP, Rust")
+	scaffoldModelsCmd.Flags().StringVar(&outFile, "output-file", util.StdoutName,
+		"File to write the models to, - stands for the standard output")
+	scaffoldModelsCmd.Flags().BoolVar(&force, "force", false,
+		"Overwrite existing output file")
+	scaffoldModelsCmd.Flags().BoolVar(&allCollections, "all-collections", false,
+		"Generate models of all the collections of the project")
+	scaffoldProjectCmd.AddCommand(scaffoldModelsCmd)
+
 	rootCmd.AddCommand(scaffoldProjectCmd)
 }
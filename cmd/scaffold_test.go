@@ -0,0 +1,108 @@
+// Copyright 2022-2023 Tigris Data, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	api "github.com/tigrisdata/tigris-client-go/api/server/v1"
+)
+
+func TestScaffoldModels(t *testing.T) {
+	schemas := map[string]string{
+		"users":  `{"title":"users","properties":{"id":{"type":"integer"}},"primary_key":["id"]}`,
+		"orders": `{"title":"orders","properties":{"id":{"type":"integer"}},"primary_key":["id"]}`,
+	}
+
+	// the server returns the schema in each of the requested formats
+	describe := func(name string) *api.CollectionDescription {
+		b, err := json.Marshal(map[string]string{"json": schemas[name]})
+		require.NoError(t, err)
+
+		return &api.CollectionDescription{Collection: name, Schema: b}
+	}
+
+	savedGet, savedDescribe := getCollectionsFn, describeCollectionsFn
+
+	getCollectionsFn = func(ctx context.Context, project string, format string) ([]*api.CollectionDescription, error) {
+		assert.Equal(t, "db1", project)
+
+		return []*api.CollectionDescription{describe("orders"), describe("users")}, nil
+	}
+
+	describeCollectionsFn = func(ctx context.Context, project string, names []string, format string,
+	) ([]*api.CollectionDescription, error) {
+		assert.Equal(t, "db1", project)
+
+		colls := make([]*api.CollectionDescription, 0, len(names))
+		for _, n := range names {
+			colls = append(colls, describe(n))
+		}
+
+		return colls, nil
+	}
+
+	defer func() {
+		getCollectionsFn, describeCollectionsFn = savedGet, savedDescribe
+		allCollections = false
+		outFile = ""
+		force = false
+	}()
+
+	cases := []struct {
+		name   string
+		names  []string
+		all    bool
+		models map[string]bool
+		err    error
+	}{
+		{"collection", []string{"users"}, false, map[string]bool{"User": true}, nil},
+		{"all collections", nil, true, map[string]bool{"Order": true, "User": true}, nil},
+		{"no collections", nil, false, nil, ErrNoModelsCollections},
+		{"collections with all", []string{"users"}, true, nil, ErrAllCollectionsWithNames},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			allCollections = c.all
+			outFile = filepath.Join(t.TempDir(), "models.rs")
+
+			err := scaffoldModels(context.Background(), "db1", c.names, "rust")
+			if c.err != nil {
+				require.ErrorIs(t, err, c.err)
+				return
+			}
+
+			require.NoError(t, err)
+
+			b, err := os.ReadFile(outFile)
+			require.NoError(t, err)
+
+			for _, m := range []string{"User", "Order"} {
+				if c.models[m] {
+					assert.Contains(t, string(b), "pub struct "+m+" {")
+				} else {
+					assert.NotContains(t, string(b), "pub struct "+m+" {")
+				}
+			}
+		})
+	}
+}
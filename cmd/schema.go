@@ -0,0 +1,134 @@
+// Copyright 2022-2023 Tigris Data, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/spf13/cobra"
+	"github.com/tigrisdata/tigris-cli/client"
+	"github.com/tigrisdata/tigris-cli/login"
+	"github.com/tigrisdata/tigris-cli/schema"
+	"github.com/tigrisdata/tigris-cli/util"
+	cschema "github.com/tigrisdata/tigris-client-go/schema"
+)
+
+const (
+	schemaFormatJSONSchema = "json-schema"
+	schemaFormatTigris     = "tigris"
+)
+
+var (
+	ErrUnsupportedSchemaFormat = fmt.Errorf("unsupported schema format. supported formats: %s, %s",
+		schemaFormatJSONSchema, schemaFormatTigris)
+
+	schemaExportFormat string
+)
+
+var schemaCmd = &cobra.Command{
+	Use:   "schema",
+	Short: "Schema related commands",
+}
+
+var schemaExportCmd = &cobra.Command{
+	Use:   "export {collection}",
+	Short: "Exports collection schema",
+	Long: `Exports collection schema in the requested format.
+
+Supported formats:
+  json-schema - standard JSON Schema (draft 2020-12) document,
+                which can be used with external validators and tooling
+  tigris      - native Tigris collection schema`,
+	Example: fmt.Sprintf(`
+  %[1]s schema export --project=myproj users
+  %[1]s schema export --project=myproj users --format=json-schema >users.schema.json
+`, rootCmd.Root().Name()),
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		if schemaExportFormat != schemaFormatJSONSchema && schemaExportFormat != schemaFormatTigris {
+			util.Fatal(ErrUnsupportedSchemaFormat, "schema export")
+		}
+
+		login.Ensure(cmd.Context(), func(ctx context.Context) error {
+			resp, err := client.GetDB().DescribeCollection(ctx, args[0])
+			if err != nil {
+				return util.Error(err, "describe collection")
+			}
+
+			if schemaExportFormat == schemaFormatTigris {
+				err = util.PrettyJSON(json.RawMessage(resp.Schema))
+				util.Fatal(err, "schema export marshal")
+
+				return nil
+			}
+
+			var sch cschema.Schema
+
+			err = json.Unmarshal(resp.Schema, &sch)
+			util.Fatal(err, "unmarshal collection schema")
+
+			err = util.PrettyJSON(schema.ToJSONSchema(&sch))
+			util.Fatal(err, "schema export marshal")
+
+			return nil
+		})
+	},
+}
+
+var schemaTemplateCmd = &cobra.Command{
+	Use:   "template {collection}",
+	Short: "Outputs sample document matching collection schema",
+	Long: `Outputs sample JSON document matching the collection schema.
+
+Fields are filled with their default values, if set in the schema,
+or with placeholder values of the field type. Arrays get one example element.
+The output can be edited and used with insert or import commands.`,
+	Example: fmt.Sprintf(`
+  %[1]s schema template --project=myproj users >user.json
+`, rootCmd.Root().Name()),
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		login.Ensure(cmd.Context(), func(ctx context.Context) error {
+			resp, err := client.GetDB().DescribeCollection(ctx, args[0])
+			if err != nil {
+				return util.Error(err, "describe collection")
+			}
+
+			var sch cschema.Schema
+
+			err = json.Unmarshal(resp.Schema, &sch)
+			util.Fatal(err, "unmarshal collection schema")
+
+			err = util.PrettyJSON(schema.Template(&sch))
+			util.Fatal(err, "schema template marshal")
+
+			return nil
+		})
+	},
+}
+
+func init() {
+	schemaExportCmd.Flags().StringVar(&schemaExportFormat, "format", schemaFormatJSONSchema,
+		"Output schema format: json-schema, tigris")
+
+	addProjectFlag(schemaExportCmd)
+	schemaCmd.AddCommand(schemaExportCmd)
+
+	addProjectFlag(schemaTemplateCmd)
+	schemaCmd.AddCommand(schemaTemplateCmd)
+	rootCmd.AddCommand(schemaCmd)
+}
@@ -0,0 +1,62 @@
+// Copyright 2022-2023 Tigris Data, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+	"github.com/tigrisdata/tigris-cli/util"
+)
+
+var (
+	// Schemaless disables schema inference and collection creation,
+	// the documents are inserted as is.
+	Schemaless bool
+
+	ErrSchemalessIgnored  = fmt.Errorf("option is ignored with --schemaless")
+	ErrSchemalessNoHeader = fmt.Errorf("--schemaless can't be used with --csv-no-header")
+
+	// schemaFlags are the flags which have no effect with --schemaless.
+	schemaFlags = []string{
+		"inference-depth", "primary-key", "autogenerate", "secondary-index", "schema-file",
+		"detect-byte-arrays", "detect-uuids", "detect-times", "detect-integers", "detect-geo",
+		"print-schema", "max-fields", "inference-log", "no-create-collection", "ttl", "ttl-field",
+		"collection-description", "detect-enums", "enum-threshold",
+		"infer-from", "update-schema", "require-compat", "field-type-report",
+		"schema-on-create-only", "byte-encoding",
+	}
+)
+
+// checkSchemaless warns about the schema related flags combined with --schemaless.
+func checkSchemaless(cmd *cobra.Command) error {
+	if !Schemaless {
+		return nil
+	}
+
+	if CSVNoHeader {
+		return ErrSchemalessNoHeader
+	}
+
+	for _, name := range schemaFlags {
+		if f := cmd.Flags().Lookup(name); f != nil && f.Changed {
+			if err := util.Warning(fmt.Errorf("%w: --%s", ErrSchemalessIgnored, name)); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
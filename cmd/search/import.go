@@ -18,9 +18,10 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
-	"unsafe"
 
+	"github.com/google/uuid"
 	"github.com/rs/zerolog/log"
 	"github.com/spf13/cobra"
 	"github.com/tigrisdata/tigris-cli/client"
@@ -31,7 +32,6 @@ import (
 	"github.com/tigrisdata/tigris-cli/util"
 	api "github.com/tigrisdata/tigris-client-go/api/server/v1"
 	"github.com/tigrisdata/tigris-client-go/driver"
-	cschema "github.com/tigrisdata/tigris-client-go/schema"
 )
 
 var (
@@ -41,8 +41,22 @@ var (
 	AutoGenerate   []string
 	UpdateSchema   bool
 	Append         bool
+	IDField        string
+	OverwriteID    bool
+	SchemaFile     string
 
-	BatchSize int32 = 100
+	// InferFrom is the file with the sample documents, the schema is inferred from,
+	// instead of the imported documents.
+	InferFrom string
+
+	// FacetFields, SearchableFields and SortFields are the fields marked
+	// as facetable, searchable and sortable in the inferred schema of the index.
+	FacetFields      []string
+	SearchableFields []string
+	SortFields       []string
+
+	// SchemaFreezeAfter stops schema inference after the number of documents.
+	SchemaFreezeAfter int64
 
 	CleanUpNULLs = true
 
@@ -51,34 +65,394 @@ var (
 	CSVTrimLeadingSpace bool
 	CSVNoHeader         bool
 
-	sch        cschema.Schema // Accumulate inferred schema across batches
-	prevSchema []byte
-
 	ErrIndexShouldExist = fmt.Errorf("index should exist to import CSV with no field names")
 	ErrNoAppend         = fmt.Errorf(
 		"index exists. use --append if you need to add documents to existing collection")
+	ErrUnsupportedIDType = fmt.Errorf("id field should be string or number")
+	ErrIndexNotFound     = fmt.Errorf("index doesn't exist. remove --no-create-index to create it")
+	ErrDocumentExists    = fmt.Errorf("document with the id already exists in the index. " +
+		"use --on-conflict=skip or --on-conflict=replace to import it")
+	ErrDocumentFailed = fmt.Errorf("document import failed")
+
+	ErrInferFromSchemaFile = fmt.Errorf("--infer-from can't be used with --schema-file")
+	ErrEmptySample         = fmt.Errorf("no documents in the sample file")
 )
 
-func evolveIdxSchema(ctx context.Context, coll string, docs []json.RawMessage) error {
+type getIndexFunc func(ctx context.Context, name string) (*driver.IndexInfo, error)
+
+// indexSchema returns whether the index exists and its schema.
+// Only NOT_FOUND error means that the index doesn't exist,
+// any other error, like permission denied, is returned to the caller.
+func indexSchema(ctx context.Context, name string, getIndex getIndexFunc) (bool, []byte, error) {
+	resp, err := getIndex(ctx, name)
+	if err == nil {
+		return true, resp.Schema, nil
+	}
+
+	// FIXME: errors.As(err, &ep) doesn't work
+	//nolint:golint,errorlint
+	if ep, ok := err.(*driver.Error); ok && ep.Code == api.Code_NOT_FOUND {
+		return false, nil, nil
+	}
+
+	return false, nil, err
+}
+
+// indexCacheKey identifies the schema of the index in the schema cache.
+func indexCacheKey(name string) []string {
+	return []string{"index", config.DefaultConfig.URL, config.GetProjectName(), name}
+}
+
+// cachedIndexSchema returns the schema of the index from the schema cache, when it's enabled,
+// or from the server.
+func cachedIndexSchema(ctx context.Context, name string, getIndex getIndexFunc) (bool, []byte, error) {
+	if b := schema.CacheGet(indexCacheKey(name)...); b != nil {
+		return true, b, nil
+	}
+
+	exists, sch, err := indexSchema(ctx, name, getIndex)
+	if exists {
+		schema.CachePut(sch, indexCacheKey(name)...)
+	}
+
+	return exists, sch, err
+}
+
+// setDocID copies the value of the IDField to the "id" field of the document,
+// which is used by the search as document identity.
+// Existing "id" of the document is kept, unless OverwriteID is set.
+// Documents missing the IDField get a UUID derived from the document content,
+// so importing the same documents again produces the same ids.
+// The order of the document fields is preserved, new "id" field goes last.
+func setDocID(doc json.RawMessage) (json.RawMessage, error) {
+	v, err := util.DecodeOrdered(doc)
+	if err != nil {
+		return nil, util.Error(err, "unmarshal doc to set id")
+	}
+
+	o, ok := v.(*util.OrderedObject)
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", util.ErrUnexpectedToken, doc)
+	}
+
+	if id, ok := o.Values["id"]; ok && id != nil && !OverwriteID {
+		return doc, nil
+	}
+
+	switch v := o.Values[IDField].(type) {
+	case nil:
+		b, err := json.Marshal(o)
+		if err != nil {
+			return nil, util.Error(err, "marshal doc to generate id")
+		}
+
+		o.Set("id", uuid.NewSHA1(uuid.NameSpaceOID, b).String())
+	case string:
+		o.Set("id", v)
+	case json.Number:
+		o.Set("id", v.String())
+	default:
+		return nil, fmt.Errorf("%w: %s", ErrUnsupportedIDType, IDField)
+	}
+
+	res, err := json.Marshal(o)
+
+	return res, util.Error(err, "marshal doc after setting id")
+}
+
+// indexImporter holds the state of a single index import run.
+type indexImporter struct {
+	name string
+
+	sch          *schema.Accumulator // Accumulate inferred schema across batches
+	prevSchema   []byte
+	serverSchema []byte // Schema of the existing index
+
+	found       bool
+	fixedSchema bool  // Schema is provided by --schema-file, inference is disabled
+	inferred    int64 // Number of documents the schema has been inferred from
+
+	inserted        int64
+	skippedExisting int64
+}
+
+func newIndexImporter(name string) *indexImporter {
+	imp := &indexImporter{name: name, sch: schema.NewAccumulator()}
+
+	imp.sch.SetSearchOptions(&schema.SearchOptions{
+		Facet:      FacetFields,
+		Searchable: SearchableFields,
+		Sort:       SortFields,
+	})
+
+	return imp
+}
+
+// inferenceDocs returns the documents of the batch, the schema should be inferred from.
+// With SchemaFreezeAfter, only the documents up to the limit are returned,
+// and the schema is frozen once the limit is reached.
+func (imp *indexImporter) inferenceDocs(docs []json.RawMessage) []json.RawMessage {
+	if SchemaFreezeAfter <= 0 {
+		return docs
+	}
+
+	left := SchemaFreezeAfter - imp.inferred
+	if left <= 0 {
+		return nil
+	}
+
+	if int64(len(docs)) > left {
+		docs = docs[:left]
+	}
+
+	imp.inferred += int64(len(docs))
+
+	if imp.inferred == SchemaFreezeAfter {
+		util.Infof("Schema is frozen after %d document(s)", imp.inferred)
+	}
+
+	return docs
+}
+
+func (imp *indexImporter) evolveIdxSchema(ctx context.Context, docs []json.RawMessage) error {
 	// Allow to reduce inference depth in the case of huge batches
 	id := len(docs)
 	if InferenceDepth > 0 {
 		id = int(InferenceDepth)
 	}
 
-	err := schema.Infer(&sch, coll, docs, PrimaryKey, AutoGenerate, id)
+	b, err := imp.sch.Infer(imp.name, docs, PrimaryKey, AutoGenerate, id)
 	util.Fatal(err, "infer schema")
 
-	b, err := json.Marshal(sch)
-	util.Fatal(err, "marshal schema: %s", string(b))
+	if bytes.Equal(b, imp.prevSchema) {
+		log.Debug().Msg("schema is not changed, skipping index update")
+		return nil
+	}
+
+	if err = imp.checkCompat(b); err != nil {
+		return err
+	}
+
+	err = client.GetSearch().CreateOrUpdateIndex(ctx, imp.name, b)
+	if err != nil {
+		schema.CacheInvalidate(indexCacheKey(imp.name)...)
+		return util.Error(err, "create or update index")
+	}
+
+	schema.CachePut(b, indexCacheKey(imp.name)...)
+
+	imp.prevSchema = b
+
+	return util.Error(schema.PrintChanged(b), "print schema")
+}
+
+// checkCompat checks the compatibility of the schema change
+// against the last schema sent to the server or the schema of the existing index.
+func (imp *indexImporter) checkCompat(b []byte) error {
+	base := imp.prevSchema
+	if base == nil {
+		base = imp.serverSchema
+	}
+
+	return util.Error(schema.CheckCompat(imp.name, base, b), "schema compatibility")
+}
+
+// createFromFile creates or updates the index with the schema from the file
+// and disables inference.
+func (imp *indexImporter) createFromFile(ctx context.Context, name string) error {
+	b, err := schema.ReadFile(name, imp.name)
+	util.Fatal(err, "read schema file: %s", name)
+
+	if err = imp.checkCompat(b); err != nil {
+		return err
+	}
+
+	err = client.GetSearch().CreateOrUpdateIndex(ctx, imp.name, b)
+	if err != nil {
+		schema.CacheInvalidate(indexCacheKey(imp.name)...)
+		return util.Error(err, "create or update index from schema file: %s", name)
+	}
+
+	schema.CachePut(b, indexCacheKey(imp.name)...)
+
+	if schema.CoerceValues {
+		err = imp.sch.Load(b)
+		util.Fatal(err, "unmarshal schema file: %s", name)
+	}
+
+	imp.prevSchema = b
+	imp.fixedSchema = true
+
+	return util.Error(schema.PrintChanged(b), "print schema")
+}
+
+// inferFromSample creates or updates the index with the schema inferred
+// from the documents of the sample file. Inference from the imported documents
+// is disabled, unless UpdateSchema is set.
+func (imp *indexImporter) inferFromSample(ctx context.Context, name string) error {
+	var b []byte
+
+	err := iterate.SourceInput(ctx, nil, name, func(_ context.Context, _ []string, docs []json.RawMessage) error {
+		id := len(docs)
+		if InferenceDepth > 0 {
+			id = int(InferenceDepth)
+		}
+
+		var err error
+
+		b, err = imp.sch.Infer(imp.name, docs, PrimaryKey, AutoGenerate, id)
+
+		return util.Error(err, "infer schema from sample file: %s", name)
+	})
+	if err != nil {
+		return err
+	}
+
+	if b == nil {
+		return util.Error(ErrEmptySample, "infer schema from sample file: %s", name)
+	}
+
+	if err = imp.checkCompat(b); err != nil {
+		return err
+	}
+
+	err = client.GetSearch().CreateOrUpdateIndex(ctx, imp.name, b)
+	if err != nil {
+		schema.CacheInvalidate(indexCacheKey(imp.name)...)
+		return util.Error(err, "create or update index from sample file: %s", name)
+	}
+
+	schema.CachePut(b, indexCacheKey(imp.name)...)
+
+	imp.prevSchema = b
+	imp.fixedSchema = !UpdateSchema
+
+	return util.Error(schema.PrintChanged(b), "print schema")
+}
+
+func isConflict(e *api.Error) bool {
+	return e.Code == api.Code_ALREADY_EXISTS || e.Code == api.Code_CONFLICT
+}
+
+// write writes the batch of documents according to the conflict policy.
+// With util.OnConflictReplace, existing documents are replaced.
+// With util.OnConflictSkip, documents with existing ids are counted and skipped,
+// otherwise they fail the import.
+// Documents failed for other reasons fail the import, or are written
+// to the error file with --skip-errors.
+// Only the documents actually written are counted as inserted.
+func (imp *indexImporter) write(ctx context.Context, docs []json.RawMessage) error {
+	var (
+		statuses []*driver.DocStatus
+		err      error
+	)
+
+	if util.OnConflict == util.OnConflictReplace {
+		statuses, err = client.GetSearch().CreateOrReplace(ctx, imp.name, client.Documents(docs))
+	} else {
+		statuses, err = client.GetSearch().Create(ctx, imp.name, client.Documents(docs))
+	}
+
+	if err != nil {
+		return err
+	}
+
+	var skipped int64
+
+	imported := make([]json.RawMessage, 0, len(docs))
+
+	for i, st := range statuses {
+		if st.GetError() == nil {
+			imported = append(imported, docs[i])
+			continue
+		}
+
+		if err = imp.docError(docs[i], st); err != nil {
+			break
+		}
+
+		if isConflict(st.GetError()) {
+			skipped++
+		}
+	}
+
+	imp.skippedExisting += skipped
+	imp.inserted += int64(len(imported))
+
+	util.Fatal(iterate.TeeDocs(imported), "tee")
+
+	return err
+}
+
+// docError handles the document, the server failed to write.
+// Conflicts are skipped with util.OnConflictSkip, other errors are skipped with --skip-errors.
+func (imp *indexImporter) docError(doc json.RawMessage, st *driver.DocStatus) error {
+	if isConflict(st.GetError()) {
+		if util.OnConflict != util.OnConflictSkip {
+			return fmt.Errorf("%w: %s", ErrDocumentExists, st.GetId())
+		}
 
-	if bytes.Equal(b, prevSchema) {
 		return nil
 	}
 
-	err = client.GetSearch().CreateOrUpdateIndex(ctx, coll, b)
+	err := fmt.Errorf("%w: %s: %s", ErrDocumentFailed, st.GetId(), st.GetError().GetMessage())
+	if !iterate.SkipErrors {
+		return err
+	}
 
-	return util.Error(err, "create or update index")
+	return iterate.SkipDoc(doc, err)
+}
+
+// coerceDocs converts the values of the documents to the types of the known fields of the index.
+func (imp *indexImporter) coerceDocs(docs []json.RawMessage) ([]json.RawMessage, error) {
+	var skip func(json.RawMessage, error) error
+	if iterate.SkipErrors {
+		skip = iterate.SkipDoc
+	}
+
+	docs, err := imp.sch.CoerceDocs(docs, skip)
+
+	return docs, util.Error(err, "coerce values")
+}
+
+func (imp *indexImporter) importDocs(ctx context.Context, docs []json.RawMessage) error {
+	docs, err := imp.coerceDocs(docs)
+	if err != nil || len(docs) == 0 {
+		return err
+	}
+
+	if IDField != "" {
+		for k := range docs {
+			if docs[k], err = setDocID(docs[k]); err != nil {
+				return err
+			}
+		}
+	}
+
+	if !imp.fixedSchema && (UpdateSchema || (!imp.found && !NoCreate)) {
+		if idocs := imp.inferenceDocs(docs); len(idocs) > 0 {
+			if err := imp.evolveIdxSchema(ctx, idocs); err != nil {
+				return err
+			}
+		}
+	}
+
+	err = imp.write(ctx, docs)
+	if err == nil || errors.Is(err, ErrDocumentExists) || errors.Is(err, ErrDocumentFailed) {
+		return util.Error(err, "import documents")
+	}
+
+	if CleanUpNULLs {
+		for k := range docs {
+			docs[k] = util.CleanupNULLValues(docs[k])
+		}
+	}
+
+	err = imp.write(ctx, docs)
+
+	log.Debug().Interface("docs", docs).Msg("import")
+
+	return util.Error(err, "import documents (after schema update")
 }
 
 var importCmd = &cobra.Command{
@@ -86,6 +460,54 @@ var importCmd = &cobra.Command{
 	Short: "Import documents into search index",
 	Long: `Imports documents into the search index.
 Input is a stream or array of JSON documents to import.
+
+The "id" field of the document is used as the document identity in the index.
+When the document doesn't have the "id" field it's generated by the server.
+Use --id-field to take the identity from another field of the document instead.
+Documents which already have the "id" field keep it, unless --overwrite-id is set.
+Documents missing the --id-field get the id derived from their content,
+so importing the same documents again doesn't create duplicates.
+
+Documents with the id which already exists in the index are handled
+according to --on-conflict:
+  * error - fail the import (default)
+  * skip - leave existing documents untouched
+  * replace - replace existing documents with the imported ones
+
+With --schema-cache the schema of the existing index is cached on disk
+for --schema-cache-ttl, to skip fetching it on the repeated imports.
+The cached schema is replaced when the import evolves the schema of the index.
+
+With --coerce-values the values of the documents are converted to the types of the fields
+of the index schema, like "42" to 42, see "tigris import --help" for the details.
+
+With --where only the documents matching the predicate are imported,
+see "tigris import --help" for the syntax of the predicate.
+
+With --watermark-field and --state-file only the documents with the value of the field
+greater than the one imported by the previous run are imported,
+see "tigris import --help" for the assumptions about the order of the values.
+
+When the exact schema is known, it can be provided with --schema-file.
+In this case schema inference is disabled and the documents are validated by the server.
+
+The fields of the inferred schema are marked as facetable, searchable and sortable
+with --facet, --searchable and --sort. The fields should exist in the inferred schema,
+otherwise the import fails. The options are not applied to the schema from --schema-file.
+
+With --require-compat the schema change is classified before it's applied to the existing index,
+see "tigris import --help" for the details.
+
+With --infer-from=sample.jsonl the schema is inferred from the representative sample file
+and the index is created or updated with it before the import.
+The imported documents are not inferred from, unless --update-schema is set.
+
+With --schema-freeze-after=N the schema is inferred from the first N documents only,
+then it's frozen, so noisy data doesn't keep changing the types of the fields
+and the index is not updated anymore. Documents not matching the frozen schema
+are rejected by the server. While --inference-depth limits the number of documents
+inspected in every batch, --schema-freeze-after limits the total number of documents
+the schema is inferred from.
 `,
 	Example: fmt.Sprintf(`
   %[1]s search import --project=myproj users --create-index \
@@ -96,79 +518,188 @@ Input is a stream or array of JSON documents to import.
 `, "tigris"),
 	Args: cobra.MinimumNArgs(1),
 	Run: func(cmd *cobra.Command, args []string) {
-		name := args[0]
+		util.Fatal(schema.ValidatePrintSchema(), "print schema")
+		util.Fatal(schema.ValidateFieldTypeReport(), "field type report")
+		util.Fatal(schema.OpenInferenceLog(), "open inference log")
+		util.Fatal(util.ValidateOnConflict(), "on conflict")
+		util.Fatal(iterate.ValidateFormat(), "input format")
+		util.Fatal(iterate.ValidateInputBufferSize(), "input buffer size")
+		util.Fatal(schema.ValidateEnums(), "enums")
+		util.Fatal(util.CheckOptimize(), "optimize")
+		util.Fatal(schema.ValidateRequireCompat(), "require compat")
+
+		schema.DateTimeFields = iterate.TimestampFields()
+
+		if InferFrom != "" && SchemaFile != "" {
+			util.Fatal(ErrInferFromSchemaFile, "infer from")
+		}
+
+		imp := newIndexImporter(args[0])
 		login.Ensure(cmd.Context(), func(ctx context.Context) error {
-			resp, err := client.GetSearch().GetIndex(ctx, name)
-			found := false
-			if err == nil {
+			err := checkSearch(ctx, config.GetProjectName(), client.GetSearch().ListIndexes)
+			if err != nil {
+				return util.Error(err, "check search")
+			}
+
+			exists, sch, err := cachedIndexSchema(ctx, imp.name, client.GetSearch().GetIndex)
+			if err != nil {
+				return util.Error(err, "get index")
+			}
+
+			switch {
+			case exists:
 				if !Append {
-					util.Fatal(ErrNoAppend, "describe collection")
+					util.Fatal(ErrNoAppend, "describe index")
 				}
-				err = json.Unmarshal(resp.Schema, &sch)
-				util.Fatal(err, "unmarshal collection schema")
-				found = true
-			} else if CSVNoHeader {
+
+				err = imp.sch.Load(sch)
+				util.Fatal(err, "unmarshal index schema")
+
+				imp.found = true
+				imp.serverSchema = sch
+			case CSVNoHeader && SchemaFile == "":
 				util.Fatal(ErrIndexShouldExist, "get index")
-			} else {
-				//nolint:golint,errorlint
-				ep, ok := err.(*driver.Error)
-				if !ok || ep.Code == api.Code_NOT_FOUND && NoCreate {
-					return util.Error(err, "import documents get index")
+			case NoCreate:
+				return util.Error(ErrIndexNotFound, "get index")
+			}
+
+			if SchemaFile != "" && (imp.found || !NoCreate) {
+				if err = imp.createFromFile(ctx, SchemaFile); err != nil {
+					return err
+				}
+			}
+
+			if InferFrom != "" && (imp.found || !NoCreate) {
+				if err = imp.inferFromSample(ctx, InferFrom); err != nil {
+					return err
 				}
 			}
 
 			err = iterate.CSVConfigure(CSVDelimiter, CSVComment, CSVTrimLeadingSpace, CSVNoHeader)
 			util.Fatal(err, "csv configure")
 
-			return iterate.Input(cmd.Context(), cmd, 1, args,
+			err = iterate.Input(cmd.Context(), cmd, 1, args,
 				func(ctx context.Context, args []string, docs []json.RawMessage) error {
-					ptr := unsafe.Pointer(&docs)
-
-					if UpdateSchema || (!found && !NoCreate) {
-						if err = evolveIdxSchema(ctx, name, docs); err != nil {
-							return err
-						}
-					}
-
-					_, err = client.GetSearch().Create(ctx, name, *(*[]driver.Document)(ptr))
-					if err == nil {
-						return nil // successfully inserted batch
-					}
-
-					if CleanUpNULLs {
-						for k := range docs {
-							docs[k] = util.CleanupNULLValues(docs[k])
-						}
-					}
+					return imp.importDocs(ctx, docs)
+				})
+			if err != nil {
+				return err
+			}
 
-					_, err = client.GetSearch().Create(ctx, name, *(*[]driver.Document)(ptr))
+			if util.OnConflict == util.OnConflictSkip {
+				util.Infof("Inserted %d document(s), skipped %d existing document(s)", imp.inserted, imp.skippedExisting)
+			}
 
-					log.Debug().Interface("docs", docs).Msg("import")
+			if err = schema.PrintFinal(imp.prevSchema); err != nil {
+				return util.Error(err, "print schema")
+			}
 
-					return util.Error(err, "import documents (after schema update")
-				})
+			return util.Error(schema.PrintFieldTypes(imp.prevSchema), "print field types")
 		})
 	},
 }
 
 func addProjectFlag(cmd *cobra.Command) {
 	cmd.PersistentFlags().StringVarP(&config.DefaultConfig.Project,
-		"project", "p", "", "Specifies project: --project=my_proj1")
+		"project", "p", "",
+		"Specifies project: --project=my_proj1. Defaults to TIGRIS_PROJECT or "+config.ProjectEnv+
+			" environment variable or project in the config file")
 }
 
 func init() {
-	importCmd.Flags().Int32VarP(&BatchSize, "batch-size", "b", BatchSize, "set batch size")
+	importCmd.Flags().Int32VarP(&iterate.BatchSize, "batch-size", "b", iterate.BatchSize, "set batch size")
+	importCmd.Flags().DurationVar(&iterate.BatchTimeout, "batch-timeout", 0,
+		"Timeout of a single batch request, every batch gets its own deadline. Default: no timeout")
+	importCmd.Flags().DurationVar(&iterate.FlushInterval, "flush-interval", 0,
+		"Flush partially filled batch after the interval, so the documents of slow streams are processed without waiting for the batch to fill up")
+	importCmd.Flags().Int64Var(&iterate.MaxRequestBytes, "max-request-bytes", 0,
+		"Split batches so that a single request doesn't exceed this size in bytes. "+
+			"Useful with HTTP protocol, where very large requests may fail. Default: no limit")
+	importCmd.Flags().Int64Var(&iterate.MaxDocSize, "max-doc-size", 0,
+		"Reject the documents larger than this size in bytes, reporting their numbers in the input. "+
+			"The documents are skipped with --skip-errors, otherwise the import is aborted. Default: no limit")
+	importCmd.Flags().BoolVar(&iterate.AdaptiveRate, "adaptive-rate", false,
+		"Slow down when the server throttles the requests and speed back up when throttling clears")
 	importCmd.Flags().Int32VarP(&InferenceDepth, "inference-depth", "d", 0,
 		"Number of records in the beginning of the stream to detect field types. It's equal to batch size if not set")
 	importCmd.Flags().StringSliceVar(&AutoGenerate, "autogenerate", []string{},
 		"Comma separated list of autogenerated fields (only top level keys supported)")
 	importCmd.Flags().BoolVar(&CleanUpNULLs, "cleanup-null-values", true,
 		"Remove NULL values and empty arrays from the documents before importing")
+	importCmd.Flags().BoolVar(&iterate.EmptyStringAsNull, "empty-string-as-null", false,
+		"Convert empty string values to null before schema inference, so as they don't force the field to be a string")
+	importCmd.Flags().BoolVar(&iterate.SanitizeKeys, "sanitize-keys", false,
+		"Replace characters not allowed in field names with '_' and prefix names starting with a digit. "+
+			"Renamed fields are reported after the import. Other options refer to the sanitized names")
+	importCmd.Flags().StringVar(&iterate.ObjectMapKey, "object-map-key", "",
+		"Read the input as top-level object, mapping the keys to the documents, like {\"id1\": {...}, \"id2\": {...}}. "+
+			"The key is set to the field with this name")
+	importCmd.Flags().BoolVar(&schema.Cache, "schema-cache", false,
+		"Cache the schema of the existing index on disk, to skip fetching it on the repeated imports")
+	importCmd.Flags().DurationVar(&schema.CacheTTL, "schema-cache-ttl", schema.CacheTTL,
+		"Time the cached schema is used before it's fetched from the server again")
+	importCmd.Flags().BoolVar(&schema.CoerceValues, "coerce-values", false,
+		"Convert the values of the documents to the types of the fields of the schema, like \"42\" to 42")
+	importCmd.Flags().StringVar(&iterate.RowNumberField, "add-row-number", "",
+		"Set the field with this name to the number of the document in the input, starting from 1. "+
+			"The header of the CSV input is not counted. Helps to find the source rows of the failed documents")
+	importCmd.Flags().StringVar(&iterate.Where, "where", "",
+		"Import only the documents matching the predicate, like: status == \"active\" && age >= 18")
+	importCmd.Flags().StringSliceVar(&iterate.OnlyFields, "only-fields", []string{},
+		"Comma separated list of the fields to import, the other fields of the documents are discarded. "+
+			"Nested fields are specified using dot notation. Absent fields are omitted. "+
+			"--where and --watermark-field see the whole documents, the fields added by the CLI are kept")
+	importCmd.Flags().StringVar(&iterate.WatermarkField, "watermark-field", "",
+		"Import only the documents with the value of the field greater than the maximum value imported by the previous run, "+
+			"which is persisted in --state-file")
+	importCmd.Flags().StringVar(&iterate.StateFile, "state-file", "",
+		"File where the watermark of --watermark-field is persisted between the runs")
+	importCmd.Flags().StringVar(&iterate.NormalizeUnicode, "normalize-unicode", "",
+		"Normalize the string values of the documents to the Unicode normalization form: NFC or NFD. Default: off")
+	importCmd.Flags().BoolVar(&iterate.NormalizeUnicodeKeys, "normalize-unicode-keys", false,
+		"Normalize the field names as well, with --normalize-unicode")
+	importCmd.Flags().BoolVar(&iterate.Compact, "compact", false,
+		"Remove insignificant whitespace from the documents before importing. The order of the fields is preserved")
+	importCmd.Flags().BoolVar(&iterate.WarnDuplicateKeys, "warn-duplicate-keys", false,
+		"Report duplicate keys in the objects of the documents, only the last value of which is retained. "+
+			"The import fails on duplicate keys with --strict")
+	importCmd.Flags().BoolVar(&iterate.StrictJSON, "strict-json", false,
+		"Fail fast on the JSON documents with duplicate keys or invalid UTF-8 and on the data "+
+			"trailing the array of documents, reporting the line and the offset of the invalid document")
+	importCmd.Flags().BoolVar(&util.PreserveOrder, "preserve-order", false,
+		"Preserve the order of the fields of the documents modified by the CLI, like --cleanup-null-values or --add-field. "+
+			"The order of the fields in the documents stored by the server is not guaranteed")
+	importCmd.Flags().StringVar(&schema.RequireCompat, "require-compat", "",
+		"Refuse to apply the evolved schema, which is not compatible in the direction: backward, forward or full. "+
+			"The classification of the schema change is reported")
 	importCmd.Flags().BoolVar(&UpdateSchema, "update-schema", false,
-		"Update index schema from the new documents")
+		"Update index schema from the new documents. "+
+			"With --infer-from, continue to update the schema from the imported documents after the sample")
+	importCmd.Flags().StringVar(&InferFrom, "infer-from", "",
+		"Infer the schema from the documents of the sample file, instead of the imported documents, "+
+			"and create or update the index with it before the import")
+	importCmd.Flags().StringSliceVar(&FacetFields, "facet", []string{},
+		"Mark the fields as facetable in the inferred schema of the index. "+
+			"Nested fields are specified using dot notation: --facet=address.city")
+	importCmd.Flags().StringSliceVar(&SearchableFields, "searchable", []string{},
+		"Mark the fields as searchable in the inferred schema of the index")
+	importCmd.Flags().StringSliceVar(&SortFields, "sort", []string{},
+		"Mark the fields as sortable in the inferred schema of the index")
+	importCmd.Flags().Int64Var(&SchemaFreezeAfter, "schema-freeze-after", 0,
+		"Stop schema inference after the number of documents, so the schema and the index are not updated anymore")
+	importCmd.Flags().StringVar(&SchemaFile, "schema-file", "",
+		"Create or update the index with the schema from the file and disable schema inference")
+	importCmd.Flags().StringVar(&IDField, "id-field", "",
+		"Name of the field to be used as the document id. "+
+			"UUID derived from the content is generated for documents missing the field")
+	importCmd.Flags().BoolVar(&OverwriteID, "overwrite-id", false,
+		"Replace existing \"id\" field of the documents with the value of --id-field")
 
 	importCmd.Flags().BoolVarP(&Append, "append", "a", false,
 		"Force append to existing index")
+	importCmd.Flags().StringVar(&util.OnConflict, "on-conflict", util.OnConflictError,
+		"What to do with documents with the id which already exists in the index: "+
+			"error - fail the import, skip - leave existing documents untouched, replace - replace existing documents")
 	importCmd.Flags().BoolVar(&NoCreate, "no-create-index", false,
 		"Do not create collection automatically if it doesn't exist")
 
@@ -180,13 +711,82 @@ func init() {
 		"Try to detect date time fields")
 	importCmd.Flags().BoolVar(&schema.DetectIntegers, "detect-integers", true,
 		"Try to detect integer fields")
+	importCmd.Flags().BoolVar(&schema.DetectGeo, "detect-geo", false,
+		"Try to detect GeoJSON geometry fields")
+	importCmd.Flags().BoolVar(&schema.DetectEnums, "detect-enums", false,
+		"Emit enum constraint for the string fields with low number of distinct sampled values")
+	importCmd.Flags().IntVar(&schema.EnumThreshold, "enum-threshold", 10,
+		"Maximum number of distinct values of the string field to be detected as enum")
+	importCmd.Flags().StringVar(&schema.PrintSchema, "print-schema", "",
+		"Print the inferred schema to stderr: each - on every schema change, final - when the import is finished. "+
+			"--print-schema is equivalent to --print-schema=each")
+	importCmd.Flags().Lookup("print-schema").NoOptDefVal = schema.PrintSchemaEach
+	importCmd.Flags().StringVar(&schema.FieldTypeReport, "field-type-report", "",
+		"Print the fields of the final schema and their types to stderr: table, json, none. "+
+			"By default the table is printed when stderr is a terminal and --quiet is not set")
+	importCmd.Flags().Lookup("field-type-report").NoOptDefVal = schema.FieldTypeReportTable
+	importCmd.Flags().BoolVarP(&util.Quiet, "quiet", "q", false,
+		"Suppress informational messages")
+	importCmd.Flags().IntVar(&schema.MaxFields, "max-fields", 0,
+		"Warn when the inferred schema exceeds this number of top level fields. Default: no limit")
+	importCmd.Flags().StringVar(&schema.InferenceLog, "inference-log", "",
+		"Write every sample value considered by the schema inference and the type decision, "+
+			"as JSON lines, to the file. Useful to diagnose why a field got a particular type")
+	importCmd.Flags().BoolVar(&util.Strict, "strict", false,
+		"Fail the import instead of printing a warning")
 
 	importCmd.Flags().StringVar(&CSVDelimiter, "csv-delimiter", "",
-		"CSV delimiter")
+		"CSV delimiter. Escape sequences, like \\t, are interpreted. "+
+			"Multi-character delimiters, like ||, are supported at the cost of slower import")
 	importCmd.Flags().BoolVar(&CSVTrimLeadingSpace, "csv-trim-leading-space", true,
 		"Trim leading space in the fields")
 	importCmd.Flags().StringVar(&CSVComment, "csv-comment", "",
 		"CSV comment")
+	importCmd.Flags().StringArrayVar(&iterate.CSVArrayFields, "csv-array-field", []string{},
+		"Split the CSV column into array by the delimiter: name:delimiter, for example tags:;. Can be repeated")
+	importCmd.Flags().StringArrayVar(&iterate.Defaults, "default", []string{},
+		"Set the field to the value when it's absent or null: name=value. Can be repeated. "+
+			"The value is parsed as JSON, so numbers, booleans, arrays and objects are supported, "+
+			"other values are used as strings. Nested fields are specified using dot notation")
+	importCmd.Flags().StringVar(&iterate.CreatedAtField, "created-at-field", "",
+		"Set the field to the time of the import, when it's absent or null in the document. "+
+			"The field is inferred as date-time. Nested fields are specified using dot notation")
+	importCmd.Flags().StringVar(&iterate.UpdatedAtField, "updated-at-field", "",
+		"Always set the field to the time of the import. "+
+			"The field is inferred as date-time. Nested fields are specified using dot notation")
+	importCmd.Flags().StringArrayVar(&iterate.EncryptFields, "encrypt-field", []string{},
+		"Encrypt the values of the field before indexing. Can be repeated. Nested fields are specified using dot notation")
+	importCmd.Flags().StringVar(&iterate.EncryptMode, "encrypt-mode", util.EncryptRandom,
+		"Encryption mode: random or deterministic. Deterministic mode allows equality filters on encrypted fields")
+	importCmd.Flags().StringVar(&iterate.EncryptionKey, "encryption-key", "",
+		"Hex or base64 encoded 32 bytes encryption key. Defaults to TIGRIS_ENCRYPTION_KEY environment variable")
+	importCmd.Flags().StringVar(&iterate.EncryptionKeyFile, "encryption-key-file", "",
+		"Read the encryption key from the file")
+	importCmd.Flags().StringArrayVar(&iterate.AddFields, "add-field", []string{},
+		"Add computed field to every document: name=expr. Can be repeated. "+
+			"Supported expressions: now(), uuid(), concat(field, 'literal', ...)")
+	importCmd.Flags().StringVar(&iterate.ValidateWith, "validate-with", "",
+		"Validate documents against the JSON Schema file before importing. Invalid documents abort the import")
+	importCmd.Flags().BoolVar(&iterate.FieldStats, "field-stats", false,
+		"Report number of documents with null or absent value per field after the import")
+	importCmd.Flags().StringVar(&iterate.Format, "format", "",
+		"Format of the input: json, csv, msgpack. JSON and CSV are detected automatically if not set. "+
+			"MessagePack binary values are converted to base64 strings and timestamps to RFC3339 strings")
+	importCmd.Flags().StringVar(&iterate.InputEncoding, "input-encoding", "",
+		"Character encoding of the input, which is converted to UTF-8 before parsing. "+
+			"For example: latin1, windows-1252, utf-16le. Default: utf-8")
+	importCmd.Flags().IntVar(&iterate.InputBufferSize, "input-buffer-size", iterate.DefaultInputBufferSize,
+		"Size of the input read buffer in bytes. Larger buffers speed up reading of big files, "+
+			"smaller save memory in constrained environments. Minimum: 4096")
+	importCmd.Flags().StringVar(&iterate.GCSProject, "gcs-project", "",
+		"Google Cloud project billed for reading gs:// sources from requester pays buckets")
+	importCmd.Flags().StringVar(&iterate.Tee, "tee", "",
+		"Also write the successfully imported documents, after all the processing done by the CLI, "+
+			"to the file as newline delimited JSON. The file is gzip compressed when the name ends with .gz")
+	importCmd.Flags().BoolVar(&util.Optimize, "optimize", false,
+		"Optimize the collection or the search index after the import. "+
+			"No-op for now: the server doesn't expose the optimize operation, so a warning is printed, "+
+			"or the import fails with --strict")
 	addProjectFlag(importCmd)
 
 	RootCmd.AddCommand(importCmd)
@@ -0,0 +1,205 @@
+// Copyright 2022-2023 Tigris Data, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package search
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tigrisdata/tigris-cli/iterate"
+	"github.com/tigrisdata/tigris-cli/util"
+	api "github.com/tigrisdata/tigris-client-go/api/server/v1"
+	"github.com/tigrisdata/tigris-client-go/driver"
+)
+
+func TestIndexSchema(t *testing.T) {
+	errNetwork := fmt.Errorf("connection refused")
+	errDenied := driver.NewError(api.Code_PERMISSION_DENIED, "denied")
+	errUnavailable := driver.NewError(api.Code_UNAVAILABLE, "unavailable")
+
+	cases := []struct {
+		name   string
+		resp   *driver.IndexInfo
+		err    error
+		exists bool
+		schema []byte
+		expErr error
+	}{
+		{"exists", &driver.IndexInfo{Name: "idx", Schema: []byte(`{"title":"idx"}`)}, nil, true, []byte(`{"title":"idx"}`), nil},
+		{"not_found", nil, driver.NewError(api.Code_NOT_FOUND, "index not found"), false, nil, nil},
+		// errors other than NOT_FOUND should not be treated as missing index
+		{"permission_denied", nil, errDenied, false, nil, errDenied},
+		{"unavailable", nil, errUnavailable, false, nil, errUnavailable},
+		{"not_driver_error", nil, errNetwork, false, nil, errNetwork},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			exists, sch, err := indexSchema(context.Background(), "idx",
+				func(ctx context.Context, name string) (*driver.IndexInfo, error) {
+					assert.Equal(t, "idx", name)
+
+					return c.resp, c.err
+				})
+
+			require.Equal(t, c.expErr, err)
+
+			assert.Equal(t, c.exists, exists)
+			assert.Equal(t, c.schema, sch)
+		})
+	}
+}
+
+func TestCheckSearch(t *testing.T) {
+	errNetwork := fmt.Errorf("connection refused")
+	errDenied := driver.NewError(api.Code_PERMISSION_DENIED, "denied")
+
+	cases := []struct {
+		name   string
+		err    error
+		expErr error
+	}{
+		{"available", nil, nil},
+		{"not_found", driver.NewError(api.Code_NOT_FOUND, "not found"), ErrSearchUnavailable},
+		{"unimplemented", driver.NewError(api.Code_UNIMPLEMENTED, "unimplemented"), ErrSearchUnavailable},
+		// errors not related to search availability are returned as is
+		{"permission_denied", errDenied, errDenied},
+		{"not_driver_error", errNetwork, errNetwork},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			err := checkSearch(context.Background(), "proj1",
+				func(ctx context.Context, filter *driver.IndexSource) ([]*driver.IndexInfo, error) {
+					return nil, c.err
+				})
+
+			require.ErrorIs(t, err, c.expErr)
+
+			if c.expErr == ErrSearchUnavailable {
+				assert.Contains(t, err.Error(), "proj1")
+			}
+		})
+	}
+}
+
+func TestInferenceDocs(t *testing.T) {
+	docs := func(n int) []json.RawMessage {
+		d := make([]json.RawMessage, n)
+		for i := range d {
+			d[i] = json.RawMessage(fmt.Sprintf(`{"id":%d}`, i))
+		}
+
+		return d
+	}
+
+	imp := newIndexImporter("idx")
+
+	// no limit
+	assert.Len(t, imp.inferenceDocs(docs(10)), 10)
+
+	SchemaFreezeAfter = 15
+
+	defer func() { SchemaFreezeAfter = 0 }()
+
+	assert.Len(t, imp.inferenceDocs(docs(10)), 10)
+	assert.Len(t, imp.inferenceDocs(docs(10)), 5)
+	assert.Empty(t, imp.inferenceDocs(docs(10)))
+	assert.Equal(t, int64(15), imp.inferred)
+}
+
+func TestSetDocID(t *testing.T) {
+	genID := func(doc string) string {
+		return uuid.NewSHA1(uuid.NameSpaceOID, []byte(doc)).String()
+	}
+
+	cases := []struct {
+		name      string
+		field     string
+		overwrite bool
+		doc       string
+		exp       string
+		err       error
+	}{
+		{"string", "key", false, `{"b":1,"key":"k1","a":2}`, `{"b":1,"key":"k1","a":2,"id":"k1"}`, nil},
+		{"number", "key", false, `{"key":12345678901234567890}`, `{"key":12345678901234567890,"id":"12345678901234567890"}`, nil},
+		{"keep existing id", "key", false, `{"id":"orig","key":"k1"}`, `{"id":"orig","key":"k1"}`, nil},
+		{"overwrite existing id", "key", true, `{"id":"orig","key":"k1"}`, `{"id":"k1","key":"k1"}`, nil},
+		{"null id is replaced", "key", false, `{"id":null,"key":"k1"}`, `{"id":"k1","key":"k1"}`, nil},
+		{"missing field", "key", false, `{"b":1,"a":2}`, `{"b":1,"a":2,"id":"` + genID(`{"b":1,"a":2}`) + `"}`, nil},
+		{"unsupported type", "key", false, `{"key":{"a":1}}`, "", ErrUnsupportedIDType},
+		{"not an object", "key", false, `[1]`, "", util.ErrUnexpectedToken},
+	}
+
+	defer func() {
+		IDField = ""
+		OverwriteID = false
+	}()
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			IDField = c.field
+			OverwriteID = c.overwrite
+
+			res, err := setDocID(json.RawMessage(c.doc))
+			if c.err != nil {
+				require.ErrorIs(t, err, c.err)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, c.exp, string(res))
+
+			// re-import produces the same id
+			res2, err := setDocID(json.RawMessage(c.doc))
+			require.NoError(t, err)
+			assert.Equal(t, string(res), string(res2))
+		})
+	}
+}
+
+func TestDocError(t *testing.T) {
+	conflict := &driver.DocStatus{Id: "1", Error: &api.Error{Code: api.Code_ALREADY_EXISTS}}
+	failed := &driver.DocStatus{Id: "2", Error: &api.Error{Code: api.Code_INVALID_ARGUMENT, Message: "bad field"}}
+
+	defer func() {
+		util.OnConflict = util.OnConflictError
+		iterate.SkipErrors = false
+	}()
+
+	imp := &indexImporter{}
+	doc := json.RawMessage(`{"id":"2"}`)
+
+	util.OnConflict = util.OnConflictSkip
+	require.NoError(t, imp.docError(doc, conflict))
+
+	util.OnConflict = util.OnConflictError
+	require.ErrorIs(t, imp.docError(doc, conflict), ErrDocumentExists)
+
+	err := imp.docError(doc, failed)
+	require.ErrorIs(t, err, ErrDocumentFailed)
+	assert.Contains(t, err.Error(), "bad field")
+
+	iterate.SkipErrors = true
+	skipped := iterate.Skipped()
+
+	require.NoError(t, imp.docError(doc, failed))
+	assert.Equal(t, skipped+1, iterate.Skipped())
+}
@@ -18,16 +18,27 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"os"
+	"strconv"
+	"strings"
 
 	"github.com/spf13/cobra"
 	"github.com/tigrisdata/tigris-cli/client"
+	"github.com/tigrisdata/tigris-cli/config"
 	"github.com/tigrisdata/tigris-cli/iterate"
 	"github.com/tigrisdata/tigris-cli/login"
 	"github.com/tigrisdata/tigris-cli/util"
+	api "github.com/tigrisdata/tigris-client-go/api/server/v1"
 	"github.com/tigrisdata/tigris-client-go/driver"
 )
 
-var ErrSchemaNameMissing = fmt.Errorf("schema name is missing")
+var (
+	ErrSchemaNameMissing = fmt.Errorf("schema name is missing")
+
+	Detailed bool
+	Yes      bool
+	IfExists bool
+)
 
 func createIndex(ctx context.Context, raw driver.Schema) error {
 	type Schema struct {
@@ -81,21 +92,73 @@ var describeIndexCmd = &cobra.Command{
 	},
 }
 
+// indexDetails is the output of index list --detailed.
+type indexDetails struct {
+	Index     string `json:"index"`
+	Documents int64  `json:"documents"`
+}
+
+func describeIndexDetails(ctx context.Context, name string) (*indexDetails, error) {
+	it, err := client.GetSearch().Search(ctx, name, &driver.SearchRequest{Q: "", PageSize: 1})
+	if err != nil {
+		return nil, err
+	}
+
+	defer it.Close()
+
+	var resp driver.SearchIndexResponse
+
+	d := &indexDetails{Index: name}
+
+	if it.Next(&resp) && resp.Meta != nil {
+		d.Documents = resp.Meta.Found
+	}
+
+	return d, it.Err()
+}
+
 var listIndexesCmd = &cobra.Command{
 	Use:   "list",
 	Short: "Lists project indexes",
+	Long: `Lists project indexes.
+With --detailed flag outputs number of documents of every index.
+With --output=json outputs JSON array of the indexes.`,
 	Run: func(cmd *cobra.Command, args []string) {
+		util.Fatal(util.ValidateOutput(), "output")
+
 		login.Ensure(cmd.Context(), func(ctx context.Context) error {
 			resp, err := client.GetSearch().ListIndexes(ctx, nil)
 			if err != nil {
 				return util.Error(err, "list indexes")
 			}
 
+			if !Detailed {
+				names := make([]string, 0, len(resp))
+				rows := make([][]string, 0, len(resp))
+
+				for _, v := range resp {
+					names = append(names, v.Name)
+					rows = append(rows, []string{v.Name})
+				}
+
+				return util.Error(util.WriteList(os.Stdout, nil, rows, names), "list indexes output")
+			}
+
+			items := make([]*indexDetails, 0, len(resp))
+			rows := make([][]string, 0, len(resp))
+
 			for _, v := range resp {
-				util.Stdoutf("%s\n", v)
+				d, err := describeIndexDetails(ctx, v.Name)
+				if err != nil {
+					return util.Error(err, "describe index: %s", v.Name)
+				}
+
+				items = append(items, d)
+				rows = append(rows, []string{d.Index, strconv.FormatInt(d.Documents, 10)})
 			}
 
-			return nil
+			return util.Error(util.WriteList(os.Stdout, []string{"INDEX", "DOCUMENTS"}, rows, items),
+				"list indexes output")
 		})
 	},
 }
@@ -161,24 +224,57 @@ var createIndexCmd = &cobra.Command{
 	},
 }
 
+// deleteIndexes deletes the indexes, asking for confirmation when run interactively,
+// unless the names of the indexes are read from the standard input.
+func deleteIndexes(cmd *cobra.Command, args []string) {
+	if !Yes {
+		util.Fatal(util.ConfirmArgs(args, "Delete index(es) %s in project %s?", strings.Join(args, ", "),
+			config.GetProjectName()), "delete index")
+	}
+
+	login.Ensure(cmd.Context(), func(ctx context.Context) error {
+		return iterate.Input(ctx, cmd, 0, args, func(ctx context.Context, args []string, docs []json.RawMessage) error {
+			for _, v := range docs {
+				err := client.GetSearch().DeleteIndex(ctx, string(v))
+
+				// FIXME: errors.As(err, &ep) doesn't work
+				//nolint:golint,errorlint
+				if ep, ok := err.(*driver.Error); ok && ep.Code == api.Code_NOT_FOUND && IfExists {
+					util.Infof("index doesn't exist: %s", string(v))
+					continue
+				}
+
+				if err != nil {
+					return util.Error(err, "delete index")
+				}
+				util.Infof("deleted index: %s", string(v))
+			}
+
+			return nil
+		})
+	})
+}
+
 var deleteIndexCmd = &cobra.Command{
-	Use:   "delete",
+	Use:   "delete {index}...|-",
 	Short: "Delete index",
 	Args:  cobra.MinimumNArgs(1),
-	Run: func(cmd *cobra.Command, args []string) {
-		login.Ensure(cmd.Context(), func(ctx context.Context) error {
-			return iterate.Input(ctx, cmd, 0, args, func(ctx context.Context, args []string, docs []json.RawMessage) error {
-				for _, v := range docs {
-					if err := client.GetSearch().DeleteIndex(ctx, string(v)); err != nil {
-						return util.Error(err, "delete index")
-					}
-					util.Infof("deleted index: %s", string(v))
-				}
+	Run:   deleteIndexes,
+}
 
-				return nil
-			})
-		})
-	},
+var dropIndexCmd = &cobra.Command{
+	Use:   "drop {index}...|-",
+	Short: "Drops search index",
+	Long: `Drops the search indexes and all their documents.
+When run interactively, asks for confirmation, unless --yes is specified
+or the names of the indexes are read from the standard input, by "-".
+With --if-exists indexes which don't exist are skipped, instead of failing the command.`,
+	Example: fmt.Sprintf(`
+  %[1]s drop --project=myproj users
+  %[1]s drop --project=myproj --yes --if-exists users orders
+`, "tigris search"),
+	Args: cobra.MinimumNArgs(1),
+	Run:  deleteIndexes,
 }
 
 var indexCmd = &cobra.Command{
@@ -187,8 +283,18 @@ var indexCmd = &cobra.Command{
 }
 
 func init() {
-	addProjectFlag(deleteIndexCmd)
+	for _, c := range []*cobra.Command{deleteIndexCmd, dropIndexCmd} {
+		c.Flags().BoolVarP(&Yes, "yes", "y", false, "Don't ask for confirmation")
+		c.Flags().BoolVar(&IfExists, "if-exists", false, "Don't fail if the index doesn't exist")
+		addProjectFlag(c)
+	}
+
 	addProjectFlag(createIndexCmd)
+	listIndexesCmd.Flags().StringVar(&util.Output, "output", util.OutputText,
+		"Output format: text, json")
+	listIndexesCmd.Flags().BoolVar(&Detailed, "detailed", false,
+		"Output number of documents in the indexes")
+
 	addProjectFlag(listIndexesCmd)
 	addProjectFlag(describeIndexCmd)
 
@@ -198,4 +304,5 @@ func init() {
 	indexCmd.AddCommand(describeIndexCmd)
 
 	RootCmd.AddCommand(indexCmd)
+	RootCmd.AddCommand(dropIndexCmd)
 }
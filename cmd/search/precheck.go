@@ -0,0 +1,48 @@
+// Copyright 2022-2023 Tigris Data, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package search
+
+import (
+	"context"
+	"fmt"
+
+	api "github.com/tigrisdata/tigris-client-go/api/server/v1"
+	"github.com/tigrisdata/tigris-client-go/driver"
+)
+
+var ErrSearchUnavailable = fmt.Errorf("search is not enabled for the project, or the project doesn't exist")
+
+type listIndexesFunc func(ctx context.Context, filter *driver.IndexSource) ([]*driver.IndexInfo, error)
+
+// checkSearch checks that the search is available for the project, so as the import fails early,
+// with clear message, instead of the driver error of the first search request.
+// Errors not related to search availability, like network errors, are returned as is.
+func checkSearch(ctx context.Context, project string, listIndexes listIndexesFunc) error {
+	_, err := listIndexes(ctx, nil)
+	if err == nil {
+		return nil
+	}
+
+	// FIXME: errors.As(err, &ep) doesn't work
+	//nolint:golint,errorlint
+	if ep, ok := err.(*driver.Error); ok {
+		switch ep.Code {
+		case api.Code_NOT_FOUND, api.Code_UNIMPLEMENTED, api.Code_FAILED_PRECONDITION:
+			return fmt.Errorf("%w: %s: %s", ErrSearchUnavailable, project, ep.Message)
+		}
+	}
+
+	return err
+}
@@ -18,7 +18,6 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
-	"unsafe"
 
 	"github.com/spf13/cobra"
 	"github.com/tigrisdata/tigris-cli/client"
@@ -108,15 +107,13 @@ func execTxOpLow(ctx context.Context, tx driver.Tx, tp string, op *Op) error {
 
 	switch tp {
 	case Insert:
-		ptr := unsafe.Pointer(&op.Documents)
-		_, err = tx.Insert(ctx, op.Collection, *(*[]driver.Document)(ptr))
+		_, err = tx.Insert(ctx, op.Collection, client.Documents(op.Documents))
 	case Update:
 		_, err = tx.Update(ctx, op.Collection, driver.Filter(op.Filter), driver.Update(op.Fields))
 	case Delete:
 		_, err = tx.Delete(ctx, op.Collection, driver.Filter(op.Filter))
 	case Replace, InsertOrReplace:
-		ptr := unsafe.Pointer(&op.Documents)
-		_, err = tx.Replace(ctx, op.Collection, *(*[]driver.Document)(ptr), &driver.ReplaceOptions{})
+		_, err = tx.Replace(ctx, op.Collection, client.Documents(op.Documents), &driver.ReplaceOptions{})
 	case CreateOrUpdateCollection:
 		err = tx.CreateOrUpdateCollection(ctx, op.Collection, driver.Schema(op.Schema))
 	case DropCollection:
@@ -16,7 +16,9 @@ package cmd
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
+	"strings"
 
 	"github.com/spf13/cobra"
 	"github.com/tigrisdata/tigris-cli/client"
@@ -25,26 +27,162 @@ import (
 	"github.com/tigrisdata/tigris-client-go/driver"
 )
 
+var (
+	updateKey      string
+	updateKeyField string
+
+	ErrUpdateCompositeKey = fmt.Errorf("collection has composite primary key, specify the field with --key-field")
+	ErrUpdateNoPrimaryKey = fmt.Errorf("collection has no primary key, specify the field with --key-field")
+)
+
+// keyValue converts the key to the type of the key field in the schema.
+// Keys of string fields are used as is, otherwise the key is parsed as JSON,
+// preserving the precision of the numbers, falling back to string,
+// so as numeric and string keys can be specified without quotes.
+func keyValue(key string, typ string) any {
+	if typ == "string" {
+		return key
+	}
+
+	dec := json.NewDecoder(strings.NewReader(key))
+	dec.UseNumber()
+
+	var v any
+
+	if err := dec.Decode(&v); err != nil || dec.More() {
+		return key
+	}
+
+	return v
+}
+
+// keySchema is the part of the collection schema describing the key fields.
+type keySchema struct {
+	PrimaryKey []string `json:"primary_key"`
+	Properties map[string]struct {
+		Type string `json:"type"`
+	} `json:"properties"`
+}
+
+// primaryKeyField returns the single field of the primary key of the collection,
+// or the field given with --key-field, and the type of the field in the schema.
+func primaryKeyField(ctx context.Context, coll string, field string) (string, string, error) {
+	resp, err := client.GetDB().DescribeCollection(ctx, coll)
+	if err != nil {
+		return "", "", err
+	}
+
+	var sch keySchema
+
+	if err = json.Unmarshal(resp.Schema, &sch); err != nil {
+		return "", "", err
+	}
+
+	if field == "" {
+		switch len(sch.PrimaryKey) {
+		case 0:
+			return "", "", ErrUpdateNoPrimaryKey
+		case 1:
+			field = sch.PrimaryKey[0]
+		default:
+			return "", "", ErrUpdateCompositeKey
+		}
+	}
+
+	return field, sch.Properties[field].Type, nil
+}
+
+// keyFilter builds the filter matching the document with the --key.
+func keyFilter(ctx context.Context, coll string) (driver.Filter, error) {
+	field, typ, err := primaryKeyField(ctx, coll, updateKeyField)
+	if err != nil {
+		return nil, err
+	}
+
+	return json.Marshal(map[string]any{field: keyValue(updateKey, typ)})
+}
+
+// patchUpdate wraps the partial document into $set,
+// unless it already contains update operators, like $set or $unset.
+func patchUpdate(patch string) (driver.Update, error) {
+	var m map[string]json.RawMessage
+
+	if err := json.Unmarshal([]byte(patch), &m); err != nil {
+		return nil, err
+	}
+
+	for k := range m {
+		if strings.HasPrefix(k, "$") {
+			return driver.Update(patch), nil
+		}
+	}
+
+	return json.Marshal(map[string]json.RawMessage{"$set": json.RawMessage(patch)})
+}
+
+func updateArgs(cmd *cobra.Command, args []string) error {
+	if updateKey != "" {
+		return cobra.ExactArgs(2)(cmd, args)
+	}
+
+	return cobra.MinimumNArgs(3)(cmd, args)
+}
+
 var updateCmd = &cobra.Command{
 	Use:   "update {collection} {filter} {fields}",
 	Short: "Updates document(s)",
-	Long:  "Updates the field values in documents according to provided filter.",
+	Long: `Updates the field values in documents according to provided filter.
+
+Single document can be updated by the value of the primary key with --key,
+the filter argument is omitted in this case. The primary key field is
+detected from the schema of the collection, or can be set by --key-field.
+
+Partial document without update operators is applied as $set.
+The number of modified documents is printed.`,
 	Example: fmt.Sprintf(`
   # Update the field "name" of user where the value of the id field is 2
   %[1]s update --project=myproj users '{"id": 19}' '{"$set": {"name": "Updated New User"}}'
+
+  # Update the field "name" of user with primary key 19
+  %[1]s update --project=myproj users --key=19 '{"name": "Updated New User"}'
 `, rootCmd.Root().Name()),
-	Args: cobra.MinimumNArgs(3),
+	Args: updateArgs,
 	Run: func(cmd *cobra.Command, args []string) {
 		login.Ensure(cmd.Context(), func(ctx context.Context) error {
-			_, err := client.GetDB().
-				Update(ctx, args[0], driver.Filter(args[1]), driver.Update(args[2]))
+			filter, fields := driver.Filter(args[1]), args[len(args)-1]
+
+			if updateKey != "" {
+				var err error
+
+				if filter, err = keyFilter(ctx, args[0]); err != nil {
+					return util.Error(err, "key filter")
+				}
+			}
 
-			return util.Error(err, "update documents failed")
+			upd, err := patchUpdate(fields)
+			if err != nil {
+				return util.Error(err, "parse update")
+			}
+
+			resp, err := client.GetDB().Update(ctx, args[0], filter, upd)
+			if err != nil {
+				return util.Error(err, "update documents failed")
+			}
+
+			util.Stdoutf("%d document(s) modified\n", resp.ModifiedCount)
+
+			return nil
 		})
 	},
 }
 
 func init() {
+	updateCmd.Flags().StringVar(&updateKey, "key", "",
+		"Update the document with the value of the primary key. "+
+			"Used as is for string key fields, parsed as JSON for other types, falling back to string")
+	updateCmd.Flags().StringVar(&updateKeyField, "key-field", "",
+		"Field matched by --key. Defaults to the primary key of the collection")
+
 	addProjectFlag(updateCmd)
 	rootCmd.AddCommand(updateCmd)
 }
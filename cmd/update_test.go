@@ -0,0 +1,48 @@
+// Copyright 2022-2023 Tigris Data, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestKeyValue(t *testing.T) {
+	cases := []struct {
+		name string
+		key  string
+		typ  string
+		exp  string
+	}{
+		{"int64 precision", "1234567890123456789", "integer", `{"id":1234567890123456789}`},
+		{"untyped number", "1234567890123456789", "", `{"id":1234567890123456789}`},
+		{"numeric string key", "12345", "string", `{"id":"12345"}`},
+		{"quoted string key", `"abc"`, "string", `{"id":"\"abc\""}`},
+		{"unquoted string", "abc", "", `{"id":"abc"}`},
+		{"trailing data", "1 2", "", `{"id":"1 2"}`},
+		{"bool", "true", "boolean", `{"id":true}`},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			b, err := json.Marshal(map[string]any{"id": keyValue(c.key, c.typ)})
+			require.NoError(t, err)
+			assert.Equal(t, c.exp, string(b))
+		})
+	}
+}
@@ -16,6 +16,10 @@ package cmd
 
 import (
 	"context"
+	"encoding/json"
+	"runtime"
+	"runtime/debug"
+	"strings"
 
 	"github.com/spf13/cobra"
 	"github.com/tigrisdata/tigris-cli/client"
@@ -24,11 +28,187 @@ import (
 	"github.com/tigrisdata/tigris-cli/util"
 )
 
+const (
+	driverModule = "github.com/tigrisdata/tigris-client-go"
+
+	compatibilityOK       = "compatible"
+	compatibilityMismatch = "incompatible"
+	compatibilityUnknown  = "unknown"
+)
+
+var (
+	VersionJSON bool
+
+	// VersionServer enables querying the version of the server, which may be slow or unreachable.
+	VersionServer bool
+)
+
+// versionInfo is the version of the CLI, its build and the server, output by the version command.
+type versionInfo struct {
+	Version       string `json:"version"`
+	Commit        string `json:"commit,omitempty"`
+	BuildTime     string `json:"build_time,omitempty"`
+	GoVersion     string `json:"go_version"`
+	Platform      string `json:"platform"`
+	DriverVersion string `json:"driver_version,omitempty"`
+
+	URL           string `json:"url,omitempty"`
+	ServerVersion string `json:"server_version,omitempty"`
+	ServerError   string `json:"server_error,omitempty"`
+	Compatibility string `json:"compatibility,omitempty"`
+}
+
+// majorVersion returns the major version of the v-prefixed semantic version, like v1 for v1.0.0-beta.2,
+// or empty string if the version is not semantic, like the development builds.
+func majorVersion(v string) string {
+	major, _, _ := strings.Cut(v, ".")
+	if len(major) < 2 || major[0] != 'v' || strings.Trim(major[1:], "0123456789") != "" {
+		return ""
+	}
+
+	return major
+}
+
+// compatibility tells whether the CLI and the server versions are compatible.
+// The versions are compatible when their major versions match.
+func compatibility(cliVersion string, serverVersion string) string {
+	cli, server := majorVersion(cliVersion), majorVersion(serverVersion)
+
+	switch {
+	case cli == "" || server == "":
+		return compatibilityUnknown
+	case cli == server:
+		return compatibilityOK
+	default:
+		return compatibilityMismatch
+	}
+}
+
+// getServerVersion returns the version of the server, if it's reachable,
+// without logging in, as the version command should work before login.
+func getServerVersion(cmdCtx context.Context) (string, error) {
+	if err := client.Init(&config.DefaultConfig); err != nil {
+		return "", err
+	}
+
+	if err := client.InitLow(); err != nil {
+		return "", err
+	}
+
+	ctx, cancel := util.GetContext(cmdCtx)
+	defer cancel()
+
+	resp, err := client.D.Info(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	return resp.ServerVersion, nil
+}
+
+// getVersionInfo returns the version of the CLI and its build, without contacting the server.
+func getVersionInfo() *versionInfo {
+	info := &versionInfo{
+		Version:   util.Version,
+		GoVersion: runtime.Version(),
+		Platform:  runtime.GOOS + "/" + runtime.GOARCH,
+	}
+
+	if bi, ok := debug.ReadBuildInfo(); ok {
+		for _, s := range bi.Settings {
+			switch s.Key {
+			case "vcs.revision":
+				info.Commit = s.Value
+			case "vcs.time":
+				info.BuildTime = s.Value
+			}
+		}
+
+		for _, dep := range bi.Deps {
+			if dep.Path == driverModule {
+				info.DriverVersion = dep.Version
+			}
+		}
+	}
+
+	return info
+}
+
+// addServerVersion adds the version of the server and its compatibility with the CLI to the info.
+func addServerVersion(ctx context.Context, info *versionInfo) {
+	info.URL = login.GetHost("")
+
+	v, err := getServerVersion(ctx)
+	if err != nil {
+		info.ServerError = err.Error()
+	}
+
+	info.ServerVersion = v
+	info.Compatibility = compatibility(info.Version, info.ServerVersion)
+}
+
+func printVersionInfo(info *versionInfo) {
+	util.Stdoutf("tigris version %s\n", info.Version)
+
+	if info.Commit != "" {
+		util.Stdoutf("  commit: %s %s\n", info.Commit, info.BuildTime)
+	}
+
+	util.Stdoutf("  go: %s %s\n", info.GoVersion, info.Platform)
+
+	if info.DriverVersion != "" {
+		util.Stdoutf("  driver: %s %s\n", driverModule, info.DriverVersion)
+	}
+}
+
+func printServerVersionInfo(info *versionInfo) {
+	if info.ServerError != "" {
+		util.Stdoutf("tigris server at %s is not reachable: %s\n", info.URL, info.ServerError)
+		return
+	}
+
+	util.Stdoutf("tigris server version at %s is %s\n", info.URL, info.ServerVersion)
+
+	switch info.Compatibility {
+	case compatibilityOK:
+		util.Stdoutf("CLI and server versions are compatible\n")
+	case compatibilityMismatch:
+		util.Stdoutf("CLI and server major versions differ, upgrade the CLI or the server\n")
+	default:
+		util.Stdoutf("CLI and server versions compatibility is unknown for the development builds\n")
+	}
+}
+
 var versionCmd = &cobra.Command{
 	Use:   "version",
 	Short: "Shows tigris cli version",
+	Long: `Shows the version of the CLI, the build information, the Go and the driver versions.
+With --server also shows the version of the server, when it's reachable,
+and whether the CLI and the server are compatible, which is when their major versions match.
+Include the output into the bug reports.`,
 	Run: func(cmd *cobra.Command, args []string) {
-		util.Stdoutf("tigris version %s\n", util.Version)
+		info := getVersionInfo()
+
+		if !VersionJSON {
+			// local version is shown, even if the server query hangs
+			printVersionInfo(info)
+
+			if VersionServer {
+				addServerVersion(cmd.Context(), info)
+				printServerVersionInfo(info)
+			}
+
+			return
+		}
+
+		if VersionServer {
+			addServerVersion(cmd.Context(), info)
+		}
+
+		b, err := json.MarshalIndent(info, "", "  ")
+		util.Fatal(err, "marshal version info")
+
+		util.Stdoutf("%s\n", string(b))
 	},
 }
 
@@ -50,5 +230,8 @@ var serverVersionCmd = &cobra.Command{
 }
 
 func init() {
+	versionCmd.Flags().BoolVar(&VersionJSON, "json", false, "Output the version information as JSON")
+	versionCmd.Flags().BoolVar(&VersionServer, "server", false,
+		"Also show the version of the server and its compatibility with the CLI")
 	rootCmd.AddCommand(versionCmd)
 }
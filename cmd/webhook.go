@@ -0,0 +1,103 @@
+// Copyright 2022-2023 Tigris Data, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"context"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/tigrisdata/tigris-cli/config"
+	"github.com/tigrisdata/tigris-cli/iterate"
+	"github.com/tigrisdata/tigris-cli/util"
+)
+
+var (
+	Webhook       string
+	WebhookSecret string
+)
+
+// importSummary is the summary of the import posted to the webhook.
+type importSummary struct {
+	Command         string           `json:"command"`
+	Project         string           `json:"project"`
+	Collections     []string         `json:"collections"`
+	Status          string           `json:"status"`
+	Error           string           `json:"error,omitempty"`
+	Documents       int64            `json:"documents"`
+	Inserted        int64            `json:"inserted"`
+	SkippedExisting int64            `json:"skipped_existing"`
+	Failed          int64            `json:"failed"`
+	FailedByError   map[string]int64 `json:"failed_by_error,omitempty"`
+	StartedAt       string           `json:"started_at"`
+	FinishedAt      string           `json:"finished_at"`
+	DurationSeconds float64          `json:"duration_seconds"`
+}
+
+func newImportSummary(command string, imps []*importer, start time.Time, err error) *importSummary {
+	end := time.Now()
+
+	s := &importSummary{
+		Command:         command,
+		Project:         config.GetProjectName(),
+		Collections:     make([]string, 0, len(imps)),
+		Status:          "success",
+		Documents:       iterate.Seen(),
+		Failed:          iterate.Skipped(),
+		FailedByError:   iterate.ErrorClasses(),
+		StartedAt:       start.UTC().Format(time.RFC3339Nano),
+		FinishedAt:      end.UTC().Format(time.RFC3339Nano),
+		DurationSeconds: end.Sub(start).Seconds(),
+	}
+
+	if err != nil {
+		s.Status = "failed"
+		s.Error = err.Error()
+	}
+
+	for _, imp := range imps {
+		s.Collections = append(s.Collections, imp.coll)
+		s.Inserted += imp.inserted
+		s.SkippedExisting += imp.skippedExisting
+	}
+
+	return s
+}
+
+// notifyWebhook posts the summary of the import to the webhook, if configured.
+// Failure to deliver the summary doesn't fail the import, it's only reported.
+// The summary is delivered even if the import has been interrupted,
+// so the context of the command is not used.
+// Fatal errors deliver the summary through the exit hook, see onImportExit.
+func notifyWebhook(command string, imps []*importer, start time.Time, importErr error) {
+	if Webhook == "" {
+		return
+	}
+
+	s := newImportSummary(command, imps, start, importErr)
+
+	if err := util.PostWebhook(context.Background(), Webhook, WebhookSecret, s); err != nil {
+		util.Stderrf("warning: failed to post import summary to webhook: %s\n", err.Error())
+	}
+}
+
+func addWebhookFlags(cmd *cobra.Command) {
+	cmd.Flags().StringVar(&Webhook, "webhook", "",
+		"POST the summary of the import as JSON to the URL on completion or failure. "+
+			"Failure to deliver the summary is reported, but doesn't fail the import")
+	cmd.Flags().StringVar(&WebhookSecret, "webhook-secret", "",
+		"Sign the webhook payload with HMAC-SHA256 using the secret. "+
+			"The signature is sent in "+util.WebhookSignatureHeader+" header as sha256=<hex>")
+}
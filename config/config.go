@@ -17,13 +17,15 @@ package config
 import (
 	"bytes"
 	"encoding/json"
-	"errors"
 	"fmt"
 	"os"
+	"path/filepath"
 	"runtime"
+	"strconv"
 	"strings"
 	"time"
 
+	"github.com/spf13/afero"
 	"github.com/spf13/viper"
 	"gopkg.in/yaml.v2"
 )
@@ -36,22 +38,54 @@ var (
 
 	Project string
 
-	errUnableToReadProject = fmt.Errorf("please specify project name")
+	errUnableToReadProject = fmt.Errorf("please specify project name with --project flag, " +
+		"TIGRIS_PROJECT or " + ProjectEnv + " environment variable or project in the config file")
+
+	ErrInvalidFormat = fmt.Errorf("invalid config format. allowed values: %s, %s, %s",
+		FormatYAML, FormatJSON, FormatTOML)
+
+	// Format is the format of the config file written by Save.
+	// Defaults to the format of the loaded config file, or yaml if no config file loaded.
+	Format string
+
+	// loadedFormat is the format of the config file found by Load.
+	loadedFormat string
 )
 
+const (
+	FormatYAML = "yaml"
+	FormatJSON = "json"
+	FormatTOML = "toml"
+)
+
+// formatExts lists config file extensions in the lookup order.
+// First found file in the config path is loaded.
+var formatExts = []struct {
+	ext    string
+	format string
+}{
+	{"yaml", FormatYAML},
+	{"yml", FormatYAML},
+	{"json", FormatJSON},
+	{"toml", FormatTOML},
+}
+
 type Log struct {
-	Level string `json:"level" yaml:"level,omitempty"`
+	Level  string `json:"level"  yaml:"level,omitempty"`
+	Format string `json:"format" yaml:"format,omitempty"`
 }
 
 type Config struct {
 	ClientID     string `json:"client_id"     mapstructure:"client_id"     yaml:"client_id,omitempty"`
 	ClientSecret string `json:"client_secret" mapstructure:"client_secret" yaml:"client_secret,omitempty"`
 	Token        string `json:"token"         yaml:"token,omitempty"`
+	TokenFile    string `json:"token_file"    mapstructure:"token_file"    yaml:"token_file,omitempty"`
 	URL          string `json:"url"           yaml:"url,omitempty"`
 	Protocol     string `json:"protocol"      yaml:"protocol,omitempty"`
 	Project      string `json:"project"       yaml:"project,omitempty"`
 	Branch       string `json:"branch"        yaml:"branch,omitempty"`
 	DataDir      string `json:"data_dir"      yaml:"data_dir,omitempty"`
+	UserAgent    string `json:"user_agent"    mapstructure:"user_agent"    yaml:"user_agent,omitempty"`
 
 	Log          Log           `json:"log"            yaml:"log,omitempty"`
 	Timeout      time.Duration `json:"timeout"        yaml:"timeout,omitempty"`
@@ -70,6 +104,85 @@ var configPath = []string{
 
 var envPrefix = "tigris"
 
+// configFs is the file system of the config files, replaced by in-memory file system in tests.
+var configFs = afero.NewOsFs()
+
+// NoConfigFileEnv disables the config file lookup,
+// for the deployments configured by the environment variables only.
+const NoConfigFileEnv = "TIGRISDB_NO_CONFIG_FILE"
+
+// ProjectEnv sets the default project.
+// The project is taken from the first of:
+//   - --project flag
+//   - TIGRIS_PROJECT environment variable
+//   - TIGRISDB_PROJECT environment variable
+//   - project in the config file
+const ProjectEnv = "TIGRISDB_PROJECT"
+
+func ValidateFormat() error {
+	switch Format {
+	case "", FormatYAML, FormatJSON, FormatTOML:
+		return nil
+	}
+
+	return ErrInvalidFormat
+}
+
+func saveFormat() string {
+	if Format != "" {
+		return Format
+	}
+
+	if loadedFormat != "" {
+		return loadedFormat
+	}
+
+	return FormatYAML
+}
+
+func marshal(format string, config any) ([]byte, error) {
+	b, err := yaml.Marshal(config)
+	if err != nil || format == FormatYAML {
+		return b, err
+	}
+
+	// Convert to other formats through generic map,
+	// so as the keys and omitted empty values are the same as in yaml.
+	var m map[string]any
+	if err = yaml.Unmarshal(b, &m); err != nil {
+		return nil, err
+	}
+
+	v := viper.New()
+	if err = v.MergeConfigMap(m); err != nil {
+		return nil, err
+	}
+
+	fs := afero.NewMemMapFs()
+	v.SetFs(fs)
+
+	file := "/config." + format
+	if err = v.WriteConfigAs(file); err != nil {
+		return nil, err
+	}
+
+	return afero.ReadFile(fs, file)
+}
+
+// findConfigFile returns the first config file with supported extension found in the config path.
+func findConfigFile(name string) (string, string) {
+	for _, p := range configPath {
+		for _, e := range formatExts {
+			file := filepath.Join(os.ExpandEnv(p), name+"."+e.ext)
+			if _, err := configFs.Stat(file); err == nil {
+				return file, e.format
+			}
+		}
+	}
+
+	return "", ""
+}
+
 func Save(name string, config any) error {
 	var home string
 
@@ -89,31 +202,52 @@ func Save(name string, config any) error {
 	}
 
 	path += "/.tigris/"
-	if err := os.MkdirAll(path, 0o700); err != nil {
+	if err := configFs.MkdirAll(path, 0o700); err != nil {
 		return err
 	}
 
-	file := path + name + ".yaml"
-	if err := os.Rename(file, file+".bak"); err != nil {
-		if !os.IsNotExist(err) {
-			return err
+	config = withoutFileToken(config)
+
+	format := saveFormat()
+
+	// Back up the config files of all formats, so as the file
+	// of the previous format doesn't shadow the new one
+	for _, e := range formatExts {
+		file := path + name + "." + e.ext
+		if err := configFs.Rename(file, file+".bak"); err != nil {
+			if !os.IsNotExist(err) {
+				return err
+			}
 		}
 	}
 
-	b, err := yaml.Marshal(config)
+	file := path + name + "." + format
+
+	b, err := marshal(format, config)
 	if err != nil {
 		return err
 	}
 
-	return os.WriteFile(file, b, 0o600)
+	return afero.WriteFile(configFs, file, b, 0o600)
 }
 
+// NoConfigFile reports whether the config file lookup is disabled
+// by NoConfigFileEnv environment variable set to true value, like 1 or true.
+func NoConfigFile() bool {
+	b, err := strconv.ParseBool(os.Getenv(NoConfigFileEnv))
+
+	return err == nil && b
+}
+
+// Load loads the config from the file found in the config path and from the environment variables.
+// The format of the config file is detected by the extension: .yaml, .yml, .json, .toml.
+// When NoConfigFile, the config path is not searched and the config
+// is loaded from the defaults and the environment variables only.
 func Load(name string, config any) {
-	viper.SetConfigName(name + ".yaml")
-	viper.SetConfigType("yaml")
+	var file, format string
 
-	for _, v := range configPath {
-		viper.AddConfigPath(v)
+	if !NoConfigFile() {
+		file, format = findConfigFile(name)
 	}
 
 	// This is needed to automatically bind environment variables to config struct
@@ -131,6 +265,10 @@ func Load(name string, config any) {
 	viper.SetEnvPrefix(envPrefix)
 	viper.AutomaticEnv()
 
+	if err := viper.BindEnv("project", ProjectEnv); err != nil {
+		e(err, "bind project env")
+	}
+
 	viper.SetConfigType("json")
 
 	br := bytes.NewBuffer(b)
@@ -138,14 +276,16 @@ func Load(name string, config any) {
 		e(err, "merge config")
 	}
 
-	viper.SetConfigType("yaml")
+	if file != "" {
+		viper.SetFs(configFs)
+		viper.SetConfigFile(file)
+		viper.SetConfigType(format)
 
-	err = viper.MergeInConfig()
-	if err != nil {
-		var ep viper.ConfigFileNotFoundError
-		if !errors.As(err, &ep) {
+		if err = viper.MergeInConfig(); err != nil {
 			e(err, "error reading config")
 		}
+
+		loadedFormat = format
 	}
 
 	if err := viper.Unmarshal(&config); err != nil {
@@ -160,7 +300,7 @@ func e(err error, _ string) {
 
 func GetProjectName() string {
 	// first user supplied flag
-	// second env variable
+	// second env variable: TIGRIS_PROJECT, then TIGRISDB_PROJECT
 	// third config file
 	if Project == "" {
 		Project = DefaultConfig.Project
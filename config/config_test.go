@@ -0,0 +1,245 @@
+// Copyright 2022-2023 Tigris Data, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package config
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/spf13/afero"
+	"github.com/spf13/pflag"
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// memConfigFs replaces the config file system and the config path for the duration of the test.
+func memConfigFs(t *testing.T, files map[string]string) afero.Fs {
+	t.Helper()
+
+	fs := afero.NewMemMapFs()
+	for name, data := range files {
+		require.NoError(t, afero.WriteFile(fs, name, []byte(data), 0o600))
+	}
+
+	savedFs, savedPath := configFs, configPath
+	configFs, configPath = fs, []string{"/etc/tigris/", "/home/user/.tigris"}
+
+	t.Cleanup(func() { configFs, configPath = savedFs, savedPath })
+
+	return fs
+}
+
+func TestFindConfigFile(t *testing.T) {
+	cases := []struct {
+		name   string
+		files  []string
+		file   string
+		format string
+	}{
+		{"no files", nil, "", ""},
+		{"yaml", []string{"/etc/tigris/test.yaml"}, "/etc/tigris/test.yaml", FormatYAML},
+		{"yml", []string{"/etc/tigris/test.yml"}, "/etc/tigris/test.yml", FormatYAML},
+		{"json", []string{"/etc/tigris/test.json"}, "/etc/tigris/test.json", FormatJSON},
+		{"toml", []string{"/etc/tigris/test.toml"}, "/etc/tigris/test.toml", FormatTOML},
+		{"other name", []string{"/etc/tigris/other.yaml"}, "", ""},
+		{"yaml wins over json and toml", []string{
+			"/etc/tigris/test.toml", "/etc/tigris/test.json", "/etc/tigris/test.yaml",
+		}, "/etc/tigris/test.yaml", FormatYAML},
+		{"yaml wins over yml", []string{
+			"/etc/tigris/test.yml", "/etc/tigris/test.yaml",
+		}, "/etc/tigris/test.yaml", FormatYAML},
+		{"json wins over toml", []string{
+			"/etc/tigris/test.toml", "/etc/tigris/test.json",
+		}, "/etc/tigris/test.json", FormatJSON},
+		{"first path wins", []string{
+			"/home/user/.tigris/test.yaml", "/etc/tigris/test.toml",
+		}, "/etc/tigris/test.toml", FormatTOML},
+		{"second path", []string{"/home/user/.tigris/test.json"}, "/home/user/.tigris/test.json", FormatJSON},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			files := make(map[string]string)
+			for _, f := range c.files {
+				files[f] = ""
+			}
+
+			memConfigFs(t, files)
+
+			file, format := findConfigFile("test")
+			assert.Equal(t, c.file, file)
+			assert.Equal(t, c.format, format)
+		})
+	}
+}
+
+func TestMarshal(t *testing.T) {
+	cfg := Config{
+		URL:     "localhost:8081",
+		Project: "p1",
+		Log:     Log{Level: "debug"},
+	}
+
+	exp := map[string]any{
+		"url":     "localhost:8081",
+		"project": "p1",
+		"log":     map[string]any{"level": "debug"},
+	}
+
+	for _, format := range []string{FormatYAML, FormatJSON, FormatTOML} {
+		t.Run(format, func(t *testing.T) {
+			b, err := marshal(format, cfg)
+			require.NoError(t, err)
+
+			// same keys in all the formats, empty values are omitted
+			v := viper.New()
+			v.SetConfigType(format)
+			require.NoError(t, v.ReadConfig(bytes.NewReader(b)))
+
+			assert.Equal(t, exp, v.AllSettings())
+		})
+	}
+}
+
+func TestSaveFormat(t *testing.T) {
+	fs := memConfigFs(t, map[string]string{
+		"/home/user/.tigris/test.yaml": "url: old\n",
+	})
+
+	t.Setenv("HOME", "/home/user")
+
+	savedFormat, savedLoaded := Format, loadedFormat
+
+	defer func() { Format, loadedFormat = savedFormat, savedLoaded }()
+
+	Format, loadedFormat = FormatJSON, FormatYAML
+
+	require.NoError(t, Save("test", Config{URL: "new"}))
+
+	// existing file of the other format is backed up, so as it doesn't win the lookup
+	file, format := findConfigFile("test")
+	assert.Equal(t, "/home/user/.tigris/test.json", file)
+	assert.Equal(t, FormatJSON, format)
+
+	b, err := afero.ReadFile(fs, file)
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"url":"new"}`, string(b))
+
+	b, err = afero.ReadFile(fs, "/home/user/.tigris/test.yaml.bak")
+	require.NoError(t, err)
+	assert.Equal(t, "url: old\n", string(b))
+}
+
+// loadTestConfig loads the config the same way as the main does,
+// then parses the command line flags bound to the config fields.
+func loadTestConfig(t *testing.T, args ...string) Config {
+	t.Helper()
+
+	viper.Reset()
+	t.Cleanup(viper.Reset)
+
+	var cfg Config
+
+	Load("test", &cfg)
+
+	flags := pflag.NewFlagSet("test", pflag.ContinueOnError)
+	flags.StringVar(&cfg.Project, "project", cfg.Project, "")
+	flags.StringVar(&cfg.URL, "url", cfg.URL, "")
+
+	require.NoError(t, flags.Parse(args))
+
+	return cfg
+}
+
+func TestNoConfigFile(t *testing.T) {
+	memConfigFs(t, map[string]string{
+		"/etc/tigris/test.yaml": "url: file_url\nproject: file_proj\nbranch: file_branch\n",
+	})
+
+	cases := []struct {
+		name   string
+		env    map[string]string
+		args   []string
+		url    string
+		proj   string
+		branch string
+	}{
+		{"config file", nil, nil, "file_url", "file_proj", "file_branch"},
+		{"config file and env", map[string]string{"TIGRIS_URL": "env_url"}, nil,
+			"env_url", "file_proj", "file_branch"},
+		{"config file disabled", map[string]string{NoConfigFileEnv: "1"}, nil, "", "", ""},
+		{"config file disabled by true", map[string]string{NoConfigFileEnv: "true"}, nil, "", "", ""},
+		{"config file not disabled by false", map[string]string{NoConfigFileEnv: "false"}, nil,
+			"file_url", "file_proj", "file_branch"},
+		{"env applies", map[string]string{NoConfigFileEnv: "1", "TIGRIS_URL": "env_url"}, nil,
+			"env_url", "", ""},
+		{"flags apply", map[string]string{NoConfigFileEnv: "1", "TIGRIS_URL": "env_url"},
+			[]string{"--url=flag_url", "--project=flag_proj"}, "flag_url", "flag_proj", ""},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			for k, v := range c.env {
+				t.Setenv(k, v)
+			}
+
+			cfg := loadTestConfig(t, c.args...)
+			assert.Equal(t, c.url, cfg.URL)
+			assert.Equal(t, c.proj, cfg.Project)
+			assert.Equal(t, c.branch, cfg.Branch)
+		})
+	}
+}
+
+func TestProjectPrecedence(t *testing.T) {
+	memConfigFs(t, map[string]string{
+		"/etc/tigris/test.yaml": "project: file_proj\n",
+	})
+
+	savedConfig, savedProject := DefaultConfig, Project
+
+	t.Cleanup(func() { DefaultConfig, Project = savedConfig, savedProject })
+
+	cases := []struct {
+		name string
+		env  map[string]string
+		args []string
+		exp  string
+	}{
+		{"config file", nil, nil, "file_proj"},
+		{"tigrisdb env", map[string]string{ProjectEnv: "tigrisdb_proj"}, nil, "tigrisdb_proj"},
+		{"tigris env", map[string]string{"TIGRIS_PROJECT": "tigris_proj"}, nil, "tigris_proj"},
+		{"tigris env over tigrisdb env", map[string]string{
+			"TIGRIS_PROJECT": "tigris_proj", ProjectEnv: "tigrisdb_proj",
+		}, nil, "tigris_proj"},
+		{"flag", map[string]string{
+			"TIGRIS_PROJECT": "tigris_proj", ProjectEnv: "tigrisdb_proj",
+		}, []string{"--project=flag_proj"}, "flag_proj"},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			for k, v := range c.env {
+				t.Setenv(k, v)
+			}
+
+			DefaultConfig = loadTestConfig(t, c.args...)
+			Project = ""
+
+			assert.Equal(t, c.exp, GetProjectName())
+		})
+	}
+}
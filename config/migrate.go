@@ -0,0 +1,161 @@
+// Copyright 2022-2023 Tigris Data, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package config
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/spf13/afero"
+	"github.com/spf13/viper"
+)
+
+var ErrNoConfigFile = fmt.Errorf("config file not found")
+
+// rename is the transformation of the deprecated config key to its current equivalent.
+// Nested keys are specified using dot notation.
+type rename struct {
+	from string
+	to   string
+}
+
+// migrations lists the config keys renamed since the config file was introduced.
+// No keys have been renamed so far, add an entry here when renaming the key
+// of the Config, so as the existing config files keep working after "config migrate".
+var migrations []rename
+
+// Migration describes single transformation of the config file.
+type Migration struct {
+	From string
+	To   string
+	// Dropped is set when the current key is already set, so the deprecated key is removed.
+	Dropped bool
+}
+
+func (m Migration) String() string {
+	if m.Dropped {
+		return fmt.Sprintf("removed %s, %s is already set", m.From, m.To)
+	}
+
+	return fmt.Sprintf("renamed %s to %s", m.From, m.To)
+}
+
+func lookupKey(m map[string]any, path []string) (map[string]any, bool) {
+	for _, k := range path[:len(path)-1] {
+		n, ok := m[k].(map[string]any)
+		if !ok {
+			return nil, false
+		}
+
+		m = n
+	}
+
+	_, ok := m[path[len(path)-1]]
+
+	return m, ok
+}
+
+func setKey(m map[string]any, path []string, v any) {
+	for _, k := range path[:len(path)-1] {
+		n, ok := m[k].(map[string]any)
+		if !ok {
+			n = map[string]any{}
+			m[k] = n
+		}
+
+		m = n
+	}
+
+	m[path[len(path)-1]] = v
+}
+
+// migrate transforms deprecated keys of the config in place.
+func migrate(cfg map[string]any, rules []rename) []Migration {
+	var res []Migration
+
+	for _, r := range rules {
+		fromPath := strings.Split(r.from, ".")
+
+		parent, ok := lookupKey(cfg, fromPath)
+		if !ok {
+			continue
+		}
+
+		fromKey := fromPath[len(fromPath)-1]
+		v := parent[fromKey]
+
+		delete(parent, fromKey)
+
+		toPath := strings.Split(r.to, ".")
+
+		if _, ok := lookupKey(cfg, toPath); ok {
+			res = append(res, Migration{From: r.from, To: r.to, Dropped: true})
+			continue
+		}
+
+		setKey(cfg, toPath, v)
+
+		res = append(res, Migration{From: r.from, To: r.to})
+	}
+
+	return res
+}
+
+// Migrate transforms deprecated keys of the config file found in the config path
+// to their current equivalents. Unless dryRun is set, the original file is backed up
+// to the file with .bak extension and the migrated config is written back in the same format.
+// Returns the path of the config file and the list of the transformations.
+func Migrate(name string, dryRun bool) (string, []Migration, error) {
+	file, format := findConfigFile(name)
+	if file == "" {
+		return "", nil, ErrNoConfigFile
+	}
+
+	v := viper.New()
+	v.SetFs(configFs)
+	v.SetConfigFile(file)
+	v.SetConfigType(format)
+
+	if err := v.ReadInConfig(); err != nil {
+		return "", nil, err
+	}
+
+	cfg := v.AllSettings()
+
+	res := migrate(cfg, migrations)
+	if len(res) == 0 || dryRun {
+		return file, res, nil
+	}
+
+	b, err := marshal(format, cfg)
+	if err != nil {
+		return "", nil, err
+	}
+
+	orig, err := afero.ReadFile(configFs, file)
+	if err != nil {
+		return "", nil, err
+	}
+
+	if err = afero.WriteFile(configFs, file+".bak", orig, 0o600); err != nil {
+		return "", nil, err
+	}
+
+	if err = afero.WriteFile(configFs, file, b, 0o600); err != nil {
+		return "", nil, err
+	}
+
+	return file, res, nil
+}
@@ -0,0 +1,146 @@
+// Copyright 2022-2023 Tigris Data, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMigrate(t *testing.T) {
+	rules := []rename{
+		{"old_url", "url"},
+		{"log_level", "log.level"},
+		{"log.old_format", "log.format"},
+	}
+
+	cases := []struct {
+		name string
+		cfg  map[string]any
+		exp  map[string]any
+		res  []Migration
+	}{
+		{
+			name: "no-op",
+			cfg:  map[string]any{"url": "u1", "log": map[string]any{"level": "debug"}},
+			exp:  map[string]any{"url": "u1", "log": map[string]any{"level": "debug"}},
+		},
+		{
+			name: "rename",
+			cfg:  map[string]any{"old_url": "u1"},
+			exp:  map[string]any{"url": "u1"},
+			res:  []Migration{{From: "old_url", To: "url"}},
+		},
+		{
+			name: "rename to nested",
+			cfg:  map[string]any{"log_level": "debug", "log": map[string]any{"format": "json"}},
+			exp:  map[string]any{"log": map[string]any{"level": "debug", "format": "json"}},
+			res:  []Migration{{From: "log_level", To: "log.level"}},
+		},
+		{
+			name: "rename nested",
+			cfg:  map[string]any{"log": map[string]any{"old_format": "json"}},
+			exp:  map[string]any{"log": map[string]any{"format": "json"}},
+			res:  []Migration{{From: "log.old_format", To: "log.format"}},
+		},
+		{
+			name: "conflict",
+			cfg:  map[string]any{"old_url": "u1", "url": "u2"},
+			exp:  map[string]any{"url": "u2"},
+			res:  []Migration{{From: "old_url", To: "url", Dropped: true}},
+		},
+		{
+			name: "nested conflict",
+			cfg:  map[string]any{"log_level": "debug", "log": map[string]any{"level": "info"}},
+			exp:  map[string]any{"log": map[string]any{"level": "info"}},
+			res:  []Migration{{From: "log_level", To: "log.level", Dropped: true}},
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			res := migrate(c.cfg, rules)
+			assert.Equal(t, c.res, res)
+			assert.Equal(t, c.exp, c.cfg)
+		})
+	}
+}
+
+func TestMigrateFile(t *testing.T) {
+	dir := t.TempDir()
+
+	savedPath := configPath
+	configPath = []string{dir}
+
+	defer func() { configPath = savedPath }()
+
+	_, _, err := Migrate("test-config", false)
+	require.ErrorIs(t, err, ErrNoConfigFile)
+
+	orig := []byte("url: u1\nproject: p1\n")
+	file := filepath.Join(dir, "test-config.yaml")
+	require.NoError(t, os.WriteFile(file, orig, 0o600))
+
+	// none of the keys is deprecated, so the file is left untouched
+	f, res, err := Migrate("test-config", false)
+	require.NoError(t, err)
+	assert.Equal(t, file, f)
+	assert.Empty(t, res)
+
+	b, err := os.ReadFile(file)
+	require.NoError(t, err)
+	assert.Equal(t, orig, b)
+
+	_, err = os.Stat(file + ".bak")
+	assert.True(t, os.IsNotExist(err))
+}
+
+func TestMigrateFileRename(t *testing.T) {
+	dir := t.TempDir()
+
+	savedPath, savedMigrations := configPath, migrations
+	configPath = []string{dir}
+	migrations = []rename{{"old_url", "url"}}
+
+	defer func() { configPath, migrations = savedPath, savedMigrations }()
+
+	orig := []byte(`{"old_url":"u1","project":"p1"}`)
+	file := filepath.Join(dir, "test-config.json")
+	require.NoError(t, os.WriteFile(file, orig, 0o600))
+
+	_, res, err := Migrate("test-config", true)
+	require.NoError(t, err)
+	assert.Equal(t, []Migration{{From: "old_url", To: "url"}}, res)
+
+	b, err := os.ReadFile(file)
+	require.NoError(t, err)
+	assert.Equal(t, orig, b, "dry run doesn't modify the file")
+
+	_, res, err = Migrate("test-config", false)
+	require.NoError(t, err)
+	assert.Equal(t, []Migration{{From: "old_url", To: "url"}}, res)
+
+	b, err = os.ReadFile(file)
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"url":"u1","project":"p1"}`, string(b))
+
+	b, err = os.ReadFile(file + ".bak")
+	require.NoError(t, err)
+	assert.Equal(t, orig, b)
+}
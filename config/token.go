@@ -0,0 +1,72 @@
+// Copyright 2022-2023 Tigris Data, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package config
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+)
+
+var (
+	ErrTokenFileNotFound = fmt.Errorf("token file not found")
+	ErrTokenFileEmpty    = fmt.Errorf("token file is empty")
+
+	// tokenFromFile is the token last read from the TokenFile.
+	// It's not written to the config file by Save.
+	tokenFromFile string
+)
+
+// ReadTokenFile sets the token of the default config from the TokenFile,
+// like the Kubernetes secret mounted as a file.
+// Surrounding whitespace is trimmed. Returns true if the token has changed.
+func ReadTokenFile() (bool, error) {
+	name := DefaultConfig.TokenFile
+	if name == "" {
+		return false, nil
+	}
+
+	b, err := os.ReadFile(name)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return false, fmt.Errorf("%w: %s", ErrTokenFileNotFound, name)
+		}
+
+		return false, err
+	}
+
+	token := strings.TrimSpace(string(b))
+	if token == "" {
+		return false, fmt.Errorf("%w: %s", ErrTokenFileEmpty, name)
+	}
+
+	changed := token != DefaultConfig.Token
+
+	DefaultConfig.Token = token
+	tokenFromFile = token
+
+	return changed, nil
+}
+
+// withoutFileToken removes the token read from the TokenFile from the config to be saved.
+func withoutFileToken(config any) any {
+	if c, ok := config.(Config); ok && tokenFromFile != "" && c.Token == tokenFromFile {
+		c.Token = ""
+		return c
+	}
+
+	return config
+}
@@ -0,0 +1,88 @@
+// Copyright 2022-2023 Tigris Data, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReadTokenFile(t *testing.T) {
+	dir := t.TempDir()
+
+	write := func(name, data string) string {
+		file := filepath.Join(dir, name)
+		require.NoError(t, os.WriteFile(file, []byte(data), 0o600))
+
+		return file
+	}
+
+	cases := []struct {
+		name     string
+		file     string
+		token    string
+		changed  bool
+		expToken string
+		err      error
+	}{
+		{"no token file", "", "tok0", false, "tok0", nil},
+		{"not found", filepath.Join(dir, "missing"), "tok0", false, "tok0", ErrTokenFileNotFound},
+		{"empty", write("empty", " \n"), "tok0", false, "tok0", ErrTokenFileEmpty},
+		{"trimmed", write("trimmed", "\n tok1 \n"), "", true, "tok1", nil},
+		{"unchanged", write("unchanged", "tok1\n"), "tok1", false, "tok1", nil},
+		{"rotated", write("rotated", "tok2"), "tok1", true, "tok2", nil},
+	}
+
+	saved := DefaultConfig
+
+	defer func() {
+		DefaultConfig = saved
+		tokenFromFile = ""
+	}()
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			DefaultConfig.TokenFile = c.file
+			DefaultConfig.Token = c.token
+
+			changed, err := ReadTokenFile()
+			require.ErrorIs(t, err, c.err)
+			assert.Equal(t, c.changed, changed)
+			assert.Equal(t, c.expToken, DefaultConfig.Token)
+		})
+	}
+}
+
+func TestWithoutFileToken(t *testing.T) {
+	defer func() { tokenFromFile = "" }()
+
+	tokenFromFile = ""
+	assert.Equal(t, Config{Token: "tok1"}, withoutFileToken(Config{Token: "tok1"}))
+
+	tokenFromFile = "tok1"
+
+	// token read from the file is not saved
+	assert.Equal(t, Config{TokenFile: "/f"}, withoutFileToken(Config{Token: "tok1", TokenFile: "/f"}))
+
+	// token set after reading the file is saved
+	assert.Equal(t, Config{Token: "tok2"}, withoutFileToken(Config{Token: "tok2"}))
+
+	// only Config values are stripped
+	assert.Equal(t, "tok1", withoutFileToken("tok1"))
+}
@@ -0,0 +1,170 @@
+// Copyright 2022-2023 Tigris Data, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package iterate
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"time"
+
+	"github.com/schollz/progressbar/v3"
+	"github.com/tigrisdata/tigris-cli/util"
+)
+
+// FlushInterval, when set, flushes partially filled batch after the interval
+// since the first document of the batch has been read, so the documents
+// of slow streams are processed without waiting for the batch to fill up.
+var FlushInterval time.Duration
+
+type nextDoc struct {
+	doc json.RawMessage
+	err error
+}
+
+// DocsInput processes the documents returned by the next function in batches,
+// until it returns io.EOF.
+func DocsInput(ctx context.Context, args []string, next func() (json.RawMessage, error),
+	fn func(ctx2 context.Context, args []string, docs []json.RawMessage) error,
+) error {
+	done, err := startInput(ctx)
+	if err != nil {
+		return err
+	}
+
+	defer done()
+
+	return iterateDocs(ctx, args, next, fn)
+}
+
+// iterateDocs batches the documents returned by the next function,
+// until it returns io.EOF. Batches are flushed when BatchSize is reached,
+// or after FlushInterval, if set.
+func iterateDocs(ctx context.Context, args []string, next func() (json.RawMessage, error),
+	fn func(ctx2 context.Context, args []string, docs []json.RawMessage) error,
+) error {
+	var bar *progressbar.ProgressBar
+
+	if showProgress() {
+		bar = util.NewProgressBar(-1)
+	}
+
+	flush := func(docs []json.RawMessage) error {
+		if len(docs) == 0 {
+			return nil
+		}
+
+		if err := processBatch(ctx, args, docs, fn); err != nil {
+			return err
+		}
+
+		if bar != nil {
+			_ = bar.Add(len(docs))
+		}
+
+		return nil
+	}
+
+	if FlushInterval > 0 {
+		return iterateDocsTimed(next, flush)
+	}
+
+	for {
+		docs := make([]json.RawMessage, 0, BatchSize)
+
+		var err error
+
+		for int32(len(docs)) < BatchSize {
+			var doc json.RawMessage
+
+			if doc, err = next(); err != nil {
+				break
+			}
+
+			docs = append(docs, doc)
+		}
+
+		if err != nil && !errors.Is(err, io.EOF) {
+			return err
+		}
+
+		if err := flush(docs); err != nil {
+			return err
+		}
+
+		if err != nil {
+			return nil
+		}
+	}
+}
+
+// iterateDocsTimed reads the documents in the background,
+// so as the batch can be flushed by the timer, while the read is blocked.
+func iterateDocsTimed(next func() (json.RawMessage, error), flush func(docs []json.RawMessage) error) error {
+	ch := make(chan nextDoc)
+	stop := make(chan struct{})
+
+	defer close(stop)
+
+	go func() {
+		for {
+			doc, err := next()
+
+			select {
+			case ch <- nextDoc{doc: doc, err: err}:
+			case <-stop:
+				return
+			}
+
+			if err != nil {
+				return
+			}
+		}
+	}()
+
+	docs := make([]json.RawMessage, 0, BatchSize)
+
+	var timer <-chan time.Time
+
+	for {
+		select {
+		case d := <-ch:
+			if errors.Is(d.err, io.EOF) {
+				return flush(docs)
+			} else if d.err != nil {
+				return d.err
+			}
+
+			if len(docs) == 0 {
+				timer = time.After(FlushInterval)
+			}
+
+			docs = append(docs, d.doc)
+
+			if int32(len(docs)) < BatchSize {
+				continue
+			}
+		case <-timer:
+		}
+
+		if err := flush(docs); err != nil {
+			return err
+		}
+
+		docs = make([]json.RawMessage, 0, BatchSize)
+		timer = nil
+	}
+}
@@ -0,0 +1,55 @@
+// Copyright 2022-2023 Tigris Data, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package iterate
+
+import (
+	"time"
+)
+
+// BatchStat is the timing of the processed batch.
+type BatchStat struct {
+	Documents       int     `json:"documents"`
+	StartedAt       string  `json:"started_at"`
+	DurationSeconds float64 `json:"duration_seconds"`
+	Error           string  `json:"error,omitempty"`
+}
+
+var (
+	// RecordBatches enables collection of the batch timings, returned by BatchStats.
+	RecordBatches bool
+)
+
+// BatchStats returns the timings of the batches processed by the last iteration.
+func BatchStats() []BatchStat {
+	return defaultRun.batchStats
+}
+
+func (r *Run) recordBatch(docs int, start time.Time, err error) {
+	if !RecordBatches {
+		return
+	}
+
+	s := BatchStat{
+		Documents:       docs,
+		StartedAt:       start.UTC().Format(time.RFC3339Nano),
+		DurationSeconds: time.Since(start).Seconds(),
+	}
+
+	if err != nil {
+		s.Error = err.Error()
+	}
+
+	r.batchStats = append(r.batchStats, s)
+}
@@ -0,0 +1,61 @@
+// Copyright 2022-2023 Tigris Data, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package iterate
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBatchStats(t *testing.T) {
+	RecordBatches = true
+	BatchSize = 2
+
+	defer func() {
+		RecordBatches = false
+		BatchSize = 100
+		defaultRun.batchStats = nil
+	}()
+
+	defaultRun.batchStats = nil
+
+	errBatch := fmt.Errorf("batch failed")
+
+	err := readerInput(context.Background(), nil, strings.NewReader(`{"id":1} {"id":2} {"id":3}`),
+		func(ctx context.Context, args []string, docs []json.RawMessage) error {
+			if len(docs) == 1 {
+				return errBatch
+			}
+
+			return nil
+		})
+	require.ErrorIs(t, err, errBatch)
+
+	stats := BatchStats()
+	require.Len(t, stats, 2)
+
+	assert.Equal(t, 2, stats[0].Documents)
+	assert.Empty(t, stats[0].Error)
+	assert.NotEmpty(t, stats[0].StartedAt)
+
+	assert.Equal(t, 1, stats[1].Documents)
+	assert.Equal(t, "batch failed", stats[1].Error)
+}
@@ -0,0 +1,44 @@
+// Copyright 2022-2023 Tigris Data, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package iterate
+
+import (
+	"fmt"
+)
+
+const (
+	// DefaultInputBufferSize is large enough to read big files in few system calls,
+	// while keeping the memory usage negligible comparing to the batches of the documents.
+	DefaultInputBufferSize = 64 * 1024
+
+	// MinInputBufferSize is the minimal size of the input buffer.
+	// Smaller buffers only add system calls, without saving memory.
+	MinInputBufferSize = 4096
+)
+
+var (
+	// InputBufferSize is the size of the buffer of the input reader.
+	InputBufferSize = DefaultInputBufferSize
+
+	ErrInputBufferSizeTooSmall = fmt.Errorf("input buffer size should be at least %d bytes", MinInputBufferSize)
+)
+
+func ValidateInputBufferSize() error {
+	if InputBufferSize < MinInputBufferSize {
+		return ErrInputBufferSizeTooSmall
+	}
+
+	return nil
+}
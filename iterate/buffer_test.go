@@ -0,0 +1,54 @@
+// Copyright 2022-2023 Tigris Data, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package iterate
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestInputBufferSize(t *testing.T) {
+	defer func() { InputBufferSize = DefaultInputBufferSize }()
+
+	require.NoError(t, ValidateInputBufferSize())
+
+	InputBufferSize = MinInputBufferSize - 1
+	require.ErrorIs(t, ValidateInputBufferSize(), ErrInputBufferSizeTooSmall)
+
+	InputBufferSize = MinInputBufferSize
+
+	// documents larger than the buffer
+	var in strings.Builder
+
+	for i := 0; i < 10; i++ {
+		_, _ = fmt.Fprintf(&in, "{\"id\":%d,\"s\":\"%s\"}\n", i, strings.Repeat("a", MinInputBufferSize))
+	}
+
+	var docs []json.RawMessage
+
+	err := readerInput(context.Background(), nil, strings.NewReader(in.String()),
+		func(ctx context.Context, args []string, d []json.RawMessage) error {
+			docs = append(docs, d...)
+			return nil
+		})
+	require.NoError(t, err)
+	assert.Len(t, docs, 10)
+}
@@ -0,0 +1,45 @@
+// Copyright 2022-2023 Tigris Data, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package iterate
+
+import (
+	"bytes"
+	"encoding/json"
+)
+
+// Compact enables removing insignificant whitespace from the documents.
+var Compact bool
+
+// compactDocs removes insignificant whitespace from the documents.
+// The order of the fields and the values are preserved as is.
+func compactDocs(docs []json.RawMessage) error {
+	if !Compact {
+		return nil
+	}
+
+	for k, doc := range docs {
+		var buf bytes.Buffer
+
+		if err := json.Compact(&buf, doc); err != nil {
+			return err
+		}
+
+		if buf.Len() != len(doc) {
+			docs[k] = buf.Bytes()
+		}
+	}
+
+	return nil
+}
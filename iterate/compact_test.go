@@ -0,0 +1,39 @@
+// Copyright 2022-2023 Tigris Data, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package iterate
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCompact(t *testing.T) {
+	Compact = true
+
+	defer func() { Compact = false }()
+
+	docs := []json.RawMessage{
+		json.RawMessage("{\n  \"z\": 1,\n  \"a\": {\"s\": \"x  y\"},\n  \"n\": 1.50\n}"),
+		json.RawMessage(`{"id":2}`),
+	}
+
+	require.NoError(t, compactDocs(docs))
+
+	assert.Equal(t, `{"z":1,"a":{"s":"x  y"},"n":1.50}`, string(docs[0]))
+	assert.Equal(t, `{"id":2}`, string(docs[1]))
+}
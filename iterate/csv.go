@@ -21,43 +21,82 @@ import (
 	"errors"
 	"fmt"
 	"io"
-	"os"
 	"strconv"
 	"strings"
+	"unicode/utf8"
 
-	"github.com/schollz/progressbar/v3"
 	"github.com/tigrisdata/tigris-cli/util"
 )
 
 var (
-	CSVDelimiter        rune
-	CSVTrimLeadingSpace bool
-	CSVComment          rune
-	CSVNoHeader         bool
+	// CSVArrayFields is the list of the columns split into arrays in the form of name:delimiter.
+	CSVArrayFields []string
 
-	ErrDelimiterTooLong = fmt.Errorf("delimiter should be one character")
-	ErrCommentTooLong   = fmt.Errorf("comment should be one character")
+	ErrCommentTooLong        = fmt.Errorf("comment should be one character")
+	ErrInvalidCSVArrayField  = fmt.Errorf("invalid CSV array field definition. expected name:delimiter")
+	ErrCSVArrayFieldNotFound = fmt.Errorf("CSV array field not found in the header")
 )
 
+// csvDelimiterRune replaces multi-character delimiter in the input.
+// It's ASCII unit separator, which is not expected in the text data.
+const csvDelimiterRune = '\x1f'
+
+// CSVOptions are the options of the CSV input of the run, set by Configure.
+type CSVOptions struct {
+	Delimiter        rune
+	TrimLeadingSpace bool
+	Comment          rune
+	NoHeader         bool
+
+	// delimiterSeq is the multi-character delimiter translated by delimiterReader.
+	delimiterSeq []byte
+}
+
+// unescapeDelimiter interprets Go escape sequences, like \t, in the delimiter.
+func unescapeDelimiter(s string) string {
+	if u, err := strconv.Unquote(`"` + s + `"`); err == nil {
+		return u
+	}
+
+	return s
+}
+
+// CSVConfigure sets the options of the CSV input of the default run.
 func CSVConfigure(delimiter string, comment string, trimLeadingSpace bool, noHeader bool) error {
+	return defaultRun.CSV.Configure(delimiter, comment, trimLeadingSpace, noHeader)
+}
+
+// Configure sets the options of the CSV input.
+func (o *CSVOptions) Configure(delimiter string, comment string, trimLeadingSpace bool, noHeader bool) error {
+	o.Delimiter = rune(0)
+	o.delimiterSeq = nil
+
 	if delimiter != "" {
-		if len(delimiter) > 1 {
-			return ErrDelimiterTooLong
-		}
+		delimiter = unescapeDelimiter(delimiter)
 
-		CSVDelimiter = rune(delimiter[0])
+		if utf8.RuneCountInString(delimiter) > 1 {
+			o.delimiterSeq = []byte(delimiter)
+			o.Delimiter = csvDelimiterRune
+
+			util.Stderrf("warning: multi-character delimiter %q is translated while reading the input, "+
+				"this slows down the import\n", delimiter)
+		} else {
+			o.Delimiter, _ = utf8.DecodeRuneInString(delimiter)
+		}
 	}
 
+	o.Comment = rune(0)
+
 	if comment != "" {
 		if len(comment) > 1 {
 			return ErrCommentTooLong
 		}
 
-		CSVComment = rune(comment[0])
+		o.Comment = rune(comment[0])
 	}
 
-	CSVTrimLeadingSpace = trimLeadingSpace
-	CSVNoHeader = noHeader
+	o.TrimLeadingSpace = trimLeadingSpace
+	o.NoHeader = noHeader
 
 	return nil
 }
@@ -74,66 +113,149 @@ func findKey(d map[string]any, names [][]string, key int) map[string]any {
 	return d
 }
 
-func readCSVBatch(reader *csv.Reader, names [][]string, batchSize int) []json.RawMessage {
-	var docs []json.RawMessage
+// csvFieldsOrder returns the order of the document fields as they appear in the header.
+func csvFieldsOrder(names [][]string) *util.OrderedObject {
+	order := util.NewOrderedObject()
 
-	for i := 0; i < batchSize; i++ {
-		row, err := reader.Read()
-		if errors.Is(err, io.EOF) {
-			return docs
+	for _, n := range names {
+		o := order
+		for i := 0; i < len(n)-1; i++ {
+			o = o.Object(n[i])
 		}
 
-		util.Fatal(err, "read csv row")
+		o.Set(n[len(n)-1], nil)
+	}
 
-		fields := make(map[string]any)
+	return order
+}
+
+// csvValue converts the value of the CSV column to number, boolean or null, if possible.
+func csvValue(v string) any {
+	if f, err := strconv.ParseFloat(v, 64); err == nil {
+		return f
+	}
+
+	switch strings.TrimSpace(v) {
+	case "null":
+		return nil
+	case "true":
+		return true
+	case "false":
+		return false
+	}
 
-		for k, v := range row {
-			d := findKey(fields, names, k)
+	return v
+}
 
-			var f float64
+// csvArray splits the value of the CSV column into array. Empty value is converted to empty array.
+func csvArray(v string, sep string) []any {
+	arr := []any{}
 
-			f, err = strconv.ParseFloat(v, 64)
+	if strings.TrimSpace(v) == "" {
+		return arr
+	}
 
-			switch {
-			case err == nil:
-				d[names[k][len(names[k])-1]] = f
-			case strings.TrimSpace(v) == "null":
-				d[names[k][len(names[k])-1]] = nil
-			case strings.TrimSpace(v) == "true":
-				d[names[k][len(names[k])-1]] = true
-			case strings.TrimSpace(v) == "false":
-				d[names[k][len(names[k])-1]] = false
-			default:
-				d[names[k][len(names[k])-1]] = v
+	for _, e := range strings.Split(v, sep) {
+		arr = append(arr, csvValue(strings.TrimSpace(e)))
+	}
+
+	return arr
+}
+
+// csvArraySeps returns the sub-delimiters of the CSVArrayFields columns by the column index.
+func csvArraySeps(headers []string) ([]string, error) {
+	if len(CSVArrayFields) == 0 {
+		return nil, nil
+	}
+
+	seps := make([]string, len(headers))
+
+	for _, f := range CSVArrayFields {
+		name, sep, ok := strings.Cut(f, ":")
+		if sep = unescapeDelimiter(sep); !ok || name == "" || sep == "" {
+			return nil, fmt.Errorf("%w: %s", ErrInvalidCSVArrayField, f)
+		}
+
+		found := false
+
+		for k, h := range headers {
+			if h == name {
+				seps[k] = sep
+				found = true
 			}
 		}
 
-		b, err := json.Marshal(fields)
-		util.Fatal(err, "marshal")
+		if !found {
+			return nil, fmt.Errorf("%w: %s", ErrCSVArrayFieldNotFound, name)
+		}
+	}
+
+	return seps, nil
+}
+
+// readCSVDoc reads the row of the CSV and converts it to the document.
+func readCSVDoc(reader *csv.Reader, names [][]string, arraySeps []string, order *util.OrderedObject,
+) (json.RawMessage, error) {
+	row, err := reader.Read()
+	if errors.Is(err, io.EOF) || errors.Is(err, ErrInterrupted) {
+		return nil, err
+	}
+
+	util.Fatal(err, "read csv row")
+
+	fields := make(map[string]any)
+
+	for k, v := range row {
+		d := findKey(fields, names, k)
+
+		if k < len(arraySeps) && arraySeps[k] != "" {
+			d[names[k][len(names[k])-1]] = csvArray(v, arraySeps[k])
+			continue
+		}
+
+		d[names[k][len(names[k])-1]] = csvValue(v)
+	}
+
+	b, err := json.Marshal(fields)
+	util.Fatal(err, "marshal")
 
-		docs = append(docs, b)
+	if util.PreserveOrder {
+		b, err = util.Reorder(b, order)
+		util.Fatal(err, "restore fields order")
 	}
 
-	return docs
+	return b, nil
 }
 
 func iterateCSVStream(ctx context.Context, args []string, r io.Reader, fn func(ctx2 context.Context, args []string,
 	docs []json.RawMessage) error,
 ) error {
+	o := &RunFrom(ctx).CSV
+
+	if o.delimiterSeq != nil {
+		r = newDelimiterReader(r, o.delimiterSeq, []byte(string(csvDelimiterRune)))
+	}
+
 	csvReader := csv.NewReader(r)
 
-	if CSVComment != rune(0) {
-		csvReader.Comment = CSVComment
-		csvReader.Comment = ';'
+	// Quoted fields spanning multiple lines are read as one record.
+	// Comment character is only recognized at the beginning of the record,
+	// so the continuation lines of the quoted fields are not treated as comments.
+	if o.Comment != rune(0) {
+		csvReader.Comment = o.Comment
 	}
 
-	if CSVDelimiter != rune(0) {
-		csvReader.Comma = CSVDelimiter
+	if o.Delimiter != rune(0) {
+		csvReader.Comma = o.Delimiter
 	}
 
-	csvReader.TrimLeadingSpace = CSVTrimLeadingSpace
+	csvReader.TrimLeadingSpace = o.TrimLeadingSpace
 
 	headers, err := csvReader.Read()
+	if errors.Is(err, ErrInterrupted) {
+		return err
+	}
+
 	util.Fatal(err, "read CSV headers: %+v", headers)
 
 	numFields := len(headers)
@@ -143,25 +265,14 @@ func iterateCSVStream(ctx context.Context, args []string, r io.Reader, fn func(c
 		names[k] = strings.Split(v, ".")
 	}
 
-	var bar *progressbar.ProgressBar
-
-	for {
-		docs := readCSVBatch(csvReader, names, int(BatchSize))
-
-		if util.IsTTY(os.Stdout) {
-			bar = progressbar.Default(-1)
-		}
-
-		if len(docs) == 0 {
-			break
-		} else if err := varyBatch(ctx, args, docs, fn); err != nil {
-			return err
-		}
-
-		if util.IsTTY(os.Stdout) {
-			_ = bar.Add(len(docs))
-		}
+	arraySeps, err := csvArraySeps(headers)
+	if err != nil {
+		return err
 	}
 
-	return nil
+	order := csvFieldsOrder(names)
+
+	return iterateDocs(ctx, args, func() (json.RawMessage, error) {
+		return readCSVDoc(csvReader, names, arraySeps, order)
+	}, fn)
 }
@@ -0,0 +1,106 @@
+// Copyright 2022-2023 Tigris Data, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package iterate
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCSVArrayFields(t *testing.T) {
+	Format = FormatCSV
+
+	defer func() {
+		Format = ""
+		CSVArrayFields = nil
+	}()
+
+	readCSV := func(in string) ([]json.RawMessage, error) {
+		var docs []json.RawMessage
+
+		err := readerInput(context.Background(), nil, strings.NewReader(in),
+			func(ctx context.Context, args []string, d []json.RawMessage) error {
+				docs = append(docs, d...)
+				return nil
+			})
+
+		return docs, err
+	}
+
+	CSVArrayFields = []string{"tags:;", "a.ids:|"}
+
+	docs, err := readCSV("name,tags,a.ids\nalice,x;y; z,1|2\nbob,,3\n")
+	require.NoError(t, err)
+
+	require.Len(t, docs, 2)
+	assert.JSONEq(t, `{"name":"alice","tags":["x","y","z"],"a":{"ids":[1,2]}}`, string(docs[0]))
+	assert.JSONEq(t, `{"name":"bob","tags":[],"a":{"ids":[3]}}`, string(docs[1]))
+
+	CSVArrayFields = []string{"other:;"}
+
+	_, err = readCSV("name,tags\nalice,x\n")
+	require.ErrorIs(t, err, ErrCSVArrayFieldNotFound)
+
+	CSVArrayFields = []string{"tags"}
+
+	_, err = readCSV("name,tags\nalice,x\n")
+	require.ErrorIs(t, err, ErrInvalidCSVArrayField)
+}
+
+func TestCSVMultilineRecords(t *testing.T) {
+	Format = FormatCSV
+
+	defer func() {
+		Format = ""
+		defaultRun.CSV.Comment = rune(0)
+		defaultRun.CSV.TrimLeadingSpace = false
+	}()
+
+	readCSV := func(in string) []json.RawMessage {
+		var docs []json.RawMessage
+
+		err := readerInput(context.Background(), nil, strings.NewReader(in),
+			func(ctx context.Context, args []string, d []json.RawMessage) error {
+				docs = append(docs, d...)
+				return nil
+			})
+		require.NoError(t, err)
+
+		return docs
+	}
+
+	in := "name,note\n" +
+		"alice, \"line 1\n# not a comment\n;also not a comment\"\n" +
+		"# comment\n" +
+		";bob,\"a,\r\nb\"\n"
+
+	require.NoError(t, CSVConfigure(",", "#", true, false))
+
+	docs := readCSV(in)
+	require.Len(t, docs, 2)
+	assert.JSONEq(t, `{"name":"alice","note":"line 1\n# not a comment\n;also not a comment"}`, string(docs[0]))
+	assert.JSONEq(t, `{"name":";bob","note":"a,\nb"}`, string(docs[1]))
+
+	require.NoError(t, CSVConfigure(",", "", true, false))
+
+	docs = readCSV("name,note\n# not a comment,\"a\nb\"\n")
+	require.Len(t, docs, 1)
+	assert.JSONEq(t, `{"name":"# not a comment","note":"a\nb"}`, string(docs[0]))
+}
@@ -0,0 +1,124 @@
+// Copyright 2022-2023 Tigris Data, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package iterate
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/tigrisdata/tigris-cli/util"
+)
+
+// Defaults is the list of default values of the fields in the form of name=value.
+// The value is parsed as JSON, values which are not valid JSON are used as strings.
+var Defaults []string
+
+var ErrInvalidDefault = fmt.Errorf("invalid default value definition. expected name=value")
+
+type defaultValue struct {
+	path  []string
+	value any
+}
+
+var defaultValues []defaultValue
+
+func parseDefaults() error {
+	defaultValues = nil
+
+	for _, def := range Defaults {
+		name, val, ok := strings.Cut(def, "=")
+		if name = strings.TrimSpace(name); !ok || name == "" {
+			return fmt.Errorf("%w: %s", ErrInvalidDefault, def)
+		}
+
+		var v any
+
+		dec := json.NewDecoder(strings.NewReader(val))
+		dec.UseNumber()
+
+		if err := dec.Decode(&v); err != nil || dec.More() {
+			v = val
+		}
+
+		defaultValues = append(defaultValues, defaultValue{path: strings.Split(name, "."), value: v})
+	}
+
+	return nil
+}
+
+// fieldParent returns the object containing the field of the path.
+// Missing parent objects of nested fields are created.
+// Returns nil if the parent is not an object.
+func fieldParent(m map[string]any, path []string) map[string]any {
+	for _, p := range path[:len(path)-1] {
+		switch v := m[p].(type) {
+		case map[string]any:
+			m = v
+		case nil:
+			n := make(map[string]any)
+			m[p] = n
+			m = n
+		default:
+			return nil
+		}
+	}
+
+	return m
+}
+
+// setDefault sets the value of the field, if it's absent or null.
+// Missing parent objects of nested fields are created.
+func setDefault(m map[string]any, path []string, value any) {
+	if m = fieldParent(m, path); m != nil && m[path[len(path)-1]] == nil {
+		m[path[len(path)-1]] = value
+	}
+}
+
+// applyDefaults sets default values of the fields which are absent or null in the documents.
+func applyDefaults(docs []json.RawMessage) error {
+	if len(defaultValues) == 0 {
+		return nil
+	}
+
+	for k, doc := range docs {
+		var m map[string]any
+
+		dec := json.NewDecoder(bytes.NewReader(doc))
+		dec.UseNumber()
+
+		if err := dec.Decode(&m); err != nil {
+			return err
+		}
+
+		for _, d := range defaultValues {
+			setDefault(m, d.path, d.value)
+		}
+
+		b, err := json.Marshal(m)
+		if err != nil {
+			return err
+		}
+
+		if b, err = util.KeepOrder(doc, b); err != nil {
+			return err
+		}
+
+		docs[k] = b
+	}
+
+	return nil
+}
@@ -0,0 +1,52 @@
+// Copyright 2022-2023 Tigris Data, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package iterate
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestApplyDefaults(t *testing.T) {
+	Defaults = []string{`status=active`, `score=0`, `tags=["a"]`, `address.country="US"`, `flag=true`}
+
+	defer func() {
+		Defaults = nil
+		defaultValues = nil
+	}()
+
+	require.NoError(t, parseDefaults())
+
+	docs := []json.RawMessage{
+		json.RawMessage(`{"id":1}`),
+		json.RawMessage(`{"id":2,"status":"blocked","score":null,"address":{"city":"x"},"flag":false}`),
+		json.RawMessage(`{"id":3,"address":"unknown"}`),
+	}
+
+	require.NoError(t, applyDefaults(docs))
+
+	assert.JSONEq(t, `{"id":1,"status":"active","score":0,"tags":["a"],"address":{"country":"US"},"flag":true}`,
+		string(docs[0]))
+	assert.JSONEq(t, `{"id":2,"status":"blocked","score":0,"tags":["a"],`+
+		`"address":{"city":"x","country":"US"},"flag":false}`, string(docs[1]))
+	assert.JSONEq(t, `{"id":3,"status":"active","score":0,"tags":["a"],"address":"unknown","flag":true}`,
+		string(docs[2]))
+
+	Defaults = []string{"=1"}
+	require.ErrorIs(t, parseDefaults(), ErrInvalidDefault)
+}
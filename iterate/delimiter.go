@@ -0,0 +1,96 @@
+// Copyright 2022-2023 Tigris Data, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package iterate
+
+import (
+	"bytes"
+	"errors"
+	"io"
+)
+
+const delimiterReadSize = 32 * 1024
+
+// delimiterReader replaces the separator sequence in the input with the replacement.
+// The separator inside the double-quoted CSV fields is kept as is.
+// The tail of the input, which can be the beginning of the separator, split
+// between the reads, is kept until the next read.
+type delimiterReader struct {
+	r   io.Reader
+	sep []byte
+	rep []byte
+
+	in     []byte // pending input
+	out    []byte // translated output
+	eof    bool
+	quoted bool // inside of the quoted field
+}
+
+func newDelimiterReader(r io.Reader, sep []byte, rep []byte) *delimiterReader {
+	return &delimiterReader{r: r, sep: sep, rep: rep}
+}
+
+func (d *delimiterReader) translate() {
+	in := d.in
+
+	end := len(in)
+	if !d.eof {
+		end -= len(d.sep) - 1
+	}
+
+	i := 0
+
+	for ; i < end; i++ {
+		switch {
+		case in[i] == '"':
+			// escaped quote, "", toggles the state twice
+			d.quoted = !d.quoted
+		case !d.quoted && bytes.HasPrefix(in[i:], d.sep):
+			d.out = append(d.out, d.rep...)
+			i += len(d.sep) - 1
+
+			continue
+		}
+
+		d.out = append(d.out, in[i])
+	}
+
+	d.in = append(d.in[:0], in[i:]...)
+}
+
+func (d *delimiterReader) Read(p []byte) (int, error) {
+	for len(d.out) == 0 {
+		if d.eof {
+			return 0, io.EOF
+		}
+
+		buf := make([]byte, delimiterReadSize)
+
+		n, err := d.r.Read(buf)
+		if errors.Is(err, io.EOF) {
+			d.eof = true
+		} else if err != nil {
+			return 0, err
+		}
+
+		d.in = append(d.in, buf[:n]...)
+
+		d.translate()
+	}
+
+	n := copy(p, d.out)
+	d.out = d.out[n:]
+
+	return n, nil
+}
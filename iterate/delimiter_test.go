@@ -0,0 +1,99 @@
+// Copyright 2022-2023 Tigris Data, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package iterate
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"strings"
+	"testing"
+	"testing/iotest"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDelimiterReader(t *testing.T) {
+	cases := []struct {
+		name string
+		in   string
+		sep  string
+		exp  string
+	}{
+		{"empty", "", "||", ""},
+		{"no separator", "a,b\n", "||", "a,b\n"},
+		{"separator", "a||b||c\n", "||", "a\x1fb\x1fc\n"},
+		{"partial separator", "a|b||c|", "||", "a|b\x1fc|"},
+		{"adjacent separators", "a||||b", "||", "a\x1f\x1fb"},
+		{"long separator", "a<->b<-c<->", "<->", "a\x1fb<-c\x1f"},
+		{"quoted separator", "\"a||b\"||c\n", "||", "\"a||b\"\x1fc\n"},
+		{"escaped quote", "\"a\"\"||b\"||c\n", "||", "\"a\"\"||b\"\x1fc\n"},
+		{"quoted multiline", "a||\"b\n||c\"||d\n", "||", "a\x1f\"b\n||c\"\x1fd\n"},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			// separator split between the reads
+			r := newDelimiterReader(iotest.OneByteReader(strings.NewReader(c.in)), []byte(c.sep), []byte("\x1f"))
+
+			b, err := io.ReadAll(r)
+			require.NoError(t, err)
+			assert.Equal(t, c.exp, string(b))
+
+			r = newDelimiterReader(strings.NewReader(c.in), []byte(c.sep), []byte("\x1f"))
+
+			b, err = io.ReadAll(r)
+			require.NoError(t, err)
+			assert.Equal(t, c.exp, string(b))
+		})
+	}
+}
+
+func TestCSVMultiCharDelimiter(t *testing.T) {
+	Format = FormatCSV
+
+	defer func() {
+		Format = ""
+		defaultRun.CSV.Delimiter = 0
+		defaultRun.CSV.delimiterSeq = nil
+	}()
+
+	for _, d := range []string{"||", `\t`, "¦"} {
+		t.Run(d, func(t *testing.T) {
+			require.NoError(t, CSVConfigure(d, "", true, false))
+
+			sep := unescapeDelimiter(d)
+			in := "id" + sep + "name\n1" + sep + "alice\n2" + sep + "\"bob" + sep + "jr\"\n"
+
+			var docs []json.RawMessage
+
+			err := readerInput(context.Background(), nil, strings.NewReader(in),
+				func(ctx context.Context, args []string, d []json.RawMessage) error {
+					docs = append(docs, d...)
+					return nil
+				})
+			require.NoError(t, err)
+
+			require.Len(t, docs, 2)
+			assert.JSONEq(t, `{"id":1,"name":"alice"}`, string(docs[0]))
+
+			// delimiter inside the quoted field is kept
+			exp, err := json.Marshal(map[string]any{"id": 2, "name": "bob" + sep + "jr"})
+			require.NoError(t, err)
+			assert.JSONEq(t, string(exp), string(docs[1]))
+		})
+	}
+}
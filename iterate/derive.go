@@ -0,0 +1,223 @@
+// Copyright 2022-2023 Tigris Data, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package iterate
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/tigrisdata/tigris-cli/util"
+)
+
+// AddFields is the list of derived fields definitions in the form of name=expr.
+// Supported expressions:
+//   - now() - current time in RFC3339 format
+//   - uuid() - random UUID
+//   - concat(arg, ...) - concatenation of the values of the fields and quoted string literals
+var AddFields []string
+
+var ErrInvalidFieldExpr = fmt.Errorf("invalid derived field expression")
+
+type derivedField struct {
+	name string
+	eval func(doc map[string]any) any
+}
+
+var derivedFields []derivedField
+
+func invalidFieldExpr(def string) error {
+	return fmt.Errorf("%w: %s. expected name=now(), name=uuid() or name=concat(field, 'literal', ...)",
+		ErrInvalidFieldExpr, def)
+}
+
+// fieldValue returns the value of the field, nested fields are referenced using dot notation.
+func fieldValue(doc map[string]any, name string) any {
+	var v any = doc
+
+	for _, p := range strings.Split(name, ".") {
+		m, ok := v.(map[string]any)
+		if !ok {
+			return nil
+		}
+
+		v = m[p]
+	}
+
+	return v
+}
+
+func valueString(v any) string {
+	switch t := v.(type) {
+	case nil:
+		return ""
+	case string:
+		return t
+	case json.Number:
+		return t.String()
+	case bool:
+		return fmt.Sprintf("%v", t)
+	}
+
+	b, _ := json.Marshal(v)
+
+	return string(b)
+}
+
+// splitArgs splits function arguments by comma, ignoring commas inside quoted literals.
+func splitArgs(s string) []string {
+	var (
+		res   []string
+		quote rune
+		start int
+	)
+
+	for i, c := range s {
+		switch {
+		case quote != 0:
+			if c == quote {
+				quote = 0
+			}
+		case c == '\'' || c == '"':
+			quote = c
+		case c == ',':
+			res = append(res, s[start:i])
+			start = i + 1
+		}
+	}
+
+	return append(res, s[start:])
+}
+
+func parseConcatArgs(def string, s string) ([]func(doc map[string]any) string, error) {
+	var args []func(doc map[string]any) string
+
+	for _, a := range splitArgs(s) {
+		a = strings.TrimSpace(a)
+
+		switch {
+		case a == "":
+			return nil, invalidFieldExpr(def)
+		case len(a) > 1 && (a[0] == '\'' || a[0] == '"'):
+			if a[len(a)-1] != a[0] {
+				return nil, invalidFieldExpr(def)
+			}
+
+			lit := a[1 : len(a)-1]
+			args = append(args, func(map[string]any) string { return lit })
+		default:
+			field := a
+			args = append(args, func(doc map[string]any) string { return valueString(fieldValue(doc, field)) })
+		}
+	}
+
+	return args, nil
+}
+
+func parseDerivedField(def string) (*derivedField, error) {
+	name, expr, ok := strings.Cut(def, "=")
+	name, expr = strings.TrimSpace(name), strings.TrimSpace(expr)
+
+	if !ok || name == "" || !strings.HasSuffix(expr, ")") {
+		return nil, invalidFieldExpr(def)
+	}
+
+	fn, args, ok := strings.Cut(strings.TrimSuffix(expr, ")"), "(")
+	if !ok {
+		return nil, invalidFieldExpr(def)
+	}
+
+	switch strings.TrimSpace(fn) {
+	case "now":
+		return &derivedField{name: name, eval: func(map[string]any) any {
+			return time.Now().UTC().Format(time.RFC3339Nano)
+		}}, nil
+	case "uuid":
+		return &derivedField{name: name, eval: func(map[string]any) any {
+			return uuid.New().String()
+		}}, nil
+	case "concat":
+		cargs, err := parseConcatArgs(def, args)
+		if err != nil {
+			return nil, err
+		}
+
+		return &derivedField{name: name, eval: func(doc map[string]any) any {
+			var sb strings.Builder
+
+			for _, a := range cargs {
+				sb.WriteString(a(doc))
+			}
+
+			return sb.String()
+		}}, nil
+	}
+
+	return nil, invalidFieldExpr(def)
+}
+
+func parseAddFields() error {
+	derivedFields = nil
+
+	for _, def := range AddFields {
+		f, err := parseDerivedField(def)
+		if err != nil {
+			return err
+		}
+
+		derivedFields = append(derivedFields, *f)
+	}
+
+	return nil
+}
+
+// addDerivedFields computes derived fields and sets them in the documents.
+// Derived fields overwrite existing fields with the same name.
+func addDerivedFields(docs []json.RawMessage) error {
+	if len(derivedFields) == 0 {
+		return nil
+	}
+
+	for k, doc := range docs {
+		var m map[string]any
+
+		dec := json.NewDecoder(bytes.NewReader(doc))
+		dec.UseNumber()
+
+		if err := dec.Decode(&m); err != nil {
+			return err
+		}
+
+		for _, f := range derivedFields {
+			m[f.name] = f.eval(m)
+		}
+
+		b, err := json.Marshal(m)
+		if err != nil {
+			return err
+		}
+
+		if b, err = util.KeepOrder(doc, b); err != nil {
+			return err
+		}
+
+		docs[k] = b
+	}
+
+	return nil
+}
@@ -0,0 +1,67 @@
+// Copyright 2022-2023 Tigris Data, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package iterate
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAddDerivedFields(t *testing.T) {
+	AddFields = []string{
+		"imported_at=now()",
+		"import_id = uuid()",
+		`full_name=concat(first, ' ', last, ", ", address.city, '#', id)`,
+	}
+
+	defer func() {
+		AddFields = nil
+		derivedFields = nil
+	}()
+
+	require.NoError(t, parseAddFields())
+
+	docs := []json.RawMessage{
+		json.RawMessage(`{"id":12345678901234567,"first":"John","last":"Doe","address":{"city":"Paris"}}`),
+		json.RawMessage(`{"id":2,"first":"Jane"}`),
+	}
+
+	require.NoError(t, addDerivedFields(docs))
+
+	var m map[string]any
+
+	require.NoError(t, json.Unmarshal(docs[0], &m))
+
+	assert.Equal(t, "John Doe, Paris#12345678901234567", m["full_name"])
+
+	_, err := time.Parse(time.RFC3339Nano, m["imported_at"].(string))
+	require.NoError(t, err)
+
+	_, err = uuid.Parse(m["import_id"].(string))
+	require.NoError(t, err)
+
+	require.NoError(t, json.Unmarshal(docs[1], &m))
+	assert.Equal(t, "Jane , #2", m["full_name"])
+
+	for _, def := range []string{"name", "=now()", "name=now", "name=random()", "name=concat(a,,b)", "name=concat('a)"} {
+		AddFields = []string{def}
+		require.ErrorIs(t, parseAddFields(), ErrInvalidFieldExpr, def)
+	}
+}
@@ -0,0 +1,91 @@
+// Copyright 2022-2023 Tigris Data, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package iterate
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/tigrisdata/tigris-cli/util"
+)
+
+// maxReportedOversized limits the number of the indices of the oversized documents reported after the import.
+const maxReportedOversized = 100
+
+var (
+	// MaxDocSize is the maximum size in bytes of the input document.
+	// Larger documents are skipped with SkipErrors, otherwise the iteration is aborted.
+	// Zero means no limit.
+	MaxDocSize int64
+
+	ErrDocTooLarge = fmt.Errorf("document exceeds --max-doc-size")
+)
+
+// checkDocSizes removes the documents exceeding MaxDocSize.
+// The first document of the batch is the document number first of the input.
+// Oversized documents are saved to the error file when SkipErrors is set,
+// otherwise error is returned.
+func (r *Run) checkDocSizes(docs []json.RawMessage, first int64) ([]json.RawMessage, error) {
+	if MaxDocSize <= 0 {
+		return docs, nil
+	}
+
+	res := docs[:0]
+
+	for k, doc := range docs {
+		if int64(len(doc)) <= MaxDocSize {
+			res = append(res, doc)
+			continue
+		}
+
+		n := first + int64(k)
+
+		err := fmt.Errorf("%w: document %d, size %d bytes, limit %d", ErrDocTooLarge, n, len(doc), MaxDocSize)
+		if !SkipErrors {
+			return nil, err
+		}
+
+		r.oversized = append(r.oversized, n)
+
+		if err = r.writeErrorDoc(doc, err); err != nil {
+			return nil, err
+		}
+	}
+
+	return res, nil
+}
+
+func (r *Run) reportOversized() {
+	if len(r.oversized) == 0 {
+		return
+	}
+
+	nums := make([]string, 0, len(r.oversized))
+
+	for k, n := range r.oversized {
+		if k == maxReportedOversized {
+			nums = append(nums, "...")
+			break
+		}
+
+		nums = append(nums, fmt.Sprintf("%d", n))
+	}
+
+	util.Stderrf("%d document(s) exceeding --max-doc-size=%d skipped: %s\n", len(r.oversized), MaxDocSize,
+		strings.Join(nums, ", "))
+
+	r.oversized = nil
+}
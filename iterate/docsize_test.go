@@ -0,0 +1,66 @@
+// Copyright 2022-2023 Tigris Data, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package iterate
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCheckDocSizes(t *testing.T) {
+	MaxDocSize = 10
+
+	defer func() {
+		MaxDocSize = 0
+		SkipErrors = false
+		ErrorFile = ""
+		defaultRun.Skipped = 0
+		defaultRun.oversized = nil
+	}()
+
+	docs := func() []json.RawMessage {
+		return []json.RawMessage{
+			json.RawMessage(`{"a":1}`),
+			json.RawMessage(`{"a":"long value"}`),
+			json.RawMessage(`{"b":2}`),
+			json.RawMessage(`{"b":"long value"}`),
+		}
+	}
+
+	_, err := defaultRun.checkDocSizes(docs(), 5)
+	require.ErrorIs(t, err, ErrDocTooLarge)
+	assert.Equal(t, "document exceeds --max-doc-size: document 6, size 18 bytes, limit 10", err.Error())
+
+	SkipErrors = true
+	ErrorFile = filepath.Join(t.TempDir(), "errors.json")
+
+	res, err := defaultRun.checkDocSizes(docs(), 5)
+	require.NoError(t, err)
+
+	closeErrorFile()
+
+	assert.Equal(t, []json.RawMessage{json.RawMessage(`{"a":1}`), json.RawMessage(`{"b":2}`)}, res)
+	assert.Equal(t, []int64{6, 8}, defaultRun.oversized)
+	assert.Equal(t, int64(2), defaultRun.Skipped)
+
+	b, err := os.ReadFile(ErrorFile)
+	require.NoError(t, err)
+	assert.Contains(t, string(b), `"error":"document exceeds --max-doc-size: document 8, size 18 bytes, limit 10"`)
+}
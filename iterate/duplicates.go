@@ -0,0 +1,124 @@
+// Copyright 2022-2023 Tigris Data, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package iterate
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/tigrisdata/tigris-cli/util"
+)
+
+var (
+	// WarnDuplicateKeys enables detection of the duplicate keys in the objects of the documents.
+	// Only the last value of the duplicate key is retained, so the duplicates are reported,
+	// as warnings, or as errors in the strict mode.
+	WarnDuplicateKeys bool
+
+	ErrDuplicateKey = fmt.Errorf("duplicate key")
+)
+
+// findDuplicateKeys returns the paths of the duplicate keys of the objects in the document.
+// Nested fields are reported using dot notation, elements of the arrays as name[].
+func findDuplicateKeys(doc json.RawMessage) ([]string, error) {
+	var dups []string
+
+	dec := json.NewDecoder(bytes.NewReader(doc))
+
+	if err := walkDuplicateKeys(dec, "", &dups); err != nil {
+		return nil, err
+	}
+
+	return dups, nil
+}
+
+func walkDuplicateKeys(dec *json.Decoder, path string, dups *[]string) error {
+	t, err := dec.Token()
+	if err != nil {
+		return err
+	}
+
+	d, ok := t.(json.Delim)
+	if !ok {
+		return nil
+	}
+
+	if d == '{' {
+		keys := map[string]bool{}
+
+		for dec.More() {
+			t, err := dec.Token()
+			if err != nil {
+				return err
+			}
+
+			k, _ := t.(string)
+
+			p := k
+			if path != "" {
+				p = path + "." + k
+			}
+
+			if keys[k] {
+				*dups = append(*dups, p)
+			}
+
+			keys[k] = true
+
+			if err = walkDuplicateKeys(dec, p, dups); err != nil {
+				return err
+			}
+		}
+	} else {
+		for dec.More() {
+			if err = walkDuplicateKeys(dec, path+"[]", dups); err != nil {
+				return err
+			}
+		}
+	}
+
+	_, err = dec.Token() // closing delimiter
+
+	return err
+}
+
+// checkDuplicateKeys reports the documents with the duplicate keys.
+// The first document of the batch is the document number first of the input.
+func checkDuplicateKeys(docs []json.RawMessage, first int64) error {
+	if !WarnDuplicateKeys {
+		return nil
+	}
+
+	for k, doc := range docs {
+		dups, err := findDuplicateKeys(doc)
+		if err != nil {
+			return err
+		}
+
+		if len(dups) == 0 {
+			continue
+		}
+
+		err = fmt.Errorf("%w: %s in document %d, only the last value is retained",
+			ErrDuplicateKey, strings.Join(dups, ", "), first+int64(k))
+		if err = util.Warning(err); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,71 @@
+// Copyright 2022-2023 Tigris Data, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package iterate
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tigrisdata/tigris-cli/util"
+)
+
+func TestFindDuplicateKeys(t *testing.T) {
+	cases := []struct {
+		name string
+		doc  string
+		exp  []string
+	}{
+		{"no duplicates", `{"a":1,"b":{"a":2},"c":[{"a":3},{"a":4}]}`, nil},
+		{"top level", `{"a":1,"b":2,"a":3}`, []string{"a"}},
+		{"nested", `{"a":{"b":1,"b":{"c":1,"c":2}}}`, []string{"a.b", "a.b.c"}},
+		{"array", `{"a":[{"b":1,"b":2}]}`, []string{"a[].b"}},
+		{"repeated", `{"a":1,"a":2,"a":3}`, []string{"a", "a"}},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			dups, err := findDuplicateKeys(json.RawMessage(c.doc))
+			require.NoError(t, err)
+			assert.Equal(t, c.exp, dups)
+		})
+	}
+}
+
+func TestCheckDuplicateKeys(t *testing.T) {
+	docs := []json.RawMessage{
+		json.RawMessage(`{"a":1}`),
+		json.RawMessage(`{"a":1,"a":2}`),
+	}
+
+	// disabled
+	require.NoError(t, checkDuplicateKeys(docs, 1))
+
+	WarnDuplicateKeys = true
+
+	defer func() {
+		WarnDuplicateKeys = false
+		util.Strict = false
+	}()
+
+	require.NoError(t, checkDuplicateKeys(docs, 1))
+
+	util.Strict = true
+
+	err := checkDuplicateKeys(docs, 10)
+	require.ErrorIs(t, err, ErrDuplicateKey)
+	assert.Contains(t, err.Error(), "a in document 11")
+}
@@ -0,0 +1,96 @@
+// Copyright 2022-2023 Tigris Data, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package iterate
+
+import (
+	"bytes"
+	"encoding/json"
+
+	"github.com/tigrisdata/tigris-cli/util"
+)
+
+// EmptyStringAsNull enables conversion of empty string values to null,
+// so as they don't affect the inferred type of the field.
+var EmptyStringAsNull bool
+
+func emptyStringToNull(v any) (any, bool) {
+	switch val := v.(type) {
+	case string:
+		if val == "" {
+			return nil, true
+		}
+	case map[string]any:
+		changed := false
+
+		for k, e := range val {
+			var ok bool
+			if val[k], ok = emptyStringToNull(e); ok {
+				changed = true
+			}
+		}
+
+		return val, changed
+	case []any:
+		changed := false
+
+		for k, e := range val {
+			var ok bool
+			if val[k], ok = emptyStringToNull(e); ok {
+				changed = true
+			}
+		}
+
+		return val, changed
+	}
+
+	return v, false
+}
+
+// emptyStringsToNulls replaces empty string values in the documents with nulls.
+// Documents without empty strings are left intact.
+func emptyStringsToNulls(docs []json.RawMessage) error {
+	if !EmptyStringAsNull {
+		return nil
+	}
+
+	for k, doc := range docs {
+		var v any
+
+		dec := json.NewDecoder(bytes.NewReader(doc))
+		dec.UseNumber()
+
+		if err := dec.Decode(&v); err != nil {
+			return err
+		}
+
+		v, changed := emptyStringToNull(v)
+		if !changed {
+			continue
+		}
+
+		b, err := json.Marshal(v)
+		if err != nil {
+			return err
+		}
+
+		if b, err = util.KeepOrder(doc, b); err != nil {
+			return err
+		}
+
+		docs[k] = b
+	}
+
+	return nil
+}
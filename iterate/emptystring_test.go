@@ -0,0 +1,44 @@
+// Copyright 2022-2023 Tigris Data, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package iterate
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEmptyStringAsNull(t *testing.T) {
+	docs := []json.RawMessage{
+		json.RawMessage(`{"id":1, "age":""}`),
+		json.RawMessage(`{"id":2, "age":10, "name":" "}`),
+		json.RawMessage(`{"id":3, "addr":{"city":""}, "tags":["a",""]}`),
+	}
+
+	require.NoError(t, emptyStringsToNulls(docs))
+	assert.Equal(t, `{"id":1, "age":""}`, string(docs[0]))
+
+	EmptyStringAsNull = true
+
+	defer func() { EmptyStringAsNull = false }()
+
+	require.NoError(t, emptyStringsToNulls(docs))
+
+	assert.JSONEq(t, `{"id":1, "age":null}`, string(docs[0]))
+	assert.Equal(t, `{"id":2, "age":10, "name":" "}`, string(docs[1]))
+	assert.JSONEq(t, `{"id":3, "addr":{"city":null}, "tags":["a",null]}`, string(docs[2]))
+}
@@ -0,0 +1,52 @@
+// Copyright 2022-2023 Tigris Data, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package iterate
+
+import (
+	"fmt"
+	"io"
+
+	"golang.org/x/text/encoding/htmlindex"
+	"golang.org/x/text/transform"
+)
+
+const encodingUTF8 = "utf-8"
+
+// InputEncoding is the character encoding of the input.
+// Input is transcoded to UTF-8 before parsing.
+// Empty value means UTF-8 input, which is passed through as is.
+var InputEncoding string
+
+var ErrUnsupportedEncoding = fmt.Errorf("unsupported input encoding")
+
+// decodeInput wraps the reader to transcode the input from InputEncoding to UTF-8.
+// Encoding names and aliases are resolved as per WHATWG Encoding Standard,
+// for example: latin1, iso-8859-1, windows-1252, utf-16le, shift_jis, gbk.
+func decodeInput(r io.Reader) (io.Reader, error) {
+	if InputEncoding == "" {
+		return r, nil
+	}
+
+	enc, err := htmlindex.Get(InputEncoding)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrUnsupportedEncoding, InputEncoding)
+	}
+
+	if name, _ := htmlindex.Name(enc); name == encodingUTF8 {
+		return r, nil
+	}
+
+	return transform.NewReader(r, enc.NewDecoder()), nil
+}
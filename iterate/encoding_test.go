@@ -0,0 +1,60 @@
+// Copyright 2022-2023 Tigris Data, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package iterate
+
+import (
+	"bytes"
+	"io"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDecodeInput(t *testing.T) {
+	defer func() { InputEncoding = "" }()
+
+	cases := []struct {
+		encoding string
+		in       []byte
+		exp      string
+		err      error
+	}{
+		{"", []byte("name\ncaf\xc3\xa9\n"), "name\ncafé\n", nil},
+		{"utf-8", []byte("name\ncaf\xc3\xa9\n"), "name\ncafé\n", nil},
+		{"latin1", []byte("name\ncaf\xe9\n"), "name\ncafé\n", nil},
+		{"windows-1252", []byte("\x93quoted\x94 \x80"), "“quoted” €", nil},
+		{"iso-8859-1", []byte("na\xefve"), "naïve", nil},
+		{"klingon", nil, "", ErrUnsupportedEncoding},
+	}
+
+	for _, c := range cases {
+		t.Run(c.encoding, func(t *testing.T) {
+			InputEncoding = c.encoding
+
+			r, err := decodeInput(bytes.NewReader(c.in))
+			require.ErrorIs(t, err, c.err)
+
+			if c.err != nil {
+				return
+			}
+
+			b, err := io.ReadAll(r)
+			require.NoError(t, err)
+
+			assert.Equal(t, c.exp, string(b))
+		})
+	}
+}
@@ -0,0 +1,80 @@
+// Copyright 2022-2023 Tigris Data, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package iterate
+
+import (
+	"encoding/json"
+
+	"github.com/tigrisdata/tigris-cli/util"
+)
+
+var (
+	// EncryptFields is the list of the fields to be encrypted before insert.
+	EncryptFields []string
+	// EncryptMode is util.EncryptRandom or util.EncryptDeterministic.
+	EncryptMode = util.EncryptRandom
+
+	// EncryptionKey and EncryptionKeyFile provide the key,
+	// see util.LoadEncryptionKey for the precedence.
+	EncryptionKey     string
+	EncryptionKeyFile string
+
+	fieldCipher *util.FieldCipher
+)
+
+// NewFieldCipher creates the cipher of the fields with the configured key.
+func NewFieldCipher(mode string, fields []string) (*util.FieldCipher, error) {
+	key, err := util.LoadEncryptionKey(EncryptionKey, EncryptionKeyFile)
+	if err != nil {
+		return nil, err
+	}
+
+	return util.NewFieldCipher(key, mode, fields)
+}
+
+func prepareEncryption() error {
+	fieldCipher = nil
+
+	if len(EncryptFields) == 0 {
+		return nil
+	}
+
+	c, err := NewFieldCipher(EncryptMode, EncryptFields)
+	if err != nil {
+		return err
+	}
+
+	fieldCipher = c
+
+	return nil
+}
+
+// encryptFields encrypts the values of EncryptFields in the documents.
+func encryptFields(docs []json.RawMessage) error {
+	if fieldCipher == nil {
+		return nil
+	}
+
+	for k, doc := range docs {
+		b, err := fieldCipher.Encrypt(doc)
+		if err != nil {
+			return err
+		}
+
+		docs[k] = b
+	}
+
+	return nil
+}
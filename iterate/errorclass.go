@@ -0,0 +1,80 @@
+// Copyright 2022-2023 Tigris Data, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package iterate
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/iancoleman/strcase"
+	"github.com/tigrisdata/tigris-client-go/driver"
+)
+
+// errorClass returns the class of the error: the code of the server error, like InvalidArgument,
+// or the innermost wrapped error, like "document validation failed", for the errors of the CLI.
+func errorClass(err error) string {
+	var ep *driver.Error
+	if errors.As(err, &ep) && ep.TigrisError != nil {
+		return strcase.ToCamel(strings.ToLower(ep.Code.String()))
+	}
+
+	for {
+		u := errors.Unwrap(err)
+		if u == nil {
+			return err.Error()
+		}
+
+		err = u
+	}
+}
+
+func (r *Run) recordErrorClass(err error) {
+	if r.errorClasses == nil {
+		r.errorClasses = make(map[string]int64)
+	}
+
+	r.errorClasses[errorClass(err)]++
+}
+
+// ErrorClasses returns the number of the documents failed to be processed by the last iteration,
+// by the class of the error.
+func ErrorClasses() map[string]int64 {
+	return defaultRun.errorClasses
+}
+
+// errorHistogram formats the error classes, most frequent first, like: 120 InvalidArgument, 5 AlreadyExists.
+func errorHistogram(classes map[string]int64) string {
+	names := make([]string, 0, len(classes))
+	for c := range classes {
+		names = append(names, c)
+	}
+
+	sort.Slice(names, func(i, j int) bool {
+		if classes[names[i]] != classes[names[j]] {
+			return classes[names[i]] > classes[names[j]]
+		}
+
+		return names[i] < names[j]
+	})
+
+	res := make([]string, 0, len(names))
+	for _, c := range names {
+		res = append(res, fmt.Sprintf("%d %s", classes[c], c))
+	}
+
+	return strings.Join(res, ", ")
+}
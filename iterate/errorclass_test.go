@@ -0,0 +1,46 @@
+// Copyright 2022-2023 Tigris Data, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package iterate
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	api "github.com/tigrisdata/tigris-client-go/api/server/v1"
+	"github.com/tigrisdata/tigris-client-go/driver"
+)
+
+func TestErrorClasses(t *testing.T) {
+	defaultRun.errorClasses = nil
+
+	defer func() { defaultRun.errorClasses = nil }()
+
+	for i := 0; i < 3; i++ {
+		defaultRun.recordErrorClass(driver.NewError(api.Code_INVALID_ARGUMENT, "bad field %d", i))
+	}
+
+	defaultRun.recordErrorClass(fmt.Errorf("wrapped: %w", driver.NewError(api.Code_ALREADY_EXISTS, "duplicate")))
+	defaultRun.recordErrorClass(fmt.Errorf("doc 1: %w", ErrValidation))
+	defaultRun.recordErrorClass(fmt.Errorf("doc 2: %w", ErrValidation))
+
+	assert.Equal(t, map[string]int64{
+		"InvalidArgument":     3,
+		"AlreadyExists":       1,
+		ErrValidation.Error(): 2,
+	}, ErrorClasses())
+
+	assert.Equal(t, "3 InvalidArgument, 2 "+ErrValidation.Error()+", 1 AlreadyExists", errorHistogram(ErrorClasses()))
+}
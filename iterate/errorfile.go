@@ -0,0 +1,192 @@
+// Copyright 2022-2023 Tigris Data, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package iterate
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"sync"
+
+	"github.com/rs/zerolog/log"
+	"github.com/tigrisdata/tigris-cli/util"
+)
+
+var (
+	// SkipErrors makes the iteration to continue after the document failed to be processed.
+	SkipErrors bool
+	// ErrorFile is the path to the file where documents failed to be processed are saved.
+	ErrorFile string
+
+	// MaxErrors aborts the iteration when the number of failed documents reaches it.
+	MaxErrors int64
+	// MaxErrorRate aborts the iteration when the percentage of failed documents exceeds it.
+	// Rate is checked after at least minErrorRateSample documents have been processed.
+	MaxErrorRate float64
+
+	ErrMaxErrorsExceeded = fmt.Errorf("too many documents failed")
+
+	// errorFile is shared by the concurrent iterations, guarded by errorFileMu.
+	errorFile   *os.File
+	errorFileMu sync.Mutex
+)
+
+const (
+	minErrorRateSample = 100
+
+	// ExitCodeMaxErrors is the exit code when the import is aborted by --max-errors or --max-error-rate.
+	ExitCodeMaxErrors = 3
+)
+
+// checkMaxErrors returns error if the number of failed documents exceeded configured thresholds.
+func (r *Run) checkMaxErrors() error {
+	if MaxErrors > 0 && r.Skipped >= MaxErrors {
+		return fmt.Errorf("%w: %d document(s) failed, --max-errors=%d", ErrMaxErrorsExceeded, r.Skipped, MaxErrors)
+	}
+
+	if MaxErrorRate > 0 && r.seen >= minErrorRateSample {
+		if rate := float64(r.Skipped) * 100 / float64(r.seen); rate > MaxErrorRate {
+			return fmt.Errorf("%w: %.2f%% of %d document(s) failed, --max-error-rate=%.2f",
+				ErrMaxErrorsExceeded, rate, r.seen, MaxErrorRate)
+		}
+	}
+
+	return nil
+}
+
+// ErrorDoc is a line of the error file.
+// Error file is a newline delimited stream of JSON objects,
+// each containing failed document and the reason of the failure.
+type ErrorDoc struct {
+	Error    string          `json:"error"`
+	Document json.RawMessage `json:"document"`
+}
+
+// SkipDoc saves the document, failed to be processed by the caller, to the error file.
+// Returns error when the number of the failed documents exceeded configured thresholds.
+func SkipDoc(doc json.RawMessage, docErr error) error {
+	return defaultRun.writeErrorDoc(doc, docErr)
+}
+
+// SkipDoc saves the document, failed to be processed by the caller, to the error file,
+// counting it in the run.
+func (r *Run) SkipDoc(doc json.RawMessage, docErr error) error {
+	return r.writeErrorDoc(doc, docErr)
+}
+
+func (r *Run) writeErrorDoc(doc json.RawMessage, docErr error) error {
+	r.Skipped++
+
+	r.recordErrorClass(docErr)
+
+	log.Debug().Err(docErr).RawJSON("doc", doc).Msg("skipping failed document")
+
+	if err := writeErrorFile(doc, docErr); err != nil {
+		return err
+	}
+
+	return r.checkMaxErrors()
+}
+
+func writeErrorFile(doc json.RawMessage, docErr error) error {
+	if ErrorFile == "" {
+		return nil
+	}
+
+	errorFileMu.Lock()
+	defer errorFileMu.Unlock()
+
+	if errorFile == nil {
+		f, err := os.OpenFile(ErrorFile, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0o600)
+		if err != nil {
+			return util.Error(err, "open error file: %s", ErrorFile)
+		}
+
+		errorFile = f
+	}
+
+	b, err := json.Marshal(&ErrorDoc{Error: docErr.Error(), Document: doc})
+	if err != nil {
+		return util.Error(err, "marshal failed document")
+	}
+
+	_, err = errorFile.Write(append(b, '\n'))
+
+	return util.Error(err, "write error file: %s", ErrorFile)
+}
+
+func closeErrorFile() {
+	errorFileMu.Lock()
+	defer errorFileMu.Unlock()
+
+	if errorFile != nil {
+		err := errorFile.Close()
+		util.Fatal(err, "close error file: %s", ErrorFile)
+
+		errorFile = nil
+	}
+}
+
+// reportErrors prints the number of the documents failed to be processed by the run.
+func (r *Run) reportErrors() {
+	if r.Skipped == 0 {
+		return
+	}
+
+	if ErrorFile != "" {
+		util.Stderrf("%d document(s) failed to import. Failed documents saved to: %s\n", r.Skipped, ErrorFile)
+	} else {
+		util.Stderrf("%d document(s) failed to import\n", r.Skipped)
+	}
+
+	util.Stderrf("Failed documents by error: %s\n", errorHistogram(r.errorClasses))
+}
+
+// ErrorFileInput reads the error file produced by the previous run,
+// strips error annotations and passes original documents to the fn.
+func ErrorFileInput(ctx context.Context, args []string, r io.Reader,
+	fn func(ctx2 context.Context, args []string, docs []json.RawMessage) error,
+) error {
+	run := RunFrom(ctx)
+	run.errorClasses = nil
+
+	defer closeTee()
+	defer run.reportErrors()
+	defer closeErrorFile()
+	defer handleInterrupt()()
+
+	ir := newInterruptibleReader(r)
+
+	err := iterateDecoder(ctx, args, json.NewDecoder(ir), func(dec *json.Decoder) (json.RawMessage, error) {
+		var v ErrorDoc
+
+		err := dec.Decode(&v)
+		if errors.Is(err, ErrInterrupted) {
+			return nil, err
+		}
+
+		util.Fatal(err, "reading documents from error file")
+
+		return v.Document, nil
+	}, fn)
+	if err == nil && ir.interrupted() {
+		return ErrInterrupted
+	}
+
+	return err
+}
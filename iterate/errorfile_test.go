@@ -0,0 +1,155 @@
+// Copyright 2022-2023 Tigris Data, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package iterate
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var errTestBadDoc = fmt.Errorf("bad document")
+
+func TestSkipErrors(t *testing.T) {
+	SkipErrors = true
+	ErrorFile = filepath.Join(t.TempDir(), "errors.json")
+	defaultRun.Skipped = 0
+
+	defer func() {
+		SkipErrors = false
+		ErrorFile = ""
+		defaultRun.Skipped = 0
+	}()
+
+	docs := []json.RawMessage{
+		json.RawMessage(`{"id":1}`),
+		json.RawMessage(`{"id":2, "bad":true}`),
+		json.RawMessage(`{"id":3}`),
+		json.RawMessage(`{"id":4, "bad":true}`),
+		json.RawMessage(`{"id":5}`),
+	}
+
+	var inserted []json.RawMessage
+
+	process := func(ctx context.Context, args []string, docs []json.RawMessage) error {
+		for _, d := range docs {
+			var m map[string]any
+			require.NoError(t, json.Unmarshal(d, &m))
+
+			if m["bad"] != nil {
+				return errTestBadDoc
+			}
+		}
+
+		inserted = append(inserted, docs...)
+
+		return nil
+	}
+
+	err := varyBatch(context.Background(), nil, docs, process)
+	require.NoError(t, err)
+
+	closeErrorFile()
+
+	assert.Equal(t, []json.RawMessage{docs[0], docs[2], docs[4]}, inserted)
+	assert.Equal(t, int64(2), defaultRun.Skipped)
+
+	f, err := os.Open(ErrorFile)
+	require.NoError(t, err)
+
+	defer func() { _ = f.Close() }()
+
+	var retried []json.RawMessage
+
+	ErrorFile = ""
+
+	err = ErrorFileInput(context.Background(), nil, f, func(ctx context.Context, args []string,
+		docs []json.RawMessage,
+	) error {
+		retried = append(retried, docs...)
+
+		return nil
+	})
+	require.NoError(t, err)
+
+	assert.Equal(t, []json.RawMessage{
+		json.RawMessage(`{"id":2,"bad":true}`),
+		json.RawMessage(`{"id":4,"bad":true}`),
+	}, retried)
+}
+
+func TestMaxErrors(t *testing.T) {
+	SkipErrors = true
+
+	defer func() {
+		SkipErrors = false
+		MaxErrors = 0
+		MaxErrorRate = 0
+		defaultRun.Skipped = 0
+		defaultRun.seen = 0
+	}()
+
+	docs := func(n int, bad int) []json.RawMessage {
+		res := make([]json.RawMessage, 0, n)
+		for i := 0; i < n; i++ {
+			res = append(res, json.RawMessage(fmt.Sprintf(`{"id":%d, "bad":%v}`, i, i < bad)))
+		}
+
+		return res
+	}
+
+	process := func(ctx context.Context, args []string, docs []json.RawMessage) error {
+		for _, d := range docs {
+			var m map[string]any
+			require.NoError(t, json.Unmarshal(d, &m))
+
+			if m["bad"] == true {
+				return errTestBadDoc
+			}
+		}
+
+		return nil
+	}
+
+	MaxErrors = 3
+
+	err := processBatch(context.Background(), nil, docs(10, 2), process)
+	require.NoError(t, err)
+
+	err = processBatch(context.Background(), nil, docs(10, 2), process)
+	require.ErrorIs(t, err, ErrMaxErrorsExceeded)
+	assert.Equal(t, int64(3), defaultRun.Skipped)
+
+	MaxErrors = 0
+	MaxErrorRate = 10
+	defaultRun.Skipped = 0
+	defaultRun.seen = 0
+
+	// rate is not checked until enough documents seen
+	err = processBatch(context.Background(), nil, docs(50, 25), process)
+	require.NoError(t, err)
+
+	err = processBatch(context.Background(), nil, docs(50, 0), process)
+	require.NoError(t, err)
+
+	err = processBatch(context.Background(), nil, docs(50, 1), process)
+	require.ErrorIs(t, err, ErrMaxErrorsExceeded)
+}
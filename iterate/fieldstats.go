@@ -0,0 +1,108 @@
+// Copyright 2022-2023 Tigris Data, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package iterate
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+)
+
+var (
+	// FieldStats enables collection of per field null statistics,
+	// which are reported after the import.
+	FieldStats bool
+
+	statsDocs int64
+	// statsPresent counts documents which have the field with non-null value.
+	// Nested fields are named using dot notation.
+	statsPresent map[string]int64
+)
+
+func countFields(prefix string, m map[string]any) {
+	for k, v := range m {
+		name := prefix + k
+
+		if _, ok := statsPresent[name]; !ok {
+			statsPresent[name] = 0
+		}
+
+		if v == nil {
+			continue
+		}
+
+		statsPresent[name]++
+
+		if nested, ok := v.(map[string]any); ok {
+			countFields(name+".", nested)
+		}
+	}
+}
+
+func collectFieldStats(docs []json.RawMessage) error {
+	if !FieldStats {
+		return nil
+	}
+
+	if statsPresent == nil {
+		statsPresent = make(map[string]int64)
+	}
+
+	for _, doc := range docs {
+		var m map[string]any
+
+		dec := json.NewDecoder(bytes.NewReader(doc))
+		dec.UseNumber()
+
+		if err := dec.Decode(&m); err != nil {
+			return err
+		}
+
+		statsDocs++
+
+		countFields("", m)
+	}
+
+	return nil
+}
+
+func writeFieldStats(w io.Writer) {
+	fields := make([]string, 0, len(statsPresent))
+	for f := range statsPresent {
+		fields = append(fields, f)
+	}
+
+	sort.Strings(fields)
+
+	_, _ = fmt.Fprintf(w, "Null or absent values in %d document(s):\n", statsDocs)
+
+	for _, f := range fields {
+		missing := statsDocs - statsPresent[f]
+
+		_, _ = fmt.Fprintf(w, "  %6d  %5.1f%%  %s\n", missing, float64(missing)*100/float64(statsDocs), f)
+	}
+}
+
+func reportFieldStats() {
+	if statsDocs > 0 {
+		writeFieldStats(os.Stderr)
+	}
+
+	statsDocs = 0
+	statsPresent = nil
+}
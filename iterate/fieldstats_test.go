@@ -0,0 +1,54 @@
+// Copyright 2022-2023 Tigris Data, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package iterate
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFieldStats(t *testing.T) {
+	FieldStats = true
+
+	defer func() {
+		FieldStats = false
+		reportFieldStats()
+	}()
+
+	require.NoError(t, collectFieldStats([]json.RawMessage{
+		json.RawMessage(`{"id":1, "name":"a", "addr":{"city":"b"}}`),
+		json.RawMessage(`{"id":2, "name":null, "addr":{"city":null}}`),
+	}))
+	require.NoError(t, collectFieldStats([]json.RawMessage{
+		json.RawMessage(`{"id":3, "addr":null}`),
+		json.RawMessage(`{"id":4, "name":"c", "tags":[]}`),
+	}))
+
+	var buf bytes.Buffer
+
+	writeFieldStats(&buf)
+
+	assert.Equal(t, `Null or absent values in 4 document(s):
+       2   50.0%  addr
+       3   75.0%  addr.city
+       0    0.0%  id
+       2   50.0%  name
+       3   75.0%  tags
+`, buf.String())
+}
@@ -0,0 +1,42 @@
+// Copyright 2022-2023 Tigris Data, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package iterate
+
+import (
+	"fmt"
+)
+
+const (
+	FormatJSON    = "json"
+	FormatCSV     = "csv"
+	FormatMsgPack = "msgpack"
+)
+
+var (
+	// Format is the format of the input. Empty value means that JSON or CSV format is detected automatically.
+	Format string
+
+	ErrInvalidFormat = fmt.Errorf("invalid input format. allowed values: %s, %s, %s",
+		FormatJSON, FormatCSV, FormatMsgPack)
+)
+
+func ValidateFormat() error {
+	switch Format {
+	case "", FormatJSON, FormatCSV, FormatMsgPack:
+		return nil
+	}
+
+	return ErrInvalidFormat
+}
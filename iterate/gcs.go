@@ -0,0 +1,155 @@
+// Copyright 2022-2023 Tigris Data, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package iterate
+
+import (
+	"bufio"
+	"compress/gzip"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/tigrisdata/tigris-cli/util"
+	"golang.org/x/oauth2"
+	"golang.org/x/oauth2/google"
+)
+
+const (
+	gcsScheme    = "gs://"
+	gcsReadScope = "https://www.googleapis.com/auth/devstorage.read_only"
+)
+
+var (
+	// GCSProject is the project billed for the requests to the requester pays buckets.
+	GCSProject string
+
+	ErrGCSInvalidURL  = fmt.Errorf("invalid Google Cloud Storage URL, expected gs://bucket/object")
+	ErrGCSCredentials = fmt.Errorf("google application default credentials not found. " +
+		"run 'gcloud auth application-default login' or set GOOGLE_APPLICATION_CREDENTIALS")
+	ErrGCSAccess = fmt.Errorf("access to Google Cloud Storage object denied. " +
+		"check that the credentials have read access to the object, " +
+		"use --gcs-project for requester pays buckets")
+	ErrGCSNotFound = fmt.Errorf("google Cloud Storage object not found")
+
+	gcsEndpoint = "https://storage.googleapis.com"
+
+	// gcsClient returns HTTP client authorized with application default credentials.
+	gcsClient = func(ctx context.Context) (*http.Client, error) {
+		creds, err := google.FindDefaultCredentials(ctx, gcsReadScope)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %s", ErrGCSCredentials, err.Error())
+		}
+
+		return oauth2.NewClient(ctx, creds.TokenSource), nil
+	}
+)
+
+func isGCSURL(s string) bool {
+	return strings.HasPrefix(s, gcsScheme)
+}
+
+func parseGCSURL(s string) (string, string, error) {
+	bucket, object, ok := strings.Cut(strings.TrimPrefix(s, gcsScheme), "/")
+	if !ok || bucket == "" || object == "" {
+		return "", "", fmt.Errorf("%w: %s", ErrGCSInvalidURL, s)
+	}
+
+	return bucket, object, nil
+}
+
+// openGCS streams the object from Google Cloud Storage.
+// The object is downloaded by the JSON API media request, authorized by the application default credentials.
+// The cloud.google.com/go/storage client is not used, because its google.golang.org/api dependency
+// is not a dependency of the CLI yet. Switch to it, once it's added to the module.
+func openGCS(ctx context.Context, source string) (io.ReadCloser, error) {
+	bucket, object, err := parseGCSURL(source)
+	if err != nil {
+		return nil, err
+	}
+
+	client, err := gcsClient(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	u := fmt.Sprintf("%s/storage/v1/b/%s/o/%s?alt=media", gcsEndpoint, url.PathEscape(bucket),
+		url.PathEscape(object))
+	if GCSProject != "" {
+		u += "&userProject=" + url.QueryEscape(GCSProject)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	req.Header.Set("User-Agent", util.UserAgent())
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+
+	switch resp.StatusCode {
+	case http.StatusOK:
+		return resp.Body, nil
+	case http.StatusUnauthorized, http.StatusForbidden:
+		err = fmt.Errorf("%w: %s", ErrGCSAccess, source)
+	case http.StatusNotFound:
+		err = fmt.Errorf("%w: %s", ErrGCSNotFound, source)
+	default:
+		err = fmt.Errorf("%w: %s", ErrSourceStatus, resp.Status)
+	}
+
+	_ = resp.Body.Close()
+
+	return nil, err
+}
+
+type gzipReadCloser struct {
+	*gzip.Reader
+	src io.Closer
+}
+
+func (g *gzipReadCloser) Close() error {
+	_ = g.Reader.Close()
+	return g.src.Close()
+}
+
+// maybeGunzip transparently decompresses the gzip compressed input,
+// which is detected by the gzip magic number.
+// Used for gs:// objects, which are often stored compressed without the gzip content encoding.
+func maybeGunzip(r io.ReadCloser) (io.ReadCloser, error) {
+	br := bufio.NewReaderSize(r, InputBufferSize)
+
+	magic, _ := br.Peek(2)
+	if len(magic) < 2 || magic[0] != 0x1f || magic[1] != 0x8b {
+		return struct {
+			io.Reader
+			io.Closer
+		}{br, r}, nil
+	}
+
+	gr, err := gzip.NewReader(br)
+	if err != nil {
+		_ = r.Close()
+		return nil, err
+	}
+
+	return &gzipReadCloser{Reader: gr, src: r}, nil
+}
@@ -0,0 +1,143 @@
+// Copyright 2022-2023 Tigris Data, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package iterate
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGCSInput(t *testing.T) {
+	var gz bytes.Buffer
+
+	w := gzip.NewWriter(&gz)
+	_, err := w.Write([]byte(`{"id":3}`))
+	require.NoError(t, err)
+	require.NoError(t, w.Close())
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.EscapedPath() {
+		case "/storage/v1/b/bucket/o/dir%2Fdocs.json":
+			assert.Equal(t, "media", r.URL.Query().Get("alt"))
+			assert.Equal(t, "proj1", r.URL.Query().Get("userProject"))
+
+			_, _ = w.Write([]byte(`[{"id":1},{"id":2}]`))
+		case "/storage/v1/b/bucket/o/docs.json.gz":
+			_, _ = w.Write(gz.Bytes())
+		case "/storage/v1/b/private/o/docs.json":
+			w.WriteHeader(http.StatusForbidden)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer srv.Close()
+
+	endpoint, client := gcsEndpoint, gcsClient
+
+	gcsEndpoint = srv.URL
+	gcsClient = func(ctx context.Context) (*http.Client, error) { return srv.Client(), nil }
+	GCSProject = "proj1"
+
+	defer func() {
+		gcsEndpoint, gcsClient = endpoint, client
+		GCSProject = ""
+	}()
+
+	var res []json.RawMessage
+
+	process := func(ctx context.Context, args []string, docs []json.RawMessage) error {
+		res = append(res, docs...)
+		return nil
+	}
+
+	err = Input(context.Background(), &cobra.Command{}, 0,
+		[]string{"gs://bucket/dir/docs.json", "gs://bucket/docs.json.gz", `{"id":4}`}, process)
+	require.NoError(t, err)
+
+	assert.Equal(t, []json.RawMessage{
+		json.RawMessage(`{"id":1}`),
+		json.RawMessage(`{"id":2}`),
+		json.RawMessage(`{"id":3}`),
+		json.RawMessage(`{"id":4}`),
+	}, res)
+
+	err = SourceInput(context.Background(), nil, "gs://private/docs.json", process)
+	require.ErrorIs(t, err, ErrGCSAccess)
+
+	err = SourceInput(context.Background(), nil, "gs://bucket/missing.json", process)
+	require.ErrorIs(t, err, ErrGCSNotFound)
+
+	err = SourceInput(context.Background(), nil, "gs://bucket", process)
+	require.ErrorIs(t, err, ErrGCSInvalidURL)
+}
+
+func TestOpenSourceGzip(t *testing.T) {
+	var gz bytes.Buffer
+
+	w := gzip.NewWriter(&gz)
+	_, err := w.Write([]byte(`{"id":1}`))
+	require.NoError(t, err)
+	require.NoError(t, w.Close())
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write(gz.Bytes())
+	}))
+	defer srv.Close()
+
+	endpoint, client := gcsEndpoint, gcsClient
+
+	gcsEndpoint = srv.URL
+	gcsClient = func(ctx context.Context) (*http.Client, error) { return srv.Client(), nil }
+
+	defer func() { gcsEndpoint, gcsClient = endpoint, client }()
+
+	name := filepath.Join(t.TempDir(), "docs.json.gz")
+	require.NoError(t, os.WriteFile(name, gz.Bytes(), 0o600))
+
+	cases := []struct {
+		name   string
+		source string
+		exp    []byte
+	}{
+		{"gs object is decompressed", "gs://bucket/docs.json.gz", []byte(`{"id":1}`)},
+		{"http source is read as is", srv.URL + "/docs.json.gz", gz.Bytes()},
+		{"local file is read as is", name, gz.Bytes()},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			r, err := openSource(context.Background(), c.source)
+			require.NoError(t, err)
+
+			defer func() { _ = r.Close() }()
+
+			b, err := io.ReadAll(r)
+			require.NoError(t, err)
+			assert.Equal(t, c.exp, b)
+		})
+	}
+}
@@ -0,0 +1,131 @@
+// Copyright 2022-2023 Tigris Data, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package iterate
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"sync/atomic"
+
+	"github.com/tigrisdata/tigris-cli/util"
+)
+
+const exitCodeInterrupted = 130
+
+var (
+	ErrInterrupted = fmt.Errorf("interrupted. stopped after finishing the current batch")
+
+	interrupted atomic.Bool
+
+	// interruptCh is closed by the first SIGINT, unblocking the readers of the input.
+	interruptCh chan struct{}
+)
+
+// handleInterrupt installs SIGINT handler for the duration of the iteration.
+// First SIGINT stops processing of the new batches, letting the in-flight batch to finish,
+// and stops reading the input, so the iteration is not stuck waiting for the input which never arrives.
+// Second SIGINT terminates the process immediately.
+// Returns the function which uninstalls the handler.
+func handleInterrupt() func() {
+	ch := make(chan os.Signal, 1)
+	done := make(chan struct{})
+	stop := make(chan struct{})
+
+	interruptCh = stop
+
+	signal.Notify(ch, os.Interrupt)
+
+	go func() {
+		select {
+		case <-ch:
+		case <-done:
+			return
+		}
+
+		interrupted.Store(true)
+		close(stop)
+
+		util.Stderrf("\nInterrupted. Finishing the current batch, press Ctrl-C again to force quit\n")
+
+		select {
+		case <-ch:
+			util.Stderrf("Force quit\n")
+			os.Exit(exitCodeInterrupted) //nolint:revive
+		case <-done:
+		}
+	}()
+
+	return func() {
+		signal.Stop(ch)
+		close(done)
+
+		interrupted.Store(false)
+		interruptCh = nil
+	}
+}
+
+type readResult struct {
+	n   int
+	err error
+}
+
+// interruptibleReader returns ErrInterrupted as soon as the process is interrupted,
+// even when the underlying reader is blocked waiting for the input, like idle stdin.
+// The blocked read is abandoned, the reader is not usable after the interruption.
+type interruptibleReader struct {
+	r    io.Reader
+	stop <-chan struct{}
+	buf  []byte
+	err  error
+}
+
+func newInterruptibleReader(r io.Reader) *interruptibleReader {
+	return &interruptibleReader{r: r, stop: interruptCh}
+}
+
+func (r *interruptibleReader) Read(p []byte) (int, error) {
+	if r.err != nil {
+		return 0, r.err
+	}
+
+	// the read is done to the own buffer, because abandoned read
+	// may complete after p is reused by the caller
+	if cap(r.buf) < len(p) {
+		r.buf = make([]byte, len(p))
+	}
+
+	buf := r.buf[:len(p)]
+	res := make(chan readResult, 1)
+
+	go func() {
+		n, err := r.r.Read(buf)
+		res <- readResult{n: n, err: err}
+	}()
+
+	select {
+	case v := <-res:
+		return copy(p, buf[:v.n]), v.err
+	case <-r.stop:
+		r.err = ErrInterrupted
+		return 0, r.err
+	}
+}
+
+// interrupted reports whether the reading has been stopped by SIGINT.
+func (r *interruptibleReader) interrupted() bool {
+	return r.err != nil
+}
@@ -22,21 +22,40 @@ import (
 	"errors"
 	"fmt"
 	"io"
+	"net/http"
 	"os"
+	"strings"
+	"sync"
+	"time"
 	"unicode"
 
 	"github.com/rs/zerolog/log"
-	"github.com/schollz/progressbar/v3"
 	"github.com/spf13/cobra"
 	"github.com/tigrisdata/tigris-cli/util"
 )
 
 var (
 	ErrNotAllDocsProcessed = fmt.Errorf("not all documents processed")
+	ErrStdinMultiple       = fmt.Errorf("standard input \"-\" can be specified only once")
+	ErrSourceStatus        = fmt.Errorf("unexpected response status")
+	ErrSourceIsDir         = fmt.Errorf("source is a directory")
 
 	BatchSize int32 = 100
+
+	// NoProgress disables the progress bar, which is otherwise shown when stdout is a terminal.
+	NoProgress bool
+
+	// inputMu guards the state shared by the concurrent iterations, see acquireInput.
+	inputMu sync.Mutex
+	// inputs is the number of the iterations in progress.
+	inputs        int
+	stopInterrupt func()
 )
 
+func showProgress() bool {
+	return !NoProgress && util.IsTTY(os.Stderr)
+}
+
 func readFirstRune(r io.RuneScanner) rune {
 	var c rune
 
@@ -45,7 +64,7 @@ func readFirstRune(r io.RuneScanner) rune {
 
 		c, _, err = r.ReadRune()
 		if err != nil {
-			if errors.Is(err, io.EOF) {
+			if errors.Is(err, io.EOF) || errors.Is(err, ErrInterrupted) {
 				return 0
 			}
 
@@ -81,49 +100,174 @@ func readArray(r []byte) []json.RawMessage {
 		util.Fatal(err, "reading parsing array of documents")
 	}
 
+	for k, v := range arr {
+		if err := checkStrictJSON(v); err != nil {
+			util.Fatal(fmt.Errorf("%w in document %d", err, k+1), "reading parsing array of documents")
+		}
+	}
+
 	return arr
 }
 
+// readStream reads the stream of the JSON documents, which are concatenated
+// with or without whitespace in between, like {...}{...} or {...}\n{...}.
+func readStream(r []byte) []json.RawMessage {
+	docs := make([]json.RawMessage, 0, 1)
+
+	dec, lc := newJSONDecoder(bytes.NewReader(r))
+
+	for dec.More() {
+		v, err := decodeStrict(dec, lc)
+		util.Fatal(err, "reading documents from stream of documents")
+
+		docs = append(docs, v)
+	}
+
+	return docs
+}
+
+// iterateStream reads the stream of the JSON documents, concatenated
+// with or without whitespace in between, like JSONL or {...}{...}.
 func iterateStream(ctx context.Context, args []string, r io.Reader, fn func(ctx2 context.Context, args []string,
 	docs []json.RawMessage) error,
 ) error {
-	var bar *progressbar.ProgressBar
+	dec, lc := newJSONDecoder(r)
+
+	return iterateDecoder(ctx, args, dec, func(dec *json.Decoder) (json.RawMessage, error) {
+		v, err := decodeStrict(dec, lc)
+		if errors.Is(err, ErrInterrupted) {
+			return nil, err
+		}
+
+		util.Fatal(err, "reading documents from stream of documents")
+
+		return v, nil
+	}, fn)
+}
+
+// iterateDecoder batches the documents returned by the next function,
+// until decoder has more data.
+func iterateDecoder(ctx context.Context, args []string, dec *json.Decoder,
+	next func(dec *json.Decoder) (json.RawMessage, error),
+	fn func(ctx2 context.Context, args []string, docs []json.RawMessage) error,
+) error {
+	return iterateDocs(ctx, args, func() (json.RawMessage, error) {
+		if !dec.More() {
+			return nil, io.EOF
+		}
+
+		return next(dec)
+	}, fn)
+}
+
+func isLimitError(err error) bool {
+	return err.Error() == "document exceeds limit" || err.Error() == "transaction exceeds limit"
+}
+
+// processBatch checks the documents for duplicate keys, adds row numbers, checks the size,
+// compacts the documents, normalizes Unicode strings, sanitizes field names, converts empty strings to nulls,
+// sets default values and timestamps, adds derived fields, filters the documents by the Where predicate
+// and the watermark, projects the documents to the selected fields, validates the batch, encrypts the fields
+// and passes valid documents for processing.
+// Batches are not processed anymore after the process has been interrupted.
+func processBatch(ctx context.Context, args []string, docs []json.RawMessage,
+	fn func(ctx2 context.Context, args []string, docs []json.RawMessage) error,
+) error {
+	if interrupted.Load() {
+		return ErrInterrupted
+	}
+
+	r := RunFrom(ctx)
 
-	if util.IsTTY(os.Stdout) {
-		bar = progressbar.Default(-1)
+	first := r.seen + 1
+	r.seen += int64(len(docs))
+
+	if err := checkDuplicateKeys(docs, first); err != nil {
+		return err
 	}
 
-	dec := json.NewDecoder(r)
+	if err := addRowNumbers(docs, first); err != nil {
+		return err
+	}
 
-	for {
-		docs := make([]json.RawMessage, 0, BatchSize)
+	docs, err := r.checkDocSizes(docs, first)
+	if err != nil {
+		return err
+	}
 
-		var i int32
+	if err = compactDocs(docs); err != nil {
+		return err
+	}
 
-		for ; i < BatchSize && dec.More(); i++ {
-			var v json.RawMessage
+	if err = normalizeDocs(docs); err != nil {
+		return err
+	}
 
-			err := dec.Decode(&v)
-			util.Fatal(err, "reading documents from stream of documents")
+	if err = sanitizeKeys(docs); err != nil {
+		return err
+	}
 
-			docs = append(docs, v)
-		}
+	if err = emptyStringsToNulls(docs); err != nil {
+		return err
+	}
 
-		if i == 0 {
-			break
-		} else if err := varyBatch(ctx, args, docs, fn); err != nil {
-			return err
-		}
+	if err = applyDefaults(docs); err != nil {
+		return err
+	}
 
-		if util.IsTTY(os.Stdout) {
-			_ = bar.Add(int(i))
-		}
+	if err = stampTimestamps(docs); err != nil {
+		return err
 	}
 
-	return nil
+	if err = addDerivedFields(docs); err != nil {
+		return err
+	}
+
+	if docs, err = filterDocs(docs); err != nil {
+		return err
+	}
+
+	docs, batchMax, err := filterWatermark(docs)
+	if err != nil {
+		return err
+	}
+
+	if err = projectDocs(docs); err != nil {
+		return err
+	}
+
+	if docs, err = r.validateDocs(docs); err != nil {
+		return err
+	}
+
+	if len(docs) == 0 {
+		return nil
+	}
+
+	if err = encryptFields(docs); err != nil {
+		return err
+	}
+
+	if err = collectFieldStats(docs); err != nil {
+		return err
+	}
+
+	start := time.Now()
+
+	err = varyBatch(ctx, args, docs, fn)
+
+	r.recordBatch(len(docs), start, err)
+
+	if err == nil {
+		advanceWatermark(batchMax)
+	}
+
+	return err
 }
 
 // varyBatch dynamically reduces the batch on document-exceeded-limit error and retries.
+// When SkipErrors is set, failed batch is bisected the same way, until failed documents isolated
+// and saved to the error file.
 func varyBatch(ctx context.Context, args []string, docs []json.RawMessage,
 	process func(ctx2 context.Context, args []string, docs []json.RawMessage) error,
 ) error {
@@ -132,27 +276,42 @@ func varyBatch(ctx context.Context, args []string, docs []json.RawMessage,
 	total := 0
 
 	for first < len(docs) {
-		if err := process(ctx, args, docs[first:last]); err != nil {
-			if (err.Error() == "document exceeds limit" || err.Error() == "transaction exceeds limit") &&
-				last-first > 1 {
-				last = first + (last-first)/2 // exponentially reduce the batch size
+		// request may be split before sending, the batch size is retained for the next request
+		end := limitRequestSize(docs, first, last)
+
+		if err := dispatch(ctx, args, docs[first:end], process); err != nil {
+			if (isLimitError(err) || SkipErrors) && end-first > 1 {
+				last = first + (end-first)/2 // exponentially reduce the batch size
 
 				log.Debug().Msgf("reducing batch size. first=%d, last=%d, len=%d", first, last, len(docs))
 
 				continue
-			} else if last-first == 1 {
+			} else if end-first == 1 {
 				log.Debug().RawJSON("doc", docs[first]).Msgf("failed to process")
+
+				if SkipErrors {
+					if err = RunFrom(ctx).writeErrorDoc(docs[first], err); err != nil {
+						return err
+					}
+
+					total++
+
+					first = end
+					last = len(docs) // retry the rest of the batch at once
+
+					continue
+				}
 			}
 
 			return err
 		}
 
-		log.Debug().Msgf("succeeded batch. first=%d, last=%d, len=%d", first, last, len(docs))
+		log.Debug().Msgf("succeeded batch. first=%d, last=%d, len=%d", first, end, len(docs))
 
 		sz := last - first // retain and reuse the batch-size which succeeded
-		total += sz
+		total += end - first
 
-		first = last
+		first = end
 
 		last = first + sz
 		if last > len(docs) {
@@ -167,71 +326,393 @@ func varyBatch(ctx context.Context, args []string, docs []json.RawMessage,
 	return nil
 }
 
+// iterateArray streams the elements of the array of documents,
+// so only the current batch is kept in memory, regardless of the size of the array.
 func iterateArray(ctx context.Context, args []string, r io.Reader, fn func(ctx2 context.Context, args []string,
 	docs []json.RawMessage) error,
 ) error {
-	buf, err := io.ReadAll(r)
-	util.Fatal(err, "error reading documents")
+	dec, lc := newJSONDecoder(r)
+
+	_, err := dec.Token() // opening bracket
+	util.Fatal(err, "reading parsing array of documents")
 
-	allDocs := readArray(buf)
+	err = iterateDecoder(ctx, args, dec, func(dec *json.Decoder) (json.RawMessage, error) {
+		v, err := decodeStrict(dec, lc)
+		if errors.Is(err, ErrInterrupted) {
+			return nil, err
+		}
+
+		util.Fatal(err, "reading parsing array of documents")
 
-	var bar *progressbar.ProgressBar
+		return v, nil
+	}, fn)
+	if err != nil {
+		return err
+	}
 
-	if util.IsTTY(os.Stdout) {
-		bar = progressbar.Default(int64(len(allDocs)))
+	_, err = dec.Token() // closing bracket
+	if errors.Is(err, ErrInterrupted) {
+		return err
 	}
 
-	for j := 0; j < len(allDocs); {
-		docs := make([]json.RawMessage, 0, BatchSize)
+	util.Fatal(err, "reading parsing array of documents")
+	util.Fatal(checkTrailingData(dec, lc), "reading parsing array of documents")
+
+	return nil
+}
 
-		var i int32
-		for ; i < BatchSize && j < len(allDocs); i++ {
-			docs = append(docs, allDocs[j])
-			j++
+func stdinInput(ctx context.Context, args []string,
+	fn func(ctx2 context.Context, args []string, docs []json.RawMessage) error,
+) error {
+	return readerInput(ctx, args, os.Stdin, fn)
+}
+
+// readerInput detects the format of the input: CSV, array or stream of JSON documents,
+// and iterates the documents accordingly. MessagePack input is not detected,
+// it's only read when requested by Format. Top-level object map is read when ObjectMapKey is set.
+func readerInput(ctx context.Context, args []string, rd io.Reader,
+	fn func(ctx2 context.Context, args []string, docs []json.RawMessage) error,
+) error {
+	ir := newInterruptibleReader(rd)
+
+	err := readerInputLow(ctx, args, ir, fn)
+	if err == nil && ir.interrupted() {
+		// the input was cut, while the reader treated it as the end of the input
+		return ErrInterrupted
+	}
+
+	return err
+}
+
+func readerInputLow(ctx context.Context, args []string, rd io.Reader,
+	fn func(ctx2 context.Context, args []string, docs []json.RawMessage) error,
+) error {
+	if Format == FormatMsgPack {
+		return iterateMsgPack(ctx, args, rd, fn)
+	}
+
+	in, err := decodeInput(rd)
+	if err != nil {
+		return err
+	}
+
+	if ObjectMapKey != "" {
+		return iterateObjectMap(ctx, args, in, fn)
+	}
+
+	r := bufio.NewReaderSize(in, InputBufferSize)
+	if Format == FormatCSV || (Format == "" && detectCSV(r)) {
+		return iterateCSVStream(ctx, args, r, fn)
+	} else if detectArray(r) {
+		return iterateArray(ctx, args, r, fn)
+	}
+
+	return iterateStream(ctx, args, r, fn)
+}
+
+// startInput prepares the iteration and returns the function,
+// which reports the results and releases the resources after iteration is done.
+// The counters are kept in the run of the context.
+// Iteration aborted by --max-errors or --max-error-rate terminates the process with ExitCodeMaxErrors.
+func startInput(ctx context.Context) (func(), error) {
+	r := RunFrom(ctx)
+
+	r.seen = 0
+	r.batchStats = nil
+	r.oversized = nil
+	r.errorClasses = nil
+
+	if err := acquireInput(); err != nil {
+		return nil, err
+	}
+
+	done := func() {
+		r.reportOversized()
+		r.reportErrors()
+
+		releaseInput()
+
+		if err := r.checkMaxErrors(); err != nil {
+			util.PrintError(err)
+			util.RunExitHooks(err)
+			os.Exit(ExitCodeMaxErrors) //nolint:revive
 		}
+	}
 
-		if err = varyBatch(ctx, args, docs, fn); err != nil {
-			return err
+	return done, nil
+}
+
+// acquireInput prepares the state shared by the concurrent iterations:
+// the options parsed from the flags, the SIGINT handler, the error and the tee files.
+// The state is prepared by the first iteration and released by the last one.
+func acquireInput() error {
+	inputMu.Lock()
+	defer inputMu.Unlock()
+
+	if inputs++; inputs > 1 {
+		return nil
+	}
+
+	stopInterrupt = handleInterrupt()
+
+	if MaxErrors > 0 || MaxErrorRate > 0 {
+		SkipErrors = true
+	}
+
+	err := prepareInput()
+	if err != nil {
+		releaseInputLocked()
+	}
+
+	return err
+}
+
+func prepareInput() error {
+	if err := parseAddFields(); err != nil {
+		return err
+	}
+
+	if err := parseDefaults(); err != nil {
+		return err
+	}
+
+	if err := parseWhere(); err != nil {
+		return err
+	}
+
+	if err := parseOnlyFields(); err != nil {
+		return err
+	}
+
+	if err := parseNormalization(); err != nil {
+		return err
+	}
+
+	if err := loadWatermark(); err != nil {
+		return err
+	}
+
+	if err := prepareEncryption(); err != nil {
+		return err
+	}
+
+	return loadValidator()
+}
+
+// releaseInput reports the results of the shared state and releases it,
+// when the last of the concurrent iterations is done.
+func releaseInput() {
+	inputMu.Lock()
+	defer inputMu.Unlock()
+
+	releaseInputLocked()
+}
+
+func releaseInputLocked() {
+	if inputs--; inputs > 0 {
+		return
+	}
+
+	reportValidationFailures()
+	reportFieldStats()
+	reportSanitizedKeys()
+	reportFiltered()
+	reportWatermark()
+	stopInterrupt()
+	closeErrorFile()
+	closeTee()
+
+	stopInterrupt = nil
+}
+
+func isURLSource(s string) bool {
+	return strings.HasPrefix(s, "http://") || strings.HasPrefix(s, "https://") || isGCSURL(s)
+}
+
+// openSource opens the source of the documents: "-" for the standard input,
+// http://, https:// or gs:// URL, or the file name.
+// Gzip compressed gs:// objects are decompressed transparently, other sources are read as is.
+func openSource(ctx context.Context, source string) (io.ReadCloser, error) {
+	if source == "-" {
+		return io.NopCloser(os.Stdin), nil
+	}
+
+	r, err := openSourceLow(ctx, source)
+	if err != nil || !isGCSURL(source) {
+		return r, err
+	}
+
+	return maybeGunzip(r)
+}
+
+func openSourceLow(ctx context.Context, source string) (io.ReadCloser, error) {
+	switch {
+	case isGCSURL(source):
+		return openGCS(ctx, source)
+	case strings.HasPrefix(source, "http://") || strings.HasPrefix(source, "https://"):
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, source, nil)
+		if err != nil {
+			return nil, err
+		}
+
+		req.Header.Set("User-Agent", util.UserAgent())
+
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			return nil, err
+		}
+
+		if resp.StatusCode != http.StatusOK {
+			_ = resp.Body.Close()
+			return nil, fmt.Errorf("%w: %s", ErrSourceStatus, resp.Status)
 		}
 
-		if util.IsTTY(os.Stdout) {
-			_ = bar.Add(int(i))
+		return resp.Body, nil
+	}
+
+	return openFile(source)
+}
+
+// openFile opens the local file source. Named pipes, sockets and devices are read
+// the same way as regular files, sequentially, as unbounded streams.
+// The size of the file is not used and the file is never seeked,
+// so the documents are processed as they arrive.
+func openFile(name string) (io.ReadCloser, error) {
+	f, err := os.Open(name)
+	if err != nil {
+		return nil, err
+	}
+
+	fi, err := f.Stat()
+	if err != nil {
+		_ = f.Close()
+		return nil, err
+	}
+
+	if fi.IsDir() {
+		_ = f.Close()
+		return nil, fmt.Errorf("%w: %s", ErrSourceIsDir, name)
+	}
+
+	if fi.Mode()&(os.ModeNamedPipe|os.ModeSocket|os.ModeCharDevice) != 0 {
+		log.Debug().Str("source", name).Msg("reading the source as unbounded stream")
+	}
+
+	return f, nil
+}
+
+// SourceInput reads documents from the source, which is a file name,
+// http://, https:// or gs:// URL, or "-" for the standard input.
+// The format of the documents is detected the same way as in Input.
+func SourceInput(ctx context.Context, args []string, source string,
+	fn func(ctx2 context.Context, args []string, docs []json.RawMessage) error,
+) error {
+	return SourcesInput(ctx, args, []string{source}, fn)
+}
+
+// SourcesInput reads documents from the sources one after another,
+// as one logical stream. See SourceInput for the supported sources.
+func SourcesInput(ctx context.Context, args []string, sources []string,
+	fn func(ctx2 context.Context, args []string, docs []json.RawMessage) error,
+) error {
+	done, err := startInput(ctx)
+	if err != nil {
+		return err
+	}
+
+	defer done()
+
+	for _, source := range sources {
+		if err = sourceInput(ctx, args, source, fn); err != nil {
+			return err
 		}
 	}
 
 	return nil
 }
 
+func sourceInput(ctx context.Context, args []string, source string,
+	fn func(ctx2 context.Context, args []string, docs []json.RawMessage) error,
+) error {
+	r, err := openSource(ctx, source)
+	if err != nil {
+		return util.Error(err, "open source: %s", source)
+	}
+
+	defer func() { _ = r.Close() }()
+
+	return readerInput(ctx, args, r, fn)
+}
+
 // Input reads repeated command parameters from standard input or args.
 // Supports newline delimited stream of objects and arrays of objects.
+//
+// Literal documents in args can be mixed with "-", which marks the position
+// of the standard input, and with http://, https:// and gs:// URLs of the sources. Sources are processed in the order they appear
+// in the command line, as one logical stream, so the schema is accumulated
+// across all the sources.
 func Input(ctx context.Context, cmd *cobra.Command, docsPosition int, args []string,
 	fn func(ctx2 context.Context, args []string, docs []json.RawMessage) error,
 ) error {
-	if len(args) > docsPosition && args[docsPosition] != "-" {
-		docs := make([]json.RawMessage, 0, len(args))
+	done, err := startInput(ctx)
+	if err != nil {
+		return err
+	}
+
+	defer done()
 
-		for _, v := range args[docsPosition:] {
+	if len(args) <= docsPosition {
+		if util.IsTTY(os.Stdin) {
+			_, _ = fmt.Fprintf(os.Stderr, "not enougn arguments\n")
+			_ = cmd.Usage()
+			os.Exit(1) //nolint:revive
+		}
+
+		// stdin not a TTY
+		return stdinInput(ctx, args, fn)
+	}
+
+	stdinUsed := false
+	docs := make([]json.RawMessage, 0, len(args))
+
+	for _, v := range args[docsPosition:] {
+		if v != "-" && !isURLSource(v) {
 			if detectArray(bufio.NewReader(bytes.NewReader([]byte(v)))) {
 				docs = append(docs, readArray([]byte(v))...)
+			} else if strings.HasPrefix(strings.TrimSpace(v), "{") {
+				docs = append(docs, readStream([]byte(v))...)
 			} else {
 				docs = append(docs, json.RawMessage(v))
 			}
+
+			continue
 		}
 
-		return fn(ctx, args, docs)
-	} else if len(args) <= docsPosition && util.IsTTY(os.Stdin) {
-		_, _ = fmt.Fprintf(os.Stderr, "not enougn arguments\n")
-		_ = cmd.Usage()
-		os.Exit(1) //nolint:revive
+		if v == "-" {
+			if stdinUsed {
+				return ErrStdinMultiple
+			}
+
+			stdinUsed = true
+		}
+
+		// flush the documents preceding the stdin or URL
+		if len(docs) > 0 {
+			if err := processBatch(ctx, args, docs, fn); err != nil {
+				return err
+			}
+
+			docs = make([]json.RawMessage, 0, len(args))
+		}
+
+		if err := sourceInput(ctx, args, v, fn); err != nil {
+			return err
+		}
 	}
 
-	// stdin not a TTY or "-" is specified
-	r := bufio.NewReader(os.Stdin)
-	if detectCSV(r) {
-		return iterateCSVStream(ctx, args, r, fn)
-	} else if detectArray(r) {
-		return iterateArray(ctx, args, r, fn)
+	if len(docs) == 0 {
+		return nil
 	}
 
-	return iterateStream(ctx, args, r, fn)
+	return processBatch(ctx, args, docs, fn)
 }
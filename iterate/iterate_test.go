@@ -0,0 +1,454 @@
+// Copyright 2022-2023 Tigris Data, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package iterate
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func setStdin(t *testing.T, data string) {
+	t.Helper()
+
+	name := filepath.Join(t.TempDir(), "stdin")
+	require.NoError(t, os.WriteFile(name, []byte(data), 0o600))
+
+	f, err := os.Open(name)
+	require.NoError(t, err)
+
+	stdin := os.Stdin
+	os.Stdin = f
+
+	t.Cleanup(func() {
+		os.Stdin = stdin
+		_ = f.Close()
+	})
+}
+
+func TestInputMixedStdin(t *testing.T) {
+	var docs []json.RawMessage
+
+	process := func(ctx context.Context, args []string, d []json.RawMessage) error {
+		docs = append(docs, d...)
+		return nil
+	}
+
+	setStdin(t, `{"id":2}
+{"id":3}
+`)
+
+	err := Input(context.Background(), &cobra.Command{}, 1,
+		[]string{"coll", `{"id":1}`, "-", `[{"id":4},{"id":5}]`, `{"id":6}`}, process)
+	require.NoError(t, err)
+
+	assert.Equal(t, []json.RawMessage{
+		json.RawMessage(`{"id":1}`),
+		json.RawMessage(`{"id":2}`),
+		json.RawMessage(`{"id":3}`),
+		json.RawMessage(`{"id":4}`),
+		json.RawMessage(`{"id":5}`),
+		json.RawMessage(`{"id":6}`),
+	}, docs)
+
+	err = Input(context.Background(), &cobra.Command{}, 1, []string{"coll", "-", `{"id":1}`, "-"}, process)
+	require.ErrorIs(t, err, ErrStdinMultiple)
+}
+
+func TestInputConcatenated(t *testing.T) {
+	cases := []struct {
+		name string
+		data string
+	}{
+		{"newline", "{\"id\":1}\n{\"id\":2}\n{\"id\":3}\n"},
+		{"space", `{"id":1} {"id":2}	{"id":3}`},
+		{"nothing", `{"id":1}{"id":2}{"id":3}`},
+		{"mixed", "\n {\"id\":1}{\"id\":2}\r\n\n{\"id\":3}  "},
+	}
+
+	expected := []json.RawMessage{
+		json.RawMessage(`{"id":1}`),
+		json.RawMessage(`{"id":2}`),
+		json.RawMessage(`{"id":3}`),
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			var docs []json.RawMessage
+
+			process := func(ctx context.Context, args []string, d []json.RawMessage) error {
+				docs = append(docs, d...)
+				return nil
+			}
+
+			setStdin(t, c.data)
+
+			err := Input(context.Background(), &cobra.Command{}, 1, []string{"coll", "-"}, process)
+			require.NoError(t, err)
+			assert.Equal(t, expected, docs)
+
+			docs = nil
+
+			err = Input(context.Background(), &cobra.Command{}, 1, []string{"coll", c.data}, process)
+			require.NoError(t, err)
+			assert.Equal(t, expected, docs)
+		})
+	}
+}
+
+func TestInputPlainArgs(t *testing.T) {
+	var docs []json.RawMessage
+
+	process := func(ctx context.Context, args []string, d []json.RawMessage) error {
+		docs = append(docs, d...)
+		return nil
+	}
+
+	err := Input(context.Background(), &cobra.Command{}, 0, []string{"coll1", "coll2", `{"id":1}{"id":2}`}, process)
+	require.NoError(t, err)
+
+	assert.Equal(t, []json.RawMessage{
+		json.RawMessage("coll1"),
+		json.RawMessage("coll2"),
+		json.RawMessage(`{"id":1}`),
+		json.RawMessage(`{"id":2}`),
+	}, docs)
+}
+
+func TestInputInterrupt(t *testing.T) {
+	BatchSize = 1
+
+	defer func() { BatchSize = 100 }()
+
+	setStdin(t, `{"id":1}
+{"id":2}
+{"id":3}
+`)
+
+	var docs []json.RawMessage
+
+	process := func(ctx context.Context, args []string, d []json.RawMessage) error {
+		if len(docs) == 0 {
+			p, err := os.FindProcess(os.Getpid())
+			require.NoError(t, err)
+			require.NoError(t, p.Signal(os.Interrupt))
+
+			require.Eventually(t, interrupted.Load, time.Second, time.Millisecond)
+		}
+
+		docs = append(docs, d...)
+
+		return nil
+	}
+
+	err := Input(context.Background(), &cobra.Command{}, 1, []string{"coll"}, process)
+	require.ErrorIs(t, err, ErrInterrupted)
+
+	// in-flight batch is finished
+	assert.Equal(t, []json.RawMessage{json.RawMessage(`{"id":1}`)}, docs)
+}
+
+// TestInputInterruptIdle checks that the interrupted import doesn't wait for the input,
+// which never arrives, when the stdin is kept open.
+func TestInputInterruptIdle(t *testing.T) {
+	BatchSize = 1
+
+	defer func() { BatchSize = 100 }()
+
+	cases := []struct {
+		name string
+		data string
+	}{
+		{"stream", "{\"id\":1}\n"},
+		{"partial document", "{\"id\":1}\n{\"id\""},
+		{"array", "[{\"id\":1},"},
+		{"csv", "id\n1\n"},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			r, w, err := os.Pipe()
+			require.NoError(t, err)
+
+			stdin := os.Stdin
+			os.Stdin = r
+
+			defer func() {
+				os.Stdin = stdin
+				_ = w.Close()
+				_ = r.Close()
+			}()
+
+			_, err = w.WriteString(c.data)
+			require.NoError(t, err)
+
+			var docs []json.RawMessage
+
+			process := func(ctx context.Context, args []string, d []json.RawMessage) error {
+				p, err := os.FindProcess(os.Getpid())
+				require.NoError(t, err)
+				require.NoError(t, p.Signal(os.Interrupt))
+
+				require.Eventually(t, interrupted.Load, time.Second, time.Millisecond)
+
+				docs = append(docs, d...)
+
+				return nil
+			}
+
+			done := make(chan error, 1)
+
+			go func() {
+				done <- Input(context.Background(), &cobra.Command{}, 1, []string{"coll"}, process)
+			}()
+
+			select {
+			case err = <-done:
+			case <-time.After(5 * time.Second):
+				require.Fail(t, "import is not stopped by the interrupt")
+			}
+
+			require.ErrorIs(t, err, ErrInterrupted)
+			assert.Equal(t, []json.RawMessage{json.RawMessage(`{"id":1}`)}, docs)
+		})
+	}
+}
+
+func TestInterruptibleReader(t *testing.T) {
+	pr, pw := io.Pipe()
+
+	defer func() { _ = pw.Close() }()
+
+	stop := make(chan struct{})
+	r := &interruptibleReader{r: pr, stop: stop}
+
+	go func() { _, _ = pw.Write([]byte("abc")) }()
+
+	b := make([]byte, 10)
+
+	n, err := r.Read(b)
+	require.NoError(t, err)
+	assert.Equal(t, "abc", string(b[:n]))
+	assert.False(t, r.interrupted())
+
+	// nothing is written to the pipe anymore, the read is unblocked by the stop
+	close(stop)
+
+	_, err = r.Read(b)
+	require.ErrorIs(t, err, ErrInterrupted)
+	assert.True(t, r.interrupted())
+
+	_, err = r.Read(b)
+	require.ErrorIs(t, err, ErrInterrupted)
+}
+
+func TestSourceInput(t *testing.T) {
+	data := `[{"id":1},{"id":2}]`
+
+	name := filepath.Join(t.TempDir(), "docs.json")
+	require.NoError(t, os.WriteFile(name, []byte(data), 0o600))
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/docs.csv" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+
+		_, _ = w.Write([]byte("id,name\n1,a\n"))
+	}))
+	defer srv.Close()
+
+	var res []json.RawMessage
+
+	process := func(ctx context.Context, args []string, docs []json.RawMessage) error {
+		res = append(res, docs...)
+		return nil
+	}
+
+	require.NoError(t, SourceInput(context.Background(), nil, name, process))
+	require.NoError(t, SourceInput(context.Background(), nil, srv.URL+"/docs.csv", process))
+
+	assert.Equal(t, []json.RawMessage{
+		json.RawMessage(`{"id":1}`),
+		json.RawMessage(`{"id":2}`),
+		json.RawMessage(`{"id":1,"name":"a"}`),
+	}, res)
+
+	err := SourceInput(context.Background(), nil, srv.URL+"/missing.json", process)
+	require.ErrorIs(t, err, ErrSourceStatus)
+}
+
+// syntheticArray generates JSON array of n documents on the fly.
+type syntheticArray struct {
+	n, i int
+	buf  []byte
+}
+
+func (s *syntheticArray) Read(p []byte) (int, error) {
+	for len(s.buf) < len(p) && s.i <= s.n {
+		switch {
+		case s.i == 0:
+			s.buf = append(s.buf, '[')
+		case s.i == s.n:
+			s.buf = append(s.buf, fmt.Sprintf(`{"id":%d,"payload":"%0100d"}]`, s.i, s.i)...)
+		default:
+			s.buf = append(s.buf, fmt.Sprintf(`{"id":%d,"payload":"%0100d"},`, s.i, s.i)...)
+		}
+
+		s.i++
+	}
+
+	if len(s.buf) == 0 {
+		return 0, io.EOF
+	}
+
+	n := copy(p, s.buf)
+	s.buf = s.buf[n:]
+
+	return n, nil
+}
+
+func heapAlloc() uint64 {
+	var m runtime.MemStats
+
+	runtime.GC()
+	runtime.ReadMemStats(&m)
+
+	return m.HeapAlloc
+}
+
+func TestArrayInputBoundedMemory(t *testing.T) {
+	const numDocs = 200000 // ~25MB of input
+
+	base := heapAlloc()
+
+	var (
+		count   int
+		batches int
+		peak    uint64
+	)
+
+	err := readerInput(context.Background(), nil, &syntheticArray{n: numDocs},
+		func(ctx context.Context, args []string, docs []json.RawMessage) error {
+			require.LessOrEqual(t, len(docs), int(BatchSize))
+
+			count += len(docs)
+			batches++
+
+			if batches%100 == 0 {
+				if h := heapAlloc(); h > peak {
+					peak = h
+				}
+			}
+
+			return nil
+		})
+	require.NoError(t, err)
+
+	assert.Equal(t, numDocs, count)
+
+	// memory footprint doesn't depend on the size of the input
+	if peak > base {
+		assert.Less(t, peak-base, uint64(4<<20))
+	}
+}
+
+func TestInputFlushInterval(t *testing.T) {
+	FlushInterval = 10 * time.Millisecond
+
+	defer func() { FlushInterval = 0 }()
+
+	r, w := io.Pipe()
+
+	batches := make(chan []json.RawMessage, 10)
+
+	go func() {
+		_, _ = w.Write([]byte("{\"id\":1}\n{\"id\":2}\n"))
+
+		// the batch is flushed by the timer, while the stream is still open
+		assert.Equal(t, []json.RawMessage{json.RawMessage(`{"id":1}`), json.RawMessage(`{"id":2}`)}, <-batches)
+
+		_, _ = w.Write([]byte("{\"id\":3}\n"))
+		_ = w.Close()
+	}()
+
+	err := readerInput(context.Background(), nil, r,
+		func(ctx context.Context, args []string, docs []json.RawMessage) error {
+			batches <- docs
+			return nil
+		})
+	require.NoError(t, err)
+
+	assert.Equal(t, []json.RawMessage{json.RawMessage(`{"id":3}`)}, <-batches)
+}
+
+func TestInputPipeStreaming(t *testing.T) {
+	BatchSize = 2
+
+	defer func() { BatchSize = 100 }()
+
+	r, w, err := os.Pipe()
+	require.NoError(t, err)
+
+	stdin := os.Stdin
+	os.Stdin = r
+
+	defer func() {
+		os.Stdin = stdin
+		_ = r.Close()
+	}()
+
+	batches := make(chan []json.RawMessage, 10)
+
+	go func() {
+		_, _ = w.WriteString("{\"id\":1}\n{\"id\":2}\n")
+
+		// the first batch is processed while the pipe is still open,
+		// so the input is not buffered till the end
+		assert.Equal(t, []json.RawMessage{json.RawMessage(`{"id":1}`), json.RawMessage(`{"id":2}`)}, <-batches)
+
+		_, _ = w.WriteString("{\"id\":3}\n")
+		_ = w.Close()
+	}()
+
+	err = Input(context.Background(), &cobra.Command{}, 1, []string{"coll", "-"},
+		func(ctx context.Context, args []string, docs []json.RawMessage) error {
+			batches <- docs
+			return nil
+		})
+	require.NoError(t, err)
+
+	assert.Equal(t, []json.RawMessage{json.RawMessage(`{"id":3}`)}, <-batches)
+}
+
+func TestSourceInputDir(t *testing.T) {
+	err := SourceInput(context.Background(), nil, t.TempDir(),
+		func(ctx context.Context, args []string, docs []json.RawMessage) error {
+			return nil
+		})
+	require.ErrorIs(t, err, ErrSourceIsDir)
+}
@@ -0,0 +1,366 @@
+// Copyright 2022-2023 Tigris Data, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package iterate
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"strconv"
+	"time"
+
+	"github.com/tigrisdata/tigris-cli/util"
+)
+
+var (
+	ErrMsgPackType     = fmt.Errorf("unsupported msgpack type")
+	ErrMsgPackDocument = fmt.Errorf("msgpack document should be a map")
+	ErrMsgPackFloat    = fmt.Errorf("NaN and infinite msgpack floats are not supported by JSON")
+)
+
+// msgpackTimestamp is the extension type of the msgpack timestamps.
+const msgpackTimestamp = -1
+
+// msgpackDecoder converts msgpack values to JSON.
+// Binary values are converted to base64 strings, so they are detected as
+// byte arrays with --detect-byte-arrays. Timestamps are converted to RFC3339 strings.
+// The order of the map keys is preserved.
+type msgpackDecoder struct {
+	r *bufio.Reader
+}
+
+func (d *msgpackDecoder) readN(n uint64) ([]byte, error) {
+	b := make([]byte, n)
+
+	_, err := io.ReadFull(d.r, b)
+
+	return b, err
+}
+
+func (d *msgpackDecoder) readUint(size int) (uint64, error) {
+	b, err := d.readN(uint64(size))
+	if err != nil {
+		return 0, err
+	}
+
+	switch size {
+	case 1:
+		return uint64(b[0]), nil
+	case 2:
+		return uint64(binary.BigEndian.Uint16(b)), nil
+	case 4:
+		return uint64(binary.BigEndian.Uint32(b)), nil
+	default:
+		return binary.BigEndian.Uint64(b), nil
+	}
+}
+
+func writeFloat(buf *bytes.Buffer, f float64, bitSize int) error {
+	if math.IsNaN(f) || math.IsInf(f, 0) {
+		return ErrMsgPackFloat
+	}
+
+	s := strconv.FormatFloat(f, 'g', -1, bitSize)
+
+	buf.WriteString(s)
+
+	// keep the number a float, so it's not inferred as integer
+	if !bytes.ContainsAny([]byte(s), ".e") {
+		buf.WriteString(".0")
+	}
+
+	return nil
+}
+
+func writeString(buf *bytes.Buffer, s string) {
+	b, _ := json.Marshal(s)
+	buf.Write(b)
+}
+
+//nolint:gocognit,gocyclo,cyclop,funlen
+func (d *msgpackDecoder) decode(buf *bytes.Buffer) error {
+	c, err := d.r.ReadByte()
+	if err != nil {
+		return err
+	}
+
+	switch {
+	case c <= 0x7f: // positive fixint
+		buf.WriteString(strconv.FormatUint(uint64(c), 10))
+	case c >= 0xe0: // negative fixint
+		buf.WriteString(strconv.FormatInt(int64(int8(c)), 10))
+	case c >= 0x80 && c <= 0x8f:
+		return d.decodeMap(buf, uint64(c&0x0f))
+	case c >= 0x90 && c <= 0x9f:
+		return d.decodeArray(buf, uint64(c&0x0f))
+	case c >= 0xa0 && c <= 0xbf:
+		return d.decodeString(buf, uint64(c&0x1f))
+	case c == 0xc0:
+		buf.WriteString("null")
+	case c == 0xc2:
+		buf.WriteString("false")
+	case c == 0xc3:
+		buf.WriteString("true")
+	case c >= 0xc4 && c <= 0xc6: // bin 8, 16, 32
+		n, err := d.readUint(1 << (c - 0xc4))
+		if err != nil {
+			return err
+		}
+
+		b, err := d.readN(n)
+		if err != nil {
+			return err
+		}
+
+		writeString(buf, base64.StdEncoding.EncodeToString(b))
+	case c >= 0xc7 && c <= 0xc9: // ext 8, 16, 32
+		n, err := d.readUint(1 << (c - 0xc7))
+		if err != nil {
+			return err
+		}
+
+		return d.decodeExt(buf, n)
+	case c == 0xca:
+		n, err := d.readUint(4)
+		if err != nil {
+			return err
+		}
+
+		return writeFloat(buf, float64(math.Float32frombits(uint32(n))), 32)
+	case c == 0xcb:
+		n, err := d.readUint(8)
+		if err != nil {
+			return err
+		}
+
+		return writeFloat(buf, math.Float64frombits(n), 64)
+	case c >= 0xcc && c <= 0xcf: // uint 8, 16, 32, 64
+		n, err := d.readUint(1 << (c - 0xcc))
+		if err != nil {
+			return err
+		}
+
+		buf.WriteString(strconv.FormatUint(n, 10))
+	case c >= 0xd0 && c <= 0xd3: // int 8, 16, 32, 64
+		size := 1 << (c - 0xd0)
+
+		n, err := d.readUint(size)
+		if err != nil {
+			return err
+		}
+
+		// sign extend
+		shift := 64 - 8*size
+		buf.WriteString(strconv.FormatInt(int64(n<<shift)>>shift, 10))
+	case c >= 0xd4 && c <= 0xd8: // fixext 1, 2, 4, 8, 16
+		return d.decodeExt(buf, 1<<(c-0xd4))
+	case c >= 0xd9 && c <= 0xdb: // str 8, 16, 32
+		n, err := d.readUint(1 << (c - 0xd9))
+		if err != nil {
+			return err
+		}
+
+		return d.decodeString(buf, n)
+	case c == 0xdc || c == 0xdd: // array 16, 32
+		n, err := d.readUint(2 << (c - 0xdc))
+		if err != nil {
+			return err
+		}
+
+		return d.decodeArray(buf, n)
+	case c == 0xde || c == 0xdf: // map 16, 32
+		n, err := d.readUint(2 << (c - 0xde))
+		if err != nil {
+			return err
+		}
+
+		return d.decodeMap(buf, n)
+	default:
+		return fmt.Errorf("%w: 0x%x", ErrMsgPackType, c)
+	}
+
+	return nil
+}
+
+func (d *msgpackDecoder) decodeString(buf *bytes.Buffer, n uint64) error {
+	b, err := d.readN(n)
+	if err != nil {
+		return err
+	}
+
+	writeString(buf, string(b))
+
+	return nil
+}
+
+func (d *msgpackDecoder) decodeArray(buf *bytes.Buffer, n uint64) error {
+	buf.WriteByte('[')
+
+	for i := uint64(0); i < n; i++ {
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+
+		if err := d.decode(buf); err != nil {
+			return err
+		}
+	}
+
+	buf.WriteByte(']')
+
+	return nil
+}
+
+// decodeMap converts the map. Non-string keys are converted to their JSON representation.
+func (d *msgpackDecoder) decodeMap(buf *bytes.Buffer, n uint64) error {
+	buf.WriteByte('{')
+
+	for i := uint64(0); i < n; i++ {
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+
+		var key bytes.Buffer
+
+		if err := d.decode(&key); err != nil {
+			return err
+		}
+
+		if key.Len() > 0 && key.Bytes()[0] == '"' {
+			buf.Write(key.Bytes())
+		} else {
+			writeString(buf, key.String())
+		}
+
+		buf.WriteByte(':')
+
+		if err := d.decode(buf); err != nil {
+			return err
+		}
+	}
+
+	buf.WriteByte('}')
+
+	return nil
+}
+
+// decodeExt converts the timestamp extension to RFC3339 string.
+// Other extension types are not supported.
+func (d *msgpackDecoder) decodeExt(buf *bytes.Buffer, n uint64) error {
+	tp, err := d.r.ReadByte()
+	if err != nil {
+		return err
+	}
+
+	b, err := d.readN(n)
+	if err != nil {
+		return err
+	}
+
+	if int8(tp) != msgpackTimestamp {
+		return fmt.Errorf("%w: extension %d", ErrMsgPackType, int8(tp))
+	}
+
+	var t time.Time
+
+	switch n {
+	case 4:
+		t = time.Unix(int64(binary.BigEndian.Uint32(b)), 0)
+	case 8:
+		v := binary.BigEndian.Uint64(b)
+		t = time.Unix(int64(v&0x3ffffffff), int64(v>>34))
+	case 12:
+		t = time.Unix(int64(binary.BigEndian.Uint64(b[4:])), int64(binary.BigEndian.Uint32(b)))
+	default:
+		return fmt.Errorf("%w: timestamp of size %d", ErrMsgPackType, n)
+	}
+
+	writeString(buf, t.UTC().Format(time.RFC3339Nano))
+
+	return nil
+}
+
+// next returns the next document of the stream.
+// Top level arrays are flattened, so the elements are returned as separate documents.
+// Returns io.EOF at the end of the stream.
+func (d *msgpackDecoder) next(pending *uint64) (json.RawMessage, error) {
+	for *pending == 0 {
+		c, err := d.r.Peek(1)
+		if err != nil {
+			return nil, err
+		}
+
+		switch {
+		case c[0] >= 0x90 && c[0] <= 0x9f:
+			_, _ = d.r.ReadByte()
+			*pending = uint64(c[0] & 0x0f)
+		case c[0] == 0xdc || c[0] == 0xdd:
+			_, _ = d.r.ReadByte()
+
+			if *pending, err = d.readUint(2 << (c[0] - 0xdc)); err != nil {
+				return nil, err
+			}
+		default:
+			return d.nextDoc()
+		}
+	}
+
+	*pending--
+
+	return d.nextDoc()
+}
+
+func (d *msgpackDecoder) nextDoc() (json.RawMessage, error) {
+	var buf bytes.Buffer
+
+	if err := d.decode(&buf); err != nil {
+		if errors.Is(err, io.EOF) {
+			err = io.ErrUnexpectedEOF
+		}
+
+		return nil, err
+	}
+
+	if buf.Bytes()[0] != '{' {
+		return nil, ErrMsgPackDocument
+	}
+
+	return buf.Bytes(), nil
+}
+
+// iterateMsgPack iterates the stream of msgpack documents.
+func iterateMsgPack(ctx context.Context, args []string, r io.Reader, fn func(ctx2 context.Context, args []string,
+	docs []json.RawMessage) error,
+) error {
+	d := &msgpackDecoder{r: bufio.NewReaderSize(r, InputBufferSize)}
+
+	var pending uint64
+
+	return iterateDocs(ctx, args, func() (json.RawMessage, error) {
+		doc, err := d.next(&pending)
+		if err != nil && !errors.Is(err, io.EOF) {
+			return nil, util.Error(err, "reading msgpack documents")
+		}
+
+		return doc, err
+	}, fn)
+}
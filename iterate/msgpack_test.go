@@ -0,0 +1,86 @@
+// Copyright 2022-2023 Tigris Data, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package iterate
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMsgPackInput(t *testing.T) {
+	Format = FormatMsgPack
+
+	defer func() { Format = "" }()
+
+	var in bytes.Buffer
+
+	// {"id":1,"neg":-3,"big":-200,"s":"x","bin":<1,2,3>,"f":1.0,"n":nil,"arr":[true],"t":<timestamp 1>}
+	in.Write([]byte{0x89})
+	in.Write([]byte{0xa2, 'i', 'd', 0x01})
+	in.Write([]byte{0xa3, 'n', 'e', 'g', 0xfd})
+	in.Write([]byte{0xa3, 'b', 'i', 'g', 0xd1, 0xff, 0x38})
+	in.Write([]byte{0xa1, 's', 0xa1, 'x'})
+	in.Write([]byte{0xa3, 'b', 'i', 'n', 0xc4, 0x03, 0x01, 0x02, 0x03})
+	in.Write([]byte{0xa1, 'f', 0xcb, 0x3f, 0xf0, 0, 0, 0, 0, 0, 0})
+	in.Write([]byte{0xa1, 'n', 0xc0})
+	in.Write([]byte{0xa3, 'a', 'r', 'r', 0x91, 0xc3})
+	in.Write([]byte{0xa1, 't', 0xd6, 0xff, 0, 0, 0, 0x01})
+
+	// top level array is flattened: [{"id":2},{"id":3}]
+	in.Write([]byte{0x92, 0x81, 0xa2, 'i', 'd', 0x02, 0x81, 0xa2, 'i', 'd', 0x03})
+
+	var docs []json.RawMessage
+
+	err := readerInput(context.Background(), nil, &in,
+		func(ctx context.Context, args []string, batch []json.RawMessage) error {
+			docs = append(docs, batch...)
+
+			return nil
+		})
+	require.NoError(t, err)
+
+	require.Len(t, docs, 3)
+	assert.Equal(t, `{"id":1,"neg":-3,"big":-200,"s":"x","bin":"AQID","f":1.0,"n":null,"arr":[true],`+
+		`"t":"1970-01-01T00:00:01Z"}`, string(docs[0]))
+	assert.Equal(t, `{"id":2}`, string(docs[1]))
+	assert.Equal(t, `{"id":3}`, string(docs[2]))
+}
+
+func TestMsgPackInputErrors(t *testing.T) {
+	Format = FormatMsgPack
+
+	defer func() { Format = "" }()
+
+	process := func(ctx context.Context, args []string, batch []json.RawMessage) error {
+		return nil
+	}
+
+	// not a map
+	err := readerInput(context.Background(), nil, bytes.NewReader([]byte{0x01}), process)
+	require.ErrorIs(t, err, ErrMsgPackDocument)
+
+	// truncated map
+	err = readerInput(context.Background(), nil, bytes.NewReader([]byte{0x81, 0xa2, 'i'}), process)
+	require.Error(t, err)
+
+	// unsupported extension
+	err = readerInput(context.Background(), nil, bytes.NewReader([]byte{0x81, 0xa1, 'e', 0xd4, 0x01, 0x00}), process)
+	require.ErrorIs(t, err, ErrMsgPackType)
+}
@@ -0,0 +1,148 @@
+// Copyright 2022-2023 Tigris Data, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package iterate
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/tigrisdata/tigris-cli/util"
+	"golang.org/x/text/unicode/norm"
+)
+
+var (
+	// NormalizeUnicode is the Unicode normalization form, NFC or NFD,
+	// the string values of the documents are converted to. Empty disables normalization.
+	NormalizeUnicode string
+
+	// NormalizeUnicodeKeys normalizes the field names as well as the values.
+	NormalizeUnicodeKeys bool
+
+	ErrInvalidNormalization = fmt.Errorf("invalid --normalize-unicode value. allowed values: NFC, NFD")
+	ErrNormalizeConflict    = fmt.Errorf("normalized field name conflicts with existing field")
+
+	normForm *norm.Form
+)
+
+func parseNormalization() error {
+	normForm = nil
+
+	var f norm.Form
+
+	switch strings.ToUpper(NormalizeUnicode) {
+	case "":
+		return nil
+	case "NFC":
+		f = norm.NFC
+	case "NFD":
+		f = norm.NFD
+	default:
+		return fmt.Errorf("%w: %s", ErrInvalidNormalization, NormalizeUnicode)
+	}
+
+	normForm = &f
+
+	return nil
+}
+
+// normalizeValue converts the strings of the value, and the keys of the objects,
+// when NormalizeUnicodeKeys is set, to the normalization form.
+// Returns the normalized value and whether it has been changed.
+func normalizeValue(path string, v any) (any, bool, error) {
+	switch val := v.(type) {
+	case string:
+		if normForm.IsNormalString(val) {
+			return val, false, nil
+		}
+
+		return normForm.String(val), true, nil
+	case *util.OrderedObject:
+		changed := false
+
+		for i, k := range val.Keys {
+			nv, c, err := normalizeValue(path+k+".", val.Values[k])
+			if err != nil {
+				return nil, false, err
+			}
+
+			val.Values[k] = nv
+			changed = changed || c
+
+			if !NormalizeUnicodeKeys || normForm.IsNormalString(k) {
+				continue
+			}
+
+			nk := normForm.String(k)
+			if _, ok := val.Values[nk]; ok {
+				return nil, false, fmt.Errorf("%w: %s%s", ErrNormalizeConflict, path, nk)
+			}
+
+			val.Keys[i] = nk
+			val.Values[nk] = val.Values[k]
+			delete(val.Values, k)
+
+			changed = true
+		}
+
+		return val, changed, nil
+	case []any:
+		changed := false
+
+		for i, e := range val {
+			nv, c, err := normalizeValue(path, e)
+			if err != nil {
+				return nil, false, err
+			}
+
+			val[i] = nv
+			changed = changed || c
+		}
+
+		return val, changed, nil
+	}
+
+	return v, false, nil
+}
+
+// normalizeDocs converts the strings of the documents to the NormalizeUnicode form.
+// Documents which are already normalized are left intact. Order of the fields is preserved.
+func normalizeDocs(docs []json.RawMessage) error {
+	if normForm == nil {
+		return nil
+	}
+
+	for k, doc := range docs {
+		v, err := util.DecodeOrdered(doc)
+		if err != nil {
+			return err
+		}
+
+		v, changed, err := normalizeValue("", v)
+		if err != nil {
+			return err
+		}
+
+		if !changed {
+			continue
+		}
+
+		if docs[k], err = json.Marshal(v); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,73 @@
+// Copyright 2022-2023 Tigris Data, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package iterate
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNormalizeDocs(t *testing.T) {
+	defer func() {
+		NormalizeUnicode = ""
+		NormalizeUnicodeKeys = false
+		normForm = nil
+	}()
+
+	// "e\u0301" is the decomposed form of "\u00e9"
+	docs := func() []json.RawMessage {
+		return []json.RawMessage{
+			json.RawMessage(`{"id":1,"name":"Jose\u0301","tags":["cafe\u0301"],"cafe\u0301":{"k":"x"}}`),
+			json.RawMessage(`{"id":2, "name":"plain"}`),
+		}
+	}
+
+	NormalizeUnicode = "bad"
+	require.ErrorIs(t, parseNormalization(), ErrInvalidNormalization)
+
+	NormalizeUnicode = ""
+	require.NoError(t, parseNormalization())
+
+	d := docs()
+	require.NoError(t, normalizeDocs(d))
+	assert.Equal(t, docs(), d)
+
+	NormalizeUnicode = "nfc"
+	require.NoError(t, parseNormalization())
+
+	d = docs()
+	require.NoError(t, normalizeDocs(d))
+	assert.Equal(t, "{\"id\":1,\"name\":\"Jos\u00e9\",\"tags\":[\"caf\u00e9\"],\"cafe\u0301\":{\"k\":\"x\"}}", string(d[0]))
+	assert.Equal(t, `{"id":2, "name":"plain"}`, string(d[1]), "normalized documents are left intact")
+
+	NormalizeUnicodeKeys = true
+
+	d = docs()
+	require.NoError(t, normalizeDocs(d))
+	assert.Equal(t, "{\"id\":1,\"name\":\"Jos\u00e9\",\"tags\":[\"caf\u00e9\"],\"caf\u00e9\":{\"k\":\"x\"}}", string(d[0]))
+
+	NormalizeUnicode = "NFD"
+	require.NoError(t, parseNormalization())
+
+	d = []json.RawMessage{json.RawMessage(`{"caf\u00e9":"\u00e9"}`)}
+	require.NoError(t, normalizeDocs(d))
+	assert.Equal(t, "{\"cafe\u0301\":\"e\u0301\"}", string(d[0]))
+
+	err := normalizeDocs([]json.RawMessage{json.RawMessage(`{"\u00e9":1,"e\u0301":2}`)})
+	require.ErrorIs(t, err, ErrNormalizeConflict)
+}
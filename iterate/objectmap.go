@@ -0,0 +1,102 @@
+// Copyright 2022-2023 Tigris Data, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package iterate
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+
+	"github.com/tigrisdata/tigris-cli/util"
+)
+
+// ObjectMapKey enables reading of the top-level object, which maps the keys to the documents,
+// like {"id1": {...}, "id2": {...}}. Every value becomes a document,
+// and the key is set to the field with this name.
+var ObjectMapKey string
+
+var (
+	ErrObjectMap      = fmt.Errorf("expected top-level object, mapping the keys to the documents")
+	ErrObjectMapValue = fmt.Errorf("value of the object map should be a document")
+)
+
+// setMapKey sets the key of the object map to the field of the document.
+// The key overwrites existing field with the same name.
+func setMapKey(doc json.RawMessage, key string) (json.RawMessage, error) {
+	var m map[string]any
+
+	dec := json.NewDecoder(bytes.NewReader(doc))
+	dec.UseNumber()
+
+	if err := dec.Decode(&m); err != nil || m == nil {
+		return nil, fmt.Errorf("%w: %s", ErrObjectMapValue, key)
+	}
+
+	m[ObjectMapKey] = key
+
+	b, err := json.Marshal(m)
+	if err != nil {
+		return nil, err
+	}
+
+	return util.KeepOrder(doc, b)
+}
+
+// iterateObjectMap streams the entries of the top-level object,
+// so only the current batch is kept in memory, regardless of the size of the object.
+func iterateObjectMap(ctx context.Context, args []string, r io.Reader, fn func(ctx2 context.Context, args []string,
+	docs []json.RawMessage) error,
+) error {
+	dec := json.NewDecoder(r)
+
+	t, err := dec.Token()
+	if err != nil {
+		return err
+	}
+
+	if d, ok := t.(json.Delim); !ok || d != '{' {
+		return ErrObjectMap
+	}
+
+	err = iterateDocs(ctx, args, func() (json.RawMessage, error) {
+		if !dec.More() {
+			return nil, io.EOF
+		}
+
+		t, err := dec.Token()
+		if err != nil {
+			return nil, err
+		}
+
+		key, _ := t.(string)
+
+		var v json.RawMessage
+
+		if err = dec.Decode(&v); err != nil {
+			return nil, err
+		}
+
+		return setMapKey(v, key)
+	}, fn)
+	if err != nil {
+		return err
+	}
+
+	_, err = dec.Token() // closing brace
+
+	return err
+}
@@ -0,0 +1,72 @@
+// Copyright 2022-2023 Tigris Data, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package iterate
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tigrisdata/tigris-cli/util"
+)
+
+func TestObjectMapInput(t *testing.T) {
+	ObjectMapKey = "id"
+	BatchSize = 2
+	util.PreserveOrder = true
+
+	defer func() {
+		ObjectMapKey = ""
+		BatchSize = 100
+		util.PreserveOrder = false
+	}()
+
+	read := func(in string) ([]json.RawMessage, error) {
+		var docs []json.RawMessage
+
+		err := readerInput(context.Background(), nil, strings.NewReader(in),
+			func(ctx context.Context, args []string, d []json.RawMessage) error {
+				require.LessOrEqual(t, len(d), int(BatchSize))
+
+				docs = append(docs, d...)
+
+				return nil
+			})
+
+		return docs, err
+	}
+
+	docs, err := read(`{"u1": {"name": "alice"}, "u2": {"name": "bob", "id": 2}, "u3": {}}`)
+	require.NoError(t, err)
+
+	assert.Equal(t, []json.RawMessage{
+		json.RawMessage(`{"name":"alice","id":"u1"}`),
+		json.RawMessage(`{"name":"bob","id":"u2"}`),
+		json.RawMessage(`{"id":"u3"}`),
+	}, docs)
+
+	docs, err = read(`{}`)
+	require.NoError(t, err)
+	assert.Empty(t, docs)
+
+	_, err = read(`[{"name": "alice"}]`)
+	require.ErrorIs(t, err, ErrObjectMap)
+
+	_, err = read(`{"u1": 1}`)
+	require.ErrorIs(t, err, ErrObjectMapValue)
+}
@@ -0,0 +1,172 @@
+// Copyright 2022-2023 Tigris Data, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package iterate
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/tigrisdata/tigris-cli/util"
+)
+
+// OnlyFields is the list of the fields of the source documents to import, the other fields are discarded.
+// Nested fields are specified using dot notation. The fields added by the CLI, like --default,
+// --add-field, --created-at-field, --updated-at-field and --add-row-number, are kept.
+var OnlyFields []string
+
+var ErrInvalidOnlyField = fmt.Errorf("invalid field name")
+
+// projection is the tree of the selected fields.
+// Nil value selects the field with all its subfields.
+var projection map[string]any
+
+// addedFields returns the fields added to the documents by the CLI.
+func addedFields() []string {
+	fields := TimestampFields()
+
+	for _, d := range defaultValues {
+		fields = append(fields, strings.Join(d.path, "."))
+	}
+
+	for _, d := range derivedFields {
+		fields = append(fields, d.name)
+	}
+
+	if RowNumberField != "" {
+		fields = append(fields, RowNumberField)
+	}
+
+	return fields
+}
+
+func addProjectionPath(p map[string]any, path []string) {
+	for _, k := range path[:len(path)-1] {
+		v, ok := p[k]
+		if ok && v == nil {
+			return // parent is selected with all its subfields
+		}
+
+		m, _ := v.(map[string]any)
+		if m == nil {
+			m = make(map[string]any)
+			p[k] = m
+		}
+
+		p = m
+	}
+
+	p[path[len(path)-1]] = nil
+}
+
+func parseOnlyFields() error {
+	projection = nil
+
+	if len(OnlyFields) == 0 {
+		return nil
+	}
+
+	for _, f := range OnlyFields {
+		if f == "" || strings.HasPrefix(f, ".") || strings.HasSuffix(f, ".") || strings.Contains(f, "..") {
+			return fmt.Errorf("%w: %q", ErrInvalidOnlyField, f)
+		}
+	}
+
+	projection = make(map[string]any)
+
+	for _, f := range append(append([]string{}, OnlyFields...), addedFields()...) {
+		addProjectionPath(projection, strings.Split(f, "."))
+	}
+
+	return nil
+}
+
+// projectValue returns the selected subfields of the object or of the objects of the array.
+// Returns false if nothing is selected.
+func projectValue(v any, p map[string]any) (any, bool) {
+	switch t := v.(type) {
+	case map[string]any:
+		res := project(t, p)
+
+		return res, len(res) > 0
+	case []any:
+		res := make([]any, 0, len(t))
+
+		for _, e := range t {
+			if pe, ok := projectValue(e, p); ok {
+				res = append(res, pe)
+			}
+		}
+
+		return res, len(res) > 0
+	}
+
+	return nil, false
+}
+
+// project returns the selected fields of the document. Absent fields are omitted.
+func project(doc map[string]any, p map[string]any) map[string]any {
+	res := make(map[string]any, len(p))
+
+	for k, sub := range p {
+		v, ok := doc[k]
+		if !ok {
+			continue
+		}
+
+		if sub == nil {
+			res[k] = v
+			continue
+		}
+
+		if pv, ok := projectValue(v, sub.(map[string]any)); ok {
+			res[k] = pv
+		}
+	}
+
+	return res
+}
+
+// projectDocs discards the fields of the documents, which are not selected by OnlyFields.
+func projectDocs(docs []json.RawMessage) error {
+	if projection == nil {
+		return nil
+	}
+
+	for k, doc := range docs {
+		var m map[string]any
+
+		dec := json.NewDecoder(bytes.NewReader(doc))
+		dec.UseNumber()
+
+		if err := dec.Decode(&m); err != nil {
+			return err
+		}
+
+		b, err := json.Marshal(project(m, projection))
+		if err != nil {
+			return err
+		}
+
+		if b, err = util.KeepOrder(doc, b); err != nil {
+			return err
+		}
+
+		docs[k] = b
+	}
+
+	return nil
+}
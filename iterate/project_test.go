@@ -0,0 +1,53 @@
+// Copyright 2022-2023 Tigris Data, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package iterate
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestProjectDocs(t *testing.T) {
+	defer func() {
+		OnlyFields = nil
+		RowNumberField = ""
+		projection = nil
+	}()
+
+	require.NoError(t, parseOnlyFields())
+	assert.Nil(t, projection)
+
+	OnlyFields = []string{"a..b"}
+	require.ErrorIs(t, parseOnlyFields(), ErrInvalidOnlyField)
+
+	OnlyFields = []string{"id", "addr.city", "items.sku", "meta", "meta.x", "missing"}
+	RowNumberField = "row"
+
+	require.NoError(t, parseOnlyFields())
+
+	docs := []json.RawMessage{
+		[]byte(`{"id":1,"name":"a","row":1,"addr":{"city":"x","zip":1},"items":[{"sku":"s1","qty":1},{"qty":2},3],` +
+			`"meta":{"x":1,"y":2}}`),
+		[]byte(`{"id":2,"addr":"unknown","items":[],"row":2}`),
+	}
+
+	require.NoError(t, projectDocs(docs))
+	assert.JSONEq(t, `{"id":1,"row":1,"addr":{"city":"x"},"items":[{"sku":"s1"}],"meta":{"x":1,"y":2}}`,
+		string(docs[0]))
+	assert.JSONEq(t, `{"id":2,"row":2}`, string(docs[1]))
+}
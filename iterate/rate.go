@@ -0,0 +1,109 @@
+// Copyright 2022-2023 Tigris Data, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package iterate
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"time"
+
+	"github.com/rs/zerolog/log"
+	api "github.com/tigrisdata/tigris-client-go/api/server/v1"
+	"github.com/tigrisdata/tigris-client-go/driver"
+)
+
+var (
+	// AdaptiveRate enables slowing down the batch dispatch when the server throttles requests.
+	AdaptiveRate bool
+
+	// Delay between batches is doubled on every throttled request, starting from minThrottleDelay,
+	// and reduced by delayStep on every successful request.
+	// This is AIMD in terms of the request rate.
+	minThrottleDelay = 100 * time.Millisecond
+	maxThrottleDelay = 30 * time.Second
+	delayStep        = 50 * time.Millisecond
+
+	// maxThrottleRetries is the number of consecutive throttled attempts
+	// after which the batch is failed.
+	maxThrottleRetries = 20
+)
+
+func isThrottleError(err error) bool {
+	var ep *driver.Error
+	if !errors.As(err, &ep) {
+		return false
+	}
+
+	return ep.Code == api.Code_RESOURCE_EXHAUSTED || ep.Code == api.Code_UNAVAILABLE
+}
+
+func sleepCtx(ctx context.Context, d time.Duration) error {
+	if d == 0 {
+		return nil
+	}
+
+	t := time.NewTimer(d)
+	defer t.Stop()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-t.C:
+		return nil
+	}
+}
+
+// dispatch passes the batch to process, limiting every attempt by BatchTimeout.
+// When AdaptiveRate is enabled, throttled batch is retried after the delay,
+// which grows while server keeps throttling and shrinks back when it clears.
+func dispatch(ctx context.Context, args []string, docs []json.RawMessage,
+	process func(ctx2 context.Context, args []string, docs []json.RawMessage) error,
+) error {
+	if !AdaptiveRate {
+		return processWithTimeout(ctx, args, docs, process)
+	}
+
+	r := RunFrom(ctx)
+
+	for i := 0; ; i++ {
+		if err := sleepCtx(ctx, r.throttleDelay); err != nil {
+			return err
+		}
+
+		err := processWithTimeout(ctx, args, docs, process)
+		if err == nil || !isThrottleError(err) || i >= maxThrottleRetries {
+			if err == nil && r.throttleDelay > 0 {
+				r.throttleDelay -= delayStep
+				if r.throttleDelay < 0 {
+					r.throttleDelay = 0
+				}
+
+				log.Debug().Dur("delay", r.throttleDelay).Msg("speeding up")
+			}
+
+			return err
+		}
+
+		r.throttleDelay *= 2
+		if r.throttleDelay < minThrottleDelay {
+			r.throttleDelay = minThrottleDelay
+		} else if r.throttleDelay > maxThrottleDelay {
+			r.throttleDelay = maxThrottleDelay
+		}
+
+		log.Debug().Err(err).Dur("delay", r.throttleDelay).Msg("server throttled the request, slowing down")
+	}
+}
@@ -0,0 +1,81 @@
+// Copyright 2022-2023 Tigris Data, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package iterate
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	api "github.com/tigrisdata/tigris-client-go/api/server/v1"
+	"github.com/tigrisdata/tigris-client-go/driver"
+)
+
+func TestAdaptiveRate(t *testing.T) {
+	AdaptiveRate = true
+	minThrottleDelay = time.Millisecond
+	delayStep = time.Millisecond
+
+	defer func() {
+		AdaptiveRate = false
+		minThrottleDelay = 100 * time.Millisecond
+		delayStep = 50 * time.Millisecond
+		defaultRun.throttleDelay = 0
+	}()
+
+	throttled := 3
+	calls := 0
+
+	process := func(ctx context.Context, args []string, docs []json.RawMessage) error {
+		calls++
+
+		if throttled > 0 {
+			throttled--
+			return driver.NewError(api.Code_RESOURCE_EXHAUSTED, "rate limit exceeded")
+		}
+
+		return nil
+	}
+
+	docs := []json.RawMessage{json.RawMessage(`{"id":1}`)}
+
+	err := varyBatch(context.Background(), nil, docs, process)
+	require.NoError(t, err)
+
+	assert.Equal(t, 4, calls)
+	// 1ms -> 2ms -> 4ms on throttling, then reduced by 1ms on success
+	assert.Equal(t, 3*time.Millisecond, defaultRun.throttleDelay)
+
+	for i := 0; i < 5; i++ {
+		require.NoError(t, varyBatch(context.Background(), nil, docs, process))
+	}
+
+	assert.Equal(t, time.Duration(0), defaultRun.throttleDelay)
+
+	// non throttling errors are not retried
+	calls = 0
+
+	err = varyBatch(context.Background(), nil, docs, func(ctx context.Context, args []string,
+		docs []json.RawMessage,
+	) error {
+		calls++
+		return errTestBadDoc
+	})
+	require.ErrorIs(t, err, errTestBadDoc)
+	assert.Equal(t, 1, calls)
+}
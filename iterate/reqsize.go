@@ -0,0 +1,49 @@
+// Copyright 2022-2023 Tigris Data, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package iterate
+
+import (
+	"encoding/json"
+
+	"github.com/rs/zerolog/log"
+)
+
+// MaxRequestBytes limits the total size of the documents sent in a single request.
+// Batches exceeding the limit are split before sending. Zero means no limit.
+var MaxRequestBytes int64
+
+// limitRequestSize returns the end of the docs[first:last] range,
+// reduced so that the total size of the documents doesn't exceed MaxRequestBytes.
+// At least one document is always included, even if it exceeds the limit alone.
+func limitRequestSize(docs []json.RawMessage, first int, last int) int {
+	if MaxRequestBytes <= 0 {
+		return last
+	}
+
+	var size int64
+
+	for i := first; i < last; i++ {
+		size += int64(len(docs[i]))
+
+		if size > MaxRequestBytes && i > first {
+			log.Debug().Msgf("splitting request at max request size. first=%d, last=%d, limit=%d", first, i,
+				MaxRequestBytes)
+
+			return i
+		}
+	}
+
+	return last
+}
@@ -0,0 +1,53 @@
+// Copyright 2022-2023 Tigris Data, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package iterate
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMaxRequestBytes(t *testing.T) {
+	defer func() { MaxRequestBytes = 0 }()
+
+	docs := []json.RawMessage{
+		json.RawMessage(`{"id":1}`), // 8 bytes
+		json.RawMessage(`{"id":2}`),
+		json.RawMessage(`{"id":3,"large":"xxxxxxxxxxxxxxxxxxxx"}`), // exceeds the limit alone
+		json.RawMessage(`{"id":4}`),
+		json.RawMessage(`{"id":5}`),
+		json.RawMessage(`{"id":6}`),
+	}
+
+	var requests [][]json.RawMessage
+
+	process := func(ctx context.Context, args []string, docs []json.RawMessage) error {
+		requests = append(requests, docs)
+		return nil
+	}
+
+	require.NoError(t, varyBatch(context.Background(), nil, docs, process))
+	assert.Equal(t, [][]json.RawMessage{docs}, requests)
+
+	MaxRequestBytes = 16
+	requests = nil
+
+	require.NoError(t, varyBatch(context.Background(), nil, docs, process))
+	assert.Equal(t, [][]json.RawMessage{docs[0:2], docs[2:3], docs[3:5], docs[5:6]}, requests)
+}
@@ -0,0 +1,61 @@
+// Copyright 2022-2023 Tigris Data, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package iterate
+
+import (
+	"bytes"
+	"encoding/json"
+
+	"github.com/tigrisdata/tigris-cli/util"
+)
+
+// RowNumberField is the field the number of the document in the input is set to.
+// Documents are numbered from 1 across all the sources of the input,
+// the header of the CSV input is not counted.
+var RowNumberField string
+
+// addRowNumbers sets the RowNumberField of the documents.
+// The first document of the batch is the document number first of the input.
+func addRowNumbers(docs []json.RawMessage, first int64) error {
+	if RowNumberField == "" {
+		return nil
+	}
+
+	for k, doc := range docs {
+		var m map[string]any
+
+		dec := json.NewDecoder(bytes.NewReader(doc))
+		dec.UseNumber()
+
+		if err := dec.Decode(&m); err != nil {
+			return err
+		}
+
+		m[RowNumberField] = first + int64(k)
+
+		b, err := json.Marshal(m)
+		if err != nil {
+			return err
+		}
+
+		if b, err = util.KeepOrder(doc, b); err != nil {
+			return err
+		}
+
+		docs[k] = b
+	}
+
+	return nil
+}
@@ -0,0 +1,44 @@
+// Copyright 2022-2023 Tigris Data, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package iterate
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tigrisdata/tigris-cli/util"
+)
+
+func TestAddRowNumbers(t *testing.T) {
+	RowNumberField = "row"
+	util.PreserveOrder = true
+
+	defer func() {
+		RowNumberField = ""
+		util.PreserveOrder = false
+	}()
+
+	docs := []json.RawMessage{
+		json.RawMessage(`{"b":1,"a":"x"}`),
+		json.RawMessage(`{"row":"old"}`),
+	}
+
+	require.NoError(t, addRowNumbers(docs, 11))
+
+	assert.Equal(t, `{"b":1,"a":"x","row":11}`, string(docs[0]))
+	assert.Equal(t, `{"row":12}`, string(docs[1]))
+}
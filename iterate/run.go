@@ -0,0 +1,89 @@
+// Copyright 2022-2023 Tigris Data, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package iterate
+
+import (
+	"context"
+	"time"
+)
+
+// Run is the state of a single iteration of the input: the counters of the documents,
+// the timings of the batches, the delay of the adaptive rate and the options of the CSV input.
+// The iterations running concurrently, like the collections of the import manifest,
+// get their own Run by WithRun. The other iterations use the default Run,
+// reported by the package level functions, like Seen and Skipped.
+type Run struct {
+	// Skipped is the number of the documents failed to be processed.
+	Skipped int64
+
+	// CSV are the options of the CSV input.
+	CSV CSVOptions
+
+	// seen is the number of documents passed for processing.
+	seen int64
+
+	batchStats []BatchStat
+
+	// oversized is the numbers of the documents of the input, exceeding MaxDocSize.
+	oversized []int64
+
+	// errorClasses counts the documents failed to be processed by the class of the error.
+	errorClasses map[string]int64
+
+	throttleDelay time.Duration
+}
+
+var defaultRun = &Run{}
+
+type runKey struct{}
+
+// NewRun returns the state of the iteration, independent of the other iterations.
+func NewRun() *Run {
+	return &Run{}
+}
+
+// WithRun returns the context, the iterations started with which use the run.
+func WithRun(ctx context.Context, r *Run) context.Context {
+	return context.WithValue(ctx, runKey{}, r)
+}
+
+// DefaultRun returns the run of the iterations, which are not given their own run by WithRun.
+func DefaultRun() *Run {
+	return defaultRun
+}
+
+// RunFrom returns the run of the context, set by WithRun, or the default run.
+func RunFrom(ctx context.Context) *Run {
+	if r, ok := ctx.Value(runKey{}).(*Run); ok {
+		return r
+	}
+
+	return defaultRun
+}
+
+// Seen returns the number of documents passed for processing by the last iteration of the run.
+func (r *Run) Seen() int64 {
+	return r.seen
+}
+
+// Seen returns the number of documents passed for processing by the last iteration.
+func Seen() int64 {
+	return defaultRun.seen
+}
+
+// Skipped returns the number of the documents failed to be processed.
+func Skipped() int64 {
+	return defaultRun.Skipped
+}
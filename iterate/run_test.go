@@ -0,0 +1,95 @@
+// Copyright 2022-2023 Tigris Data, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package iterate
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestConcurrentRuns(t *testing.T) {
+	SkipErrors = true
+	Format = FormatCSV
+
+	defer func() {
+		SkipErrors = false
+		Format = ""
+	}()
+
+	dir := t.TempDir()
+
+	cases := []struct {
+		delimiter string
+		in        string
+		docs      int
+		failed    int64
+	}{
+		{",", "id,bad\n1,false\n2,true\n3,false\n", 2, 1},
+		{"||", "id||bad\n1||true\n2||true\n3||false\n4||false\n", 2, 2},
+		{";", "id;bad\n1;false\n", 1, 0},
+	}
+
+	runs := make([]*Run, len(cases))
+	docs := make([][]json.RawMessage, len(cases))
+
+	var wg sync.WaitGroup
+
+	for k, c := range cases {
+		name := filepath.Join(dir, c.delimiter+".csv")
+		require.NoError(t, os.WriteFile(name, []byte(c.in), 0o600))
+
+		runs[k] = NewRun()
+		require.NoError(t, runs[k].CSV.Configure(c.delimiter, "", true, false))
+
+		wg.Add(1)
+
+		go func(k int) {
+			defer wg.Done()
+
+			err := SourceInput(WithRun(context.Background(), runs[k]), nil, name,
+				func(ctx context.Context, args []string, batch []json.RawMessage) error {
+					for _, d := range batch {
+						if bytes.Contains(d, []byte(`"bad":true`)) {
+							return errTestBadDoc
+						}
+					}
+
+					docs[k] = append(docs[k], batch...)
+
+					return nil
+				})
+			assert.NoError(t, err)
+		}(k)
+	}
+
+	wg.Wait()
+
+	for k, c := range cases {
+		assert.Len(t, docs[k], c.docs, c.delimiter)
+		assert.Equal(t, c.failed, runs[k].Skipped, c.delimiter)
+		assert.Equal(t, int64(c.docs)+c.failed, runs[k].Seen(), c.delimiter)
+	}
+
+	assert.Equal(t, int64(0), Skipped())
+	assert.Equal(t, 0, inputs)
+}
@@ -0,0 +1,173 @@
+// Copyright 2022-2023 Tigris Data, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package iterate
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/tigrisdata/tigris-cli/util"
+)
+
+const sanitizeSubstitute = '_'
+
+var (
+	// SanitizeKeys enables replacing the characters which are not allowed in the field names.
+	SanitizeKeys bool
+
+	ErrSanitizeConflict = fmt.Errorf("sanitized field name conflicts with existing field")
+
+	// sanitizedKeys maps original field paths to the sanitized field names.
+	sanitizedKeys map[string]string
+)
+
+func isKeyChar(c rune, first bool) bool {
+	return c == '_' || c == '$' || (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z') ||
+		(!first && c >= '0' && c <= '9')
+}
+
+// sanitizeKey replaces characters, which are not letters, digits, '_' or '$', with '_'.
+// Names starting with a digit are prefixed with '_'.
+func sanitizeKey(key string) string {
+	var sb strings.Builder
+
+	for i, c := range key {
+		switch {
+		case isKeyChar(c, i == 0):
+			sb.WriteRune(c)
+		case i == 0 && c >= '0' && c <= '9':
+			sb.WriteRune(sanitizeSubstitute)
+			sb.WriteRune(c)
+		default:
+			sb.WriteRune(sanitizeSubstitute)
+		}
+	}
+
+	if sb.Len() == 0 {
+		return string(sanitizeSubstitute)
+	}
+
+	return sb.String()
+}
+
+// sanitizeValue sanitizes the keys of the objects in the value.
+// prefix and newPrefix are the original and sanitized paths of the value.
+func sanitizeValue(prefix string, newPrefix string, v any) (bool, error) {
+	switch val := v.(type) {
+	case *util.OrderedObject:
+		changed := false
+
+		for i, k := range val.Keys {
+			path := prefix + k
+			sk := sanitizeKey(k)
+
+			c, err := sanitizeValue(path+".", newPrefix+sk+".", val.Values[k])
+			if err != nil {
+				return false, err
+			}
+
+			changed = changed || c
+
+			if sk == k {
+				continue
+			}
+
+			if _, ok := val.Values[sk]; ok {
+				return false, fmt.Errorf("%w: %s -> %s", ErrSanitizeConflict, path, sk)
+			}
+
+			val.Keys[i] = sk
+			val.Values[sk] = val.Values[k]
+			delete(val.Values, k)
+
+			sanitizedKeys[path] = newPrefix + sk
+			changed = true
+		}
+
+		return changed, nil
+	case []any:
+		changed := false
+
+		for _, e := range val {
+			c, err := sanitizeValue(prefix, newPrefix, e)
+			if err != nil {
+				return false, err
+			}
+
+			changed = changed || c
+		}
+
+		return changed, nil
+	}
+
+	return false, nil
+}
+
+// sanitizeKeys replaces invalid characters in the field names of the documents.
+// Order of the fields is preserved.
+func sanitizeKeys(docs []json.RawMessage) error {
+	if !SanitizeKeys {
+		return nil
+	}
+
+	if sanitizedKeys == nil {
+		sanitizedKeys = make(map[string]string)
+	}
+
+	for k, doc := range docs {
+		v, err := util.DecodeOrdered(doc)
+		if err != nil {
+			return err
+		}
+
+		changed, err := sanitizeValue("", "", v)
+		if err != nil {
+			return err
+		}
+
+		if !changed {
+			continue
+		}
+
+		if docs[k], err = json.Marshal(v); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func reportSanitizedKeys() {
+	if len(sanitizedKeys) == 0 {
+		return
+	}
+
+	keys := make([]string, 0, len(sanitizedKeys))
+	for k := range sanitizedKeys {
+		keys = append(keys, k)
+	}
+
+	sort.Strings(keys)
+
+	util.Stderrf("Renamed fields:\n")
+
+	for _, k := range keys {
+		util.Stderrf("  %q -> %s\n", k, sanitizedKeys[k])
+	}
+
+	sanitizedKeys = nil
+}
@@ -0,0 +1,57 @@
+// Copyright 2022-2023 Tigris Data, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package iterate
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSanitizeKeys(t *testing.T) {
+	SanitizeKeys = true
+
+	defer func() {
+		SanitizeKeys = false
+		sanitizedKeys = nil
+	}()
+
+	assert.Equal(t, "first_name", sanitizeKey("first name"))
+	assert.Equal(t, "_1st", sanitizeKey("1st"))
+	assert.Equal(t, "a_b", sanitizeKey("a.b"))
+	assert.Equal(t, "$ok_1", sanitizeKey("$ok_1"))
+	assert.Equal(t, "_", sanitizeKey(""))
+
+	docs := []json.RawMessage{
+		json.RawMessage(`{"id":1,"first name":"a","addr":{"zip code":"b"},"items":[{"2nd":1}]}`),
+		json.RawMessage(`{"id":2}`),
+	}
+
+	require.NoError(t, sanitizeKeys(docs))
+
+	assert.Equal(t, `{"id":1,"first_name":"a","addr":{"zip_code":"b"},"items":[{"_2nd":1}]}`, string(docs[0]))
+	assert.Equal(t, `{"id":2}`, string(docs[1]))
+
+	assert.Equal(t, map[string]string{
+		"first name":    "first_name",
+		"addr.zip code": "addr.zip_code",
+		"items.2nd":     "items._2nd",
+	}, sanitizedKeys)
+
+	err := sanitizeKeys([]json.RawMessage{json.RawMessage(`{"a b":1,"a_b":2}`)})
+	require.ErrorIs(t, err, ErrSanitizeConflict)
+}
@@ -0,0 +1,147 @@
+// Copyright 2022-2023 Tigris Data, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package iterate
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"strings"
+
+	"github.com/schollz/progressbar/v3"
+	"github.com/tigrisdata/tigris-cli/util"
+)
+
+// sqlColumn is the part of *sql.ColumnType required to convert the value to JSON.
+type sqlColumn interface {
+	Name() string
+	DatabaseTypeName() string
+}
+
+// sqlValue converts the value scanned from the database to the value,
+// which marshals to the closest JSON type.
+// Drivers return some column types as raw bytes, so the type is derived from database type name.
+func sqlValue(col sqlColumn, v any) any {
+	b, ok := v.([]byte)
+	if !ok {
+		return v // nil, numbers, booleans and time.Time are marshalled as is
+	}
+
+	tp := strings.TrimPrefix(strings.ToUpper(col.DatabaseTypeName()), "UNSIGNED ")
+
+	switch tp {
+	case "INT", "INTEGER", "TINYINT", "SMALLINT", "MEDIUMINT", "BIGINT", "INT2", "INT4", "INT8",
+		"FLOAT", "DOUBLE", "REAL", "FLOAT4", "FLOAT8", "NUMERIC", "DECIMAL", "YEAR":
+		return json.Number(b)
+	case "BOOL", "BOOLEAN":
+		return string(b) == "1" || strings.EqualFold(string(b), "true") || string(b) == "t"
+	case "JSON", "JSONB":
+		return json.RawMessage(b)
+	case "BYTEA", "BLOB", "TINYBLOB", "MEDIUMBLOB", "LONGBLOB", "BINARY", "VARBINARY", "BIT":
+		return b // marshalled as base64 string
+	}
+
+	return string(b)
+}
+
+func sqlRowToDoc(cols []sqlColumn, vals []any) (json.RawMessage, error) {
+	// columns are the fields of the document in the order of the query
+	doc := util.NewOrderedObject()
+
+	for i, c := range cols {
+		doc.Set(c.Name(), sqlValue(c, vals[i]))
+	}
+
+	return json.Marshal(doc)
+}
+
+// SQLInput runs the query against SQL database and passes resulting rows to the fn as JSON documents.
+// Column names become the field names of the documents.
+func SQLInput(ctx context.Context, args []string, driverName string, dsn string, query string,
+	fn func(ctx2 context.Context, args []string, docs []json.RawMessage) error,
+) error {
+	done, err := startInput(ctx)
+	if err != nil {
+		return err
+	}
+
+	defer done()
+
+	db, err := sql.Open(driverName, dsn)
+	if err != nil {
+		return util.Error(err, "open sql database")
+	}
+
+	defer func() { _ = db.Close() }()
+
+	rows, err := db.QueryContext(ctx, query)
+	if err != nil {
+		return util.Error(err, "sql query")
+	}
+
+	defer func() { _ = rows.Close() }()
+
+	types, err := rows.ColumnTypes()
+	if err != nil {
+		return util.Error(err, "sql column types")
+	}
+
+	cols := make([]sqlColumn, 0, len(types))
+	for _, t := range types {
+		cols = append(cols, t)
+	}
+
+	var bar *progressbar.ProgressBar
+
+	if showProgress() {
+		bar = util.NewProgressBar(-1)
+	}
+
+	for {
+		docs := make([]json.RawMessage, 0, BatchSize)
+
+		for int32(len(docs)) < BatchSize && rows.Next() {
+			vals := make([]any, len(cols))
+			ptrs := make([]any, len(cols))
+
+			for i := range vals {
+				ptrs[i] = &vals[i]
+			}
+
+			if err = rows.Scan(ptrs...); err != nil {
+				return util.Error(err, "sql scan row")
+			}
+
+			doc, err := sqlRowToDoc(cols, vals)
+			if err != nil {
+				return util.Error(err, "sql row to document")
+			}
+
+			docs = append(docs, doc)
+		}
+
+		if len(docs) == 0 {
+			break
+		} else if err = processBatch(ctx, args, docs, fn); err != nil {
+			return err
+		}
+
+		if showProgress() {
+			_ = bar.Add(len(docs))
+		}
+	}
+
+	return util.Error(rows.Err(), "sql read rows")
+}
@@ -0,0 +1,75 @@
+// Copyright 2022-2023 Tigris Data, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package iterate
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type testColumn struct {
+	name string
+	tp   string
+}
+
+func (c testColumn) Name() string             { return c.name }
+func (c testColumn) DatabaseTypeName() string { return c.tp }
+
+func TestSQLRowToDoc(t *testing.T) {
+	cols := []sqlColumn{
+		testColumn{"id", "BIGINT"},
+		testColumn{"u_id", "UNSIGNED INT"},
+		testColumn{"name", "VARCHAR"},
+		testColumn{"price", "NUMERIC"},
+		testColumn{"active", "BOOL"},
+		testColumn{"attrs", "JSONB"},
+		testColumn{"data", "BYTEA"},
+		testColumn{"created", "TIMESTAMP"},
+		testColumn{"deleted", "TIMESTAMP"},
+		testColumn{"pg_int", "INT4"},
+	}
+
+	vals := []any{
+		[]byte("12345678901234567"),
+		[]byte("5"),
+		[]byte("John"),
+		[]byte("12.50"),
+		[]byte("1"),
+		[]byte(`{"a":1}`),
+		[]byte{1, 2, 3},
+		time.Date(2023, 1, 2, 3, 4, 5, 0, time.UTC),
+		nil,
+		int64(7),
+	}
+
+	doc, err := sqlRowToDoc(cols, vals)
+	require.NoError(t, err)
+
+	assert.JSONEq(t, `{
+		"id": 12345678901234567,
+		"u_id": 5,
+		"name": "John",
+		"price": 12.50,
+		"active": true,
+		"attrs": {"a":1},
+		"data": "AQID",
+		"created": "2023-01-02T03:04:05Z",
+		"deleted": null,
+		"pg_int": 7
+	}`, string(doc))
+}
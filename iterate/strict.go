@@ -0,0 +1,119 @@
+// Copyright 2022-2023 Tigris Data, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package iterate
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"unicode/utf8"
+)
+
+var (
+	// StrictJSON rejects the JSON documents with duplicate keys or invalid UTF-8,
+	// and the data trailing the array of documents, instead of importing them partially.
+	StrictJSON bool
+
+	ErrInvalidUTF8  = fmt.Errorf("invalid UTF-8")
+	ErrTrailingData = fmt.Errorf("trailing data after the array of documents")
+)
+
+// lineCounter counts the new lines read from the underlying reader,
+// to report the position of the invalid document in the strict mode.
+type lineCounter struct {
+	r     io.Reader
+	lines int64
+}
+
+func (l *lineCounter) Read(p []byte) (int, error) {
+	n, err := l.r.Read(p)
+	l.lines += int64(bytes.Count(p[:n], []byte{'\n'}))
+
+	return n, err
+}
+
+// newJSONDecoder returns the decoder of the reader, which tracks the line numbers of the input.
+func newJSONDecoder(r io.Reader) (*json.Decoder, *lineCounter) {
+	lc := &lineCounter{r: r}
+
+	return json.NewDecoder(lc), lc
+}
+
+// errorAt annotates the error of the document just decoded by the decoder
+// with the line and the byte offset of the document in the input.
+func (l *lineCounter) errorAt(dec *json.Decoder, doc json.RawMessage, err error) error {
+	buffered, _ := io.ReadAll(dec.Buffered())
+
+	line := l.lines - int64(bytes.Count(buffered, []byte{'\n'})) - int64(bytes.Count(doc, []byte{'\n'})) + 1
+	offset := dec.InputOffset() - int64(len(doc))
+
+	return fmt.Errorf("%w at line %d, offset %d", err, line, offset)
+}
+
+// checkStrictJSON checks the document for invalid UTF-8 and duplicate keys, when StrictJSON is enabled.
+func checkStrictJSON(doc json.RawMessage) error {
+	if !StrictJSON {
+		return nil
+	}
+
+	if !utf8.Valid(doc) {
+		return ErrInvalidUTF8
+	}
+
+	dups, err := findDuplicateKeys(doc)
+	if err != nil {
+		return err
+	}
+
+	if len(dups) > 0 {
+		return fmt.Errorf("%w: %s", ErrDuplicateKey, strings.Join(dups, ", "))
+	}
+
+	return nil
+}
+
+// decodeStrict decodes the next document and checks it in the strict mode.
+func decodeStrict(dec *json.Decoder, lc *lineCounter) (json.RawMessage, error) {
+	var v json.RawMessage
+
+	if err := dec.Decode(&v); err != nil {
+		return nil, err
+	}
+
+	if err := checkStrictJSON(v); err != nil {
+		return nil, lc.errorAt(dec, v, err)
+	}
+
+	return v, nil
+}
+
+// checkTrailingData checks that only whitespace follows the array of documents, when StrictJSON is enabled.
+// The error reports the position of the end of the array.
+func checkTrailingData(dec *json.Decoder, lc *lineCounter) error {
+	if !StrictJSON {
+		return nil
+	}
+
+	trailing := lc.errorAt(dec, nil, ErrTrailingData)
+
+	if _, err := dec.Token(); !errors.Is(err, io.EOF) {
+		return trailing
+	}
+
+	return nil
+}
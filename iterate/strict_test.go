@@ -0,0 +1,73 @@
+// Copyright 2022-2023 Tigris Data, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package iterate
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStrictJSON(t *testing.T) {
+	defer func() { StrictJSON = false }()
+
+	input := "{\"id\":1}\n{\"id\":2,\n\"name\":\"a\xffb\"}\n{\"id\":3,\"id\":4}\n"
+
+	dec, lc := newJSONDecoder(strings.NewReader(input))
+
+	for dec.More() {
+		_, err := decodeStrict(dec, lc)
+		require.NoError(t, err)
+	}
+
+	StrictJSON = true
+
+	dec, lc = newJSONDecoder(strings.NewReader(input))
+
+	v, err := decodeStrict(dec, lc)
+	require.NoError(t, err)
+	assert.Equal(t, `{"id":1}`, string(v))
+
+	_, err = decodeStrict(dec, lc)
+	require.ErrorIs(t, err, ErrInvalidUTF8)
+	assert.Equal(t, "invalid UTF-8 at line 2, offset 9", err.Error())
+
+	_, err = decodeStrict(dec, lc)
+	require.ErrorIs(t, err, ErrDuplicateKey)
+	assert.Equal(t, "duplicate key: id at line 4, offset 32", err.Error())
+
+	dec, lc = newJSONDecoder(strings.NewReader("[{\"id\":1}]\n \n"))
+	_, err = dec.Token()
+	require.NoError(t, err)
+	_, err = decodeStrict(dec, lc)
+	require.NoError(t, err)
+	_, err = dec.Token()
+	require.NoError(t, err)
+	require.NoError(t, checkTrailingData(dec, lc))
+
+	dec, lc = newJSONDecoder(strings.NewReader("[{\"id\":1}]\n{\"id\":2}"))
+	_, err = dec.Token()
+	require.NoError(t, err)
+	_, err = decodeStrict(dec, lc)
+	require.NoError(t, err)
+	_, err = dec.Token()
+	require.NoError(t, err)
+
+	err = checkTrailingData(dec, lc)
+	require.ErrorIs(t, err, ErrTrailingData)
+	assert.Equal(t, "trailing data after the array of documents at line 1, offset 10", err.Error())
+}
@@ -0,0 +1,109 @@
+// Copyright 2022-2023 Tigris Data, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package iterate
+
+import (
+	"bufio"
+	"compress/gzip"
+	"encoding/json"
+	"io"
+	"os"
+	"strings"
+	"sync"
+
+	"github.com/tigrisdata/tigris-cli/util"
+)
+
+var (
+	// Tee is the path to the file, where the successfully imported documents are written to,
+	// as newline delimited JSON, after all the processing done by the CLI.
+	// The file is gzip compressed when the path ends with .gz.
+	Tee string
+
+	teeMu     sync.Mutex
+	teeFile   *os.File
+	teeGzip   *gzip.Writer
+	teeWriter *bufio.Writer
+)
+
+func openTee() error {
+	f, err := os.OpenFile(Tee, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0o600)
+	if err != nil {
+		return util.Error(err, "open tee file: %s", Tee)
+	}
+
+	teeFile = f
+
+	var w io.Writer = f
+
+	if strings.HasSuffix(Tee, ".gz") {
+		teeGzip = gzip.NewWriter(f)
+		w = teeGzip
+	}
+
+	teeWriter = bufio.NewWriter(w)
+
+	return nil
+}
+
+// TeeDocs writes the documents, successfully imported by the caller, to the tee file.
+func TeeDocs(docs []json.RawMessage) error {
+	if Tee == "" {
+		return nil
+	}
+
+	teeMu.Lock()
+	defer teeMu.Unlock()
+
+	if teeWriter == nil {
+		if err := openTee(); err != nil {
+			return err
+		}
+	}
+
+	for _, doc := range docs {
+		if _, err := teeWriter.Write(doc); err != nil {
+			return util.Error(err, "write tee file: %s", Tee)
+		}
+
+		if err := teeWriter.WriteByte('\n'); err != nil {
+			return util.Error(err, "write tee file: %s", Tee)
+		}
+	}
+
+	return nil
+}
+
+func closeTee() {
+	teeMu.Lock()
+	defer teeMu.Unlock()
+
+	if teeWriter == nil {
+		return
+	}
+
+	err := teeWriter.Flush()
+	util.Fatal(err, "flush tee file: %s", Tee)
+
+	if teeGzip != nil {
+		err = teeGzip.Close()
+		util.Fatal(err, "close tee file: %s", Tee)
+	}
+
+	err = teeFile.Close()
+	util.Fatal(err, "close tee file: %s", Tee)
+
+	teeFile, teeGzip, teeWriter = nil, nil, nil
+}
@@ -0,0 +1,71 @@
+// Copyright 2022-2023 Tigris Data, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package iterate
+
+import (
+	"compress/gzip"
+	"encoding/json"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTeeDocs(t *testing.T) {
+	defer func() { Tee = "" }()
+
+	docs := []json.RawMessage{json.RawMessage(`{"a":1}`), json.RawMessage(`{"b":"x"}`)}
+
+	t.Run("off", func(t *testing.T) {
+		Tee = ""
+
+		require.NoError(t, TeeDocs(docs))
+		assert.Nil(t, teeWriter)
+	})
+
+	t.Run("plain", func(t *testing.T) {
+		Tee = filepath.Join(t.TempDir(), "docs.json")
+
+		require.NoError(t, TeeDocs(docs[:1]))
+		require.NoError(t, TeeDocs(docs[1:]))
+		closeTee()
+
+		b, err := os.ReadFile(Tee)
+		require.NoError(t, err)
+		assert.Equal(t, "{\"a\":1}\n{\"b\":\"x\"}\n", string(b))
+	})
+
+	t.Run("gzip", func(t *testing.T) {
+		Tee = filepath.Join(t.TempDir(), "docs.json.gz")
+
+		require.NoError(t, TeeDocs(docs))
+		closeTee()
+
+		f, err := os.Open(Tee)
+		require.NoError(t, err)
+
+		defer func() { _ = f.Close() }()
+
+		r, err := gzip.NewReader(f)
+		require.NoError(t, err)
+
+		b, err := io.ReadAll(r)
+		require.NoError(t, err)
+		assert.Equal(t, "{\"a\":1}\n{\"b\":\"x\"}\n", string(b))
+	})
+}
@@ -0,0 +1,41 @@
+// Copyright 2022-2023 Tigris Data, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package iterate
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+)
+
+// BatchTimeout is the deadline of a single attempt to process the batch.
+// Every attempt gets a fresh context derived from the context of the iteration,
+// so the deadlines of the batches don't accumulate over the long imports.
+// Zero means no deadline other than the one of the iteration context.
+var BatchTimeout time.Duration
+
+// processWithTimeout passes the batch to process with the context limited by BatchTimeout.
+func processWithTimeout(ctx context.Context, args []string, docs []json.RawMessage,
+	process func(ctx2 context.Context, args []string, docs []json.RawMessage) error,
+) error {
+	if BatchTimeout <= 0 {
+		return process(ctx, args, docs)
+	}
+
+	bctx, cancel := context.WithTimeout(ctx, BatchTimeout)
+	defer cancel()
+
+	return process(bctx, args, docs)
+}
@@ -0,0 +1,61 @@
+// Copyright 2022-2023 Tigris Data, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package iterate
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestProcessWithTimeout(t *testing.T) {
+	defer func() { BatchTimeout = 0 }()
+
+	var deadlines []time.Time
+
+	process := func(ctx context.Context, _ []string, _ []json.RawMessage) error {
+		d, ok := ctx.Deadline()
+		if ok {
+			deadlines = append(deadlines, d)
+		}
+
+		return ctx.Err()
+	}
+
+	require.NoError(t, processWithTimeout(context.Background(), nil, nil, process))
+	assert.Empty(t, deadlines)
+
+	BatchTimeout = time.Hour
+
+	require.NoError(t, processWithTimeout(context.Background(), nil, nil, process))
+	time.Sleep(time.Millisecond)
+	require.NoError(t, processWithTimeout(context.Background(), nil, nil, process))
+
+	require.Len(t, deadlines, 2)
+	assert.True(t, deadlines[1].After(deadlines[0]), "every batch gets its own deadline")
+
+	BatchTimeout = time.Nanosecond
+
+	err := processWithTimeout(context.Background(), nil, nil,
+		func(ctx context.Context, _ []string, _ []json.RawMessage) error {
+			<-ctx.Done()
+			return ctx.Err()
+		})
+	require.ErrorIs(t, err, context.DeadlineExceeded)
+}
@@ -0,0 +1,96 @@
+// Copyright 2022-2023 Tigris Data, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package iterate
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"time"
+
+	"github.com/tigrisdata/tigris-cli/util"
+)
+
+var (
+	// CreatedAtField is the field set to the time of the import, when it's absent or null in the document.
+	CreatedAtField string
+	// UpdatedAtField is the field always set to the time of the import.
+	UpdatedAtField string
+
+	timeNow = time.Now
+)
+
+// TimestampFields returns the fields stamped with the time of the import.
+func TimestampFields() []string {
+	var fields []string
+
+	for _, f := range []string{CreatedAtField, UpdatedAtField} {
+		if f != "" {
+			fields = append(fields, f)
+		}
+	}
+
+	return fields
+}
+
+// setValue sets the value of the field, overwriting the existing value.
+// Missing parent objects of nested fields are created.
+func setValue(m map[string]any, path []string, value any) {
+	if m = fieldParent(m, path); m != nil {
+		m[path[len(path)-1]] = value
+	}
+}
+
+// stampTimestamps sets the created-at and updated-at fields of the documents to the current time.
+// All the documents of the batch get the same time.
+func stampTimestamps(docs []json.RawMessage) error {
+	if CreatedAtField == "" && UpdatedAtField == "" {
+		return nil
+	}
+
+	now := timeNow().UTC().Format(time.RFC3339)
+
+	for k, doc := range docs {
+		var m map[string]any
+
+		dec := json.NewDecoder(bytes.NewReader(doc))
+		dec.UseNumber()
+
+		if err := dec.Decode(&m); err != nil {
+			return err
+		}
+
+		if CreatedAtField != "" {
+			setDefault(m, strings.Split(CreatedAtField, "."), now)
+		}
+
+		if UpdatedAtField != "" {
+			setValue(m, strings.Split(UpdatedAtField, "."), now)
+		}
+
+		b, err := json.Marshal(m)
+		if err != nil {
+			return err
+		}
+
+		if b, err = util.KeepOrder(doc, b); err != nil {
+			return err
+		}
+
+		docs[k] = b
+	}
+
+	return nil
+}
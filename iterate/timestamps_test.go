@@ -0,0 +1,58 @@
+// Copyright 2022-2023 Tigris Data, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package iterate
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStampTimestamps(t *testing.T) {
+	defer func() {
+		CreatedAtField = ""
+		UpdatedAtField = ""
+		timeNow = time.Now
+	}()
+
+	timeNow = func() time.Time { return time.Date(2023, 5, 6, 7, 8, 9, 10, time.FixedZone("", 3600)) }
+
+	docs := []json.RawMessage{[]byte(`{"id":1}`)}
+	require.NoError(t, stampTimestamps(docs))
+	assert.JSONEq(t, `{"id":1}`, string(docs[0]))
+	assert.Empty(t, TimestampFields())
+
+	CreatedAtField = "created_at"
+	UpdatedAtField = "meta.updated_at"
+
+	assert.Equal(t, []string{"created_at", "meta.updated_at"}, TimestampFields())
+
+	docs = []json.RawMessage{
+		[]byte(`{"id":1}`),
+		[]byte(`{"id":2,"created_at":"2020-01-01T00:00:00Z","meta":{"updated_at":"2020-01-01T00:00:00Z"}}`),
+		[]byte(`{"id":3,"created_at":null,"meta":{"v":1}}`),
+	}
+
+	require.NoError(t, stampTimestamps(docs))
+	assert.JSONEq(t, `{"id":1,"created_at":"2023-05-06T06:08:09Z","meta":{"updated_at":"2023-05-06T06:08:09Z"}}`,
+		string(docs[0]))
+	assert.JSONEq(t, `{"id":2,"created_at":"2020-01-01T00:00:00Z","meta":{"updated_at":"2023-05-06T06:08:09Z"}}`,
+		string(docs[1]))
+	assert.JSONEq(t, `{"id":3,"created_at":"2023-05-06T06:08:09Z","meta":{"v":1,"updated_at":"2023-05-06T06:08:09Z"}}`,
+		string(docs[2]))
+}
@@ -0,0 +1,158 @@
+// Copyright 2022-2023 Tigris Data, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package iterate
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+
+	"github.com/santhosh-tekuri/jsonschema/v5"
+	"github.com/tigrisdata/tigris-cli/util"
+)
+
+var (
+	// ValidateWith is the path to the JSON Schema file to validate documents against.
+	ValidateWith string
+
+	ErrValidation = fmt.Errorf("document validation failed")
+
+	validator *jsonschema.Schema
+
+	// validationFailures counts failed validations by the reason.
+	validationFailures map[string]int64
+)
+
+func loadValidator() error {
+	if ValidateWith == "" {
+		return nil
+	}
+
+	sch, err := jsonschema.Compile(ValidateWith)
+	if err != nil {
+		return util.Error(err, "compile JSON schema: %s", ValidateWith)
+	}
+
+	validator = sch
+	validationFailures = make(map[string]int64)
+
+	return nil
+}
+
+// validationReasons returns the reasons of the validation failure,
+// which are the leaf errors of the validation error tree.
+func validationReasons(verr *jsonschema.ValidationError, reasons []string) []string {
+	if len(verr.Causes) == 0 {
+		loc := verr.InstanceLocation
+		if loc == "" {
+			loc = "/"
+		}
+
+		return append(reasons, fmt.Sprintf("%s: %s", loc, verr.Message))
+	}
+
+	for _, c := range verr.Causes {
+		reasons = validationReasons(c, reasons)
+	}
+
+	return reasons
+}
+
+func validateDoc(doc json.RawMessage) error {
+	var v any
+
+	dec := json.NewDecoder(bytes.NewReader(doc))
+	dec.UseNumber()
+
+	if err := dec.Decode(&v); err != nil {
+		return err
+	}
+
+	err := validator.Validate(v)
+	if err == nil {
+		return nil
+	}
+
+	var verr *jsonschema.ValidationError
+	if !errors.As(err, &verr) {
+		return err
+	}
+
+	reasons := validationReasons(verr, nil)
+	for _, r := range reasons {
+		validationFailures[r]++
+	}
+
+	return fmt.Errorf("%w: %s", ErrValidation, reasons[0])
+}
+
+// validateDocs removes the documents which don't pass validation against the ValidateWith schema.
+// Invalid documents are saved to the error file when SkipErrors is set,
+// otherwise error is returned.
+func (r *Run) validateDocs(docs []json.RawMessage) ([]json.RawMessage, error) {
+	if validator == nil {
+		return docs, nil
+	}
+
+	valid := docs[:0]
+
+	for _, doc := range docs {
+		if err := validateDoc(doc); err != nil {
+			if !SkipErrors {
+				return nil, err
+			}
+
+			if err = r.writeErrorDoc(doc, err); err != nil {
+				return nil, err
+			}
+
+			continue
+		}
+
+		valid = append(valid, doc)
+	}
+
+	return valid, nil
+}
+
+func reportValidationFailures() {
+	if len(validationFailures) == 0 {
+		return
+	}
+
+	reasons := make([]string, 0, len(validationFailures))
+	for r := range validationFailures {
+		reasons = append(reasons, r)
+	}
+
+	sort.Slice(reasons, func(i, j int) bool {
+		if validationFailures[reasons[i]] != validationFailures[reasons[j]] {
+			return validationFailures[reasons[i]] > validationFailures[reasons[j]]
+		}
+
+		return reasons[i] < reasons[j]
+	})
+
+	util.Stderrf("Validation failures against %s:\n", ValidateWith)
+
+	for _, r := range reasons {
+		util.Stderrf("  %6d  %s\n", validationFailures[r], r)
+	}
+
+	validator = nil
+	validationFailures = nil
+}
@@ -0,0 +1,84 @@
+// Copyright 2022-2023 Tigris Data, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package iterate
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidateWith(t *testing.T) {
+	ValidateWith = filepath.Join(t.TempDir(), "schema.json")
+
+	err := os.WriteFile(ValidateWith, []byte(`{
+		"type": "object",
+		"properties": {
+			"id": { "type": "integer" },
+			"name": { "type": "string", "maxLength": 5 }
+		},
+		"required": ["id"]
+	}`), 0o600)
+	require.NoError(t, err)
+
+	defer func() {
+		ValidateWith = ""
+		SkipErrors = false
+		defaultRun.Skipped = 0
+		validator = nil
+		validationFailures = nil
+	}()
+
+	docs := func() []json.RawMessage {
+		return []json.RawMessage{
+			json.RawMessage(`{"id":1, "name":"a"}`),
+			json.RawMessage(`{"id":"2"}`),
+			json.RawMessage(`{"name":"b"}`),
+			json.RawMessage(`{"id":4, "name":"too long"}`),
+			json.RawMessage(`{"id":5}`),
+		}
+	}
+
+	var inserted []json.RawMessage
+
+	process := func(ctx context.Context, args []string, docs []json.RawMessage) error {
+		inserted = append(inserted, docs...)
+		return nil
+	}
+
+	require.NoError(t, loadValidator())
+
+	err = processBatch(context.Background(), nil, docs(), process)
+	require.ErrorIs(t, err, ErrValidation)
+	assert.Empty(t, inserted)
+
+	SkipErrors = true
+	validationFailures = make(map[string]int64)
+
+	err = processBatch(context.Background(), nil, docs(), process)
+	require.NoError(t, err)
+
+	assert.Equal(t, []json.RawMessage{
+		json.RawMessage(`{"id":1, "name":"a"}`),
+		json.RawMessage(`{"id":5}`),
+	}, inserted)
+	assert.Equal(t, int64(3), defaultRun.Skipped)
+	assert.Len(t, validationFailures, 3)
+}
@@ -0,0 +1,202 @@
+// Copyright 2022-2023 Tigris Data, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package iterate
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+
+	"github.com/tigrisdata/tigris-cli/util"
+)
+
+var (
+	// WatermarkField is the field, the maximum value of which is persisted in the StateFile,
+	// so the subsequent runs import only the documents with the greater value of the field.
+	//
+	// The values of the field are assumed to grow monotonically in the order of the source:
+	// the watermark is advanced after every successfully processed batch, so the documents
+	// with the smaller value, which come later in the source, are skipped by the next run.
+	// Numbers are compared numerically, strings lexicographically, so the timestamps
+	// should be in the same format and time zone, like RFC3339 UTC.
+	// The documents without the field are always imported and don't advance the watermark.
+	WatermarkField string
+
+	// StateFile is the path to the file, where the watermark is persisted between the runs.
+	StateFile string
+
+	// WatermarkSkipped is the number of the documents skipped as not newer than the watermark.
+	WatermarkSkipped int64
+
+	ErrWatermarkNoStateFile  = fmt.Errorf("--watermark-field requires --state-file")
+	ErrWatermarkFieldChanged = fmt.Errorf("state file holds the watermark of another field")
+
+	// watermark is the value loaded from the state file, nil when there is no previous run.
+	watermark any
+
+	// watermarkMax is the maximum value of the field in the successfully processed batches.
+	watermarkMax any
+)
+
+// watermarkState is the content of the state file.
+type watermarkState struct {
+	Field     string `json:"watermark_field"`
+	Watermark any    `json:"watermark"`
+}
+
+// watermarkValue converts the value of the field to the form compared by compareWhere.
+// Returns nil for the values other than numbers and strings, which are not ordered.
+func watermarkValue(v any) any {
+	switch t := v.(type) {
+	case json.Number:
+		f, err := t.Float64()
+		if err != nil {
+			return nil
+		}
+
+		return f
+	case string:
+		return t
+	}
+
+	return nil
+}
+
+// loadWatermark reads the watermark persisted by the previous run.
+func loadWatermark() error {
+	watermark = nil
+	watermarkMax = nil
+	WatermarkSkipped = 0
+
+	if WatermarkField == "" {
+		return nil
+	}
+
+	if StateFile == "" {
+		return ErrWatermarkNoStateFile
+	}
+
+	b, err := os.ReadFile(StateFile)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil
+	}
+
+	if err != nil {
+		return util.Error(err, "read state file: %s", StateFile)
+	}
+
+	var st watermarkState
+
+	dec := json.NewDecoder(bytes.NewReader(b))
+	dec.UseNumber()
+
+	if err = dec.Decode(&st); err != nil {
+		return util.Error(err, "parse state file: %s", StateFile)
+	}
+
+	if st.Field != WatermarkField {
+		return fmt.Errorf("%w: %s, expected: %s", ErrWatermarkFieldChanged, st.Field, WatermarkField)
+	}
+
+	if watermarkValue(st.Watermark) == nil {
+		return nil
+	}
+
+	watermark = st.Watermark
+	watermarkMax = st.Watermark
+
+	return nil
+}
+
+// filterWatermark removes the documents not newer than the watermark
+// and returns the maximum value of the field in the remaining documents.
+func filterWatermark(docs []json.RawMessage) ([]json.RawMessage, any, error) {
+	if WatermarkField == "" {
+		return docs, nil, nil
+	}
+
+	var batchMax any
+
+	res := docs[:0]
+
+	for _, doc := range docs {
+		var m map[string]any
+
+		dec := json.NewDecoder(bytes.NewReader(doc))
+		dec.UseNumber()
+
+		if err := dec.Decode(&m); err != nil {
+			return nil, nil, err
+		}
+
+		v := fieldValue(m, WatermarkField)
+		if watermarkValue(v) == nil {
+			res = append(res, doc)
+			continue
+		}
+
+		if watermark != nil && !compareWhere(">", watermarkValue(v), watermarkValue(watermark)) {
+			WatermarkSkipped++
+			continue
+		}
+
+		if batchMax == nil || compareWhere(">", watermarkValue(v), watermarkValue(batchMax)) {
+			batchMax = v
+		}
+
+		res = append(res, doc)
+	}
+
+	return res, batchMax, nil
+}
+
+// advanceWatermark records the maximum value of the field in the successfully processed batch.
+func advanceWatermark(batchMax any) {
+	if batchMax != nil && (watermarkMax == nil ||
+		compareWhere(">", watermarkValue(batchMax), watermarkValue(watermarkMax))) {
+		watermarkMax = batchMax
+	}
+}
+
+// saveWatermark persists the watermark advanced by the run to the state file.
+func saveWatermark() error {
+	if WatermarkField == "" || watermarkMax == nil || watermarkMax == watermark {
+		return nil
+	}
+
+	b, err := json.Marshal(&watermarkState{Field: WatermarkField, Watermark: watermarkMax})
+	if err != nil {
+		return util.Error(err, "marshal state")
+	}
+
+	tmp := StateFile + ".tmp"
+
+	if err = os.WriteFile(tmp, append(b, '\n'), 0o600); err != nil {
+		return util.Error(err, "write state file: %s", tmp)
+	}
+
+	return util.Error(os.Rename(tmp, StateFile), "write state file: %s", StateFile)
+}
+
+func reportWatermark() {
+	if WatermarkSkipped > 0 {
+		util.Stderrf("%d document(s) not newer than the watermark skipped\n", WatermarkSkipped)
+	}
+
+	util.Fatal(saveWatermark(), "save watermark")
+}
@@ -0,0 +1,115 @@
+// Copyright 2022-2023 Tigris Data, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package iterate
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWatermark(t *testing.T) {
+	defer func() {
+		WatermarkField = ""
+		StateFile = ""
+		WatermarkSkipped = 0
+		watermark = nil
+		watermarkMax = nil
+	}()
+
+	WatermarkField = "seq"
+	StateFile = ""
+
+	require.ErrorIs(t, loadWatermark(), ErrWatermarkNoStateFile)
+
+	StateFile = filepath.Join(t.TempDir(), "state.json")
+
+	run := func(docs ...string) []string {
+		var res []string
+
+		in := make([]json.RawMessage, 0, len(docs))
+		for _, d := range docs {
+			in = append(in, json.RawMessage(d))
+		}
+
+		done, err := startInput(context.Background())
+		require.NoError(t, err)
+
+		err = processBatch(context.Background(), nil, in,
+			func(_ context.Context, _ []string, docs []json.RawMessage) error {
+				for _, d := range docs {
+					res = append(res, string(d))
+				}
+
+				return nil
+			})
+		require.NoError(t, err)
+
+		done()
+
+		return res
+	}
+
+	// no state file, everything is imported
+	assert.Equal(t, []string{`{"seq":1}`, `{"seq":3}`, `{"id":"a"}`},
+		run(`{"seq":1}`, `{"seq":3}`, `{"id":"a"}`))
+
+	b, err := os.ReadFile(StateFile)
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"watermark_field":"seq","watermark":3}`, string(b))
+
+	// documents not newer than the watermark are skipped, documents without the field are imported
+	assert.Equal(t, []string{`{"seq":4}`, `{"id":"b"}`, `{"seq":10}`},
+		run(`{"seq":2}`, `{"seq":3}`, `{"seq":4}`, `{"id":"b"}`, `{"seq":10}`))
+	assert.Equal(t, int64(2), WatermarkSkipped)
+
+	b, err = os.ReadFile(StateFile)
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"watermark_field":"seq","watermark":10}`, string(b))
+
+	// nothing new
+	assert.Empty(t, run(`{"seq":9}`))
+
+	WatermarkField = "updated_at"
+
+	require.ErrorIs(t, loadWatermark(), ErrWatermarkFieldChanged)
+}
+
+func TestWatermarkStrings(t *testing.T) {
+	defer func() {
+		WatermarkField = ""
+		watermark = nil
+		watermarkMax = nil
+	}()
+
+	WatermarkField = "ts"
+	watermark = "2023-01-02T00:00:00Z"
+
+	docs, batchMax, err := filterWatermark([]json.RawMessage{
+		json.RawMessage(`{"ts":"2023-01-01T00:00:00Z"}`),
+		json.RawMessage(`{"ts":"2023-01-03T00:00:00Z"}`),
+		json.RawMessage(`{"ts":"2023-01-05T00:00:00Z"}`),
+		json.RawMessage(`{"ts":"2023-01-04T00:00:00Z"}`),
+		json.RawMessage(`{"ts":true}`),
+	})
+	require.NoError(t, err)
+	assert.Len(t, docs, 4)
+	assert.Equal(t, "2023-01-05T00:00:00Z", batchMax)
+}
@@ -0,0 +1,410 @@
+// Copyright 2022-2023 Tigris Data, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package iterate
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"github.com/tigrisdata/tigris-cli/util"
+)
+
+// Where is the predicate, only the documents matching which are imported.
+//
+// Grammar:
+//
+//	expr    = and { "||" and }
+//	and     = not { "&&" not }
+//	not     = "!" not | cmp
+//	cmp     = operand [ ( "==" | "!=" | "<" | "<=" | ">" | ">=" ) operand ]
+//	operand = field | string | number | "true" | "false" | "null" | "(" expr ")"
+//	field   = name { "." name }, name is letters, digits and underscores, not starting with a digit
+//	string  = double-quoted string with Go escapes
+//
+// Absent fields are null. Numbers are compared numerically, strings lexicographically,
+// ordering of the values of different types is false. Operand alone matches when it's true.
+var Where string
+
+// Filtered is the number of the documents not matching the Where predicate.
+var Filtered int64
+
+var ErrWhereSyntax = fmt.Errorf("invalid --where expression")
+
+// whereNode evaluates the expression node on the document.
+type whereNode func(doc map[string]any) any
+
+var whereExpr whereNode
+
+type whereToken struct {
+	pos  int
+	kind string // "op", "name", "string", "number", "end"
+	text string
+}
+
+type whereParser struct {
+	expr   string
+	tokens []whereToken
+	cur    int
+}
+
+func parseWhere() error {
+	whereExpr = nil
+	Filtered = 0
+
+	if Where == "" {
+		return nil
+	}
+
+	p := &whereParser{expr: Where}
+
+	if err := p.tokenize(); err != nil {
+		return err
+	}
+
+	node, err := p.or()
+	if err != nil {
+		return err
+	}
+
+	if t := p.peek(); t.kind != "end" {
+		return p.errorf(t, "unexpected %q", t.text)
+	}
+
+	whereExpr = node
+
+	return nil
+}
+
+func (p *whereParser) errorf(t whereToken, format string, args ...any) error {
+	return fmt.Errorf("%w at position %d: %s", ErrWhereSyntax, t.pos+1, fmt.Sprintf(format, args...))
+}
+
+func isNameChar(c byte, first bool) bool {
+	return c == '_' || (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z') || (!first && c >= '0' && c <= '9')
+}
+
+func (p *whereParser) tokenize() error {
+	s := p.expr
+
+	for i := 0; i < len(s); {
+		c := s[i]
+
+		switch {
+		case c == ' ' || c == '\t' || c == '\n' || c == '\r':
+			i++
+		case strings.HasPrefix(s[i:], "==") || strings.HasPrefix(s[i:], "!=") ||
+			strings.HasPrefix(s[i:], "<=") || strings.HasPrefix(s[i:], ">=") ||
+			strings.HasPrefix(s[i:], "&&") || strings.HasPrefix(s[i:], "||"):
+			p.tokens = append(p.tokens, whereToken{pos: i, kind: "op", text: s[i : i+2]})
+			i += 2
+		case strings.IndexByte("<>!()", c) >= 0:
+			p.tokens = append(p.tokens, whereToken{pos: i, kind: "op", text: s[i : i+1]})
+			i++
+		case c == '"':
+			j := i + 1
+			for ; j < len(s) && s[j] != '"'; j++ {
+				if s[j] == '\\' {
+					j++
+				}
+			}
+
+			if j >= len(s) {
+				return p.errorf(whereToken{pos: i}, "unterminated string")
+			}
+
+			v, err := strconv.Unquote(s[i : j+1])
+			if err != nil {
+				return p.errorf(whereToken{pos: i}, "invalid string %s", s[i:j+1])
+			}
+
+			p.tokens = append(p.tokens, whereToken{pos: i, kind: "string", text: v})
+			i = j + 1
+		case c == '-' || c == '.' || (c >= '0' && c <= '9'):
+			j := i + 1
+			for ; j < len(s) && strings.IndexByte("0123456789.eE+-", s[j]) >= 0; j++ {
+				if (s[j] == '+' || s[j] == '-') && s[j-1] != 'e' && s[j-1] != 'E' {
+					break
+				}
+			}
+
+			if _, err := strconv.ParseFloat(s[i:j], 64); err != nil {
+				return p.errorf(whereToken{pos: i}, "invalid number %s", s[i:j])
+			}
+
+			p.tokens = append(p.tokens, whereToken{pos: i, kind: "number", text: s[i:j]})
+			i = j
+		case isNameChar(c, true):
+			j := i + 1
+			for j < len(s) && (isNameChar(s[j], false) || (s[j] == '.' && j+1 < len(s) && isNameChar(s[j+1], true))) {
+				j++
+			}
+
+			p.tokens = append(p.tokens, whereToken{pos: i, kind: "name", text: s[i:j]})
+			i = j
+		default:
+			return p.errorf(whereToken{pos: i}, "unexpected character %q", c)
+		}
+	}
+
+	p.tokens = append(p.tokens, whereToken{pos: len(s), kind: "end", text: "end of expression"})
+
+	return nil
+}
+
+func (p *whereParser) peek() whereToken {
+	return p.tokens[p.cur]
+}
+
+func (p *whereParser) next() whereToken {
+	t := p.tokens[p.cur]
+	if t.kind != "end" {
+		p.cur++
+	}
+
+	return t
+}
+
+func (p *whereParser) isOp(text string) bool {
+	t := p.peek()
+
+	return t.kind == "op" && t.text == text
+}
+
+func (p *whereParser) or() (whereNode, error) {
+	left, err := p.and()
+	if err != nil {
+		return nil, err
+	}
+
+	for p.isOp("||") {
+		p.next()
+
+		right, err := p.and()
+		if err != nil {
+			return nil, err
+		}
+
+		l := left
+		left = func(doc map[string]any) any { return l(doc) == true || right(doc) == true }
+	}
+
+	return left, nil
+}
+
+func (p *whereParser) and() (whereNode, error) {
+	left, err := p.not()
+	if err != nil {
+		return nil, err
+	}
+
+	for p.isOp("&&") {
+		p.next()
+
+		right, err := p.not()
+		if err != nil {
+			return nil, err
+		}
+
+		l := left
+		left = func(doc map[string]any) any { return l(doc) == true && right(doc) == true }
+	}
+
+	return left, nil
+}
+
+func (p *whereParser) not() (whereNode, error) {
+	if p.isOp("!") {
+		p.next()
+
+		n, err := p.not()
+		if err != nil {
+			return nil, err
+		}
+
+		return func(doc map[string]any) any { return n(doc) != true }, nil
+	}
+
+	return p.cmp()
+}
+
+func (p *whereParser) cmp() (whereNode, error) {
+	left, err := p.operand()
+	if err != nil {
+		return nil, err
+	}
+
+	t := p.peek()
+	if t.kind != "op" {
+		return left, nil
+	}
+
+	switch t.text {
+	case "==", "!=", "<", "<=", ">", ">=":
+	default:
+		return left, nil
+	}
+
+	p.next()
+
+	right, err := p.operand()
+	if err != nil {
+		return nil, err
+	}
+
+	op := t.text
+
+	return func(doc map[string]any) any { return compareWhere(op, left(doc), right(doc)) }, nil
+}
+
+func (p *whereParser) operand() (whereNode, error) {
+	t := p.next()
+
+	switch t.kind {
+	case "string":
+		return func(map[string]any) any { return t.text }, nil
+	case "number":
+		f, _ := strconv.ParseFloat(t.text, 64)
+
+		return func(map[string]any) any { return f }, nil
+	case "name":
+		switch t.text {
+		case "true":
+			return func(map[string]any) any { return true }, nil
+		case "false":
+			return func(map[string]any) any { return false }, nil
+		case "null":
+			return func(map[string]any) any { return nil }, nil
+		}
+
+		return func(doc map[string]any) any { return whereValue(doc, t.text) }, nil
+	case "op":
+		if t.text == "(" {
+			n, err := p.or()
+			if err != nil {
+				return nil, err
+			}
+
+			if c := p.next(); c.kind != "op" || c.text != ")" {
+				return nil, p.errorf(c, "expected \")\", got %q", c.text)
+			}
+
+			return n, nil
+		}
+	}
+
+	return nil, p.errorf(t, "expected field, value or \"(\", got %q", t.text)
+}
+
+// whereValue returns the value of the field, numbers are converted to float64.
+func whereValue(doc map[string]any, name string) any {
+	v := fieldValue(doc, name)
+
+	if n, ok := v.(json.Number); ok {
+		f, err := n.Float64()
+		if err != nil {
+			return nil
+		}
+
+		return f
+	}
+
+	return v
+}
+
+// compareWhere compares the values. Arrays and objects are only compared for equality,
+// which is deep, as they are not comparable with ==.
+func compareWhere(op string, l any, r any) bool {
+	switch op {
+	case "==":
+		return reflect.DeepEqual(l, r)
+	case "!=":
+		return !reflect.DeepEqual(l, r)
+	}
+
+	var c int
+
+	switch lv := l.(type) {
+	case float64:
+		rv, ok := r.(float64)
+		if !ok {
+			return false
+		}
+
+		switch {
+		case lv < rv:
+			c = -1
+		case lv > rv:
+			c = 1
+		}
+	case string:
+		rv, ok := r.(string)
+		if !ok {
+			return false
+		}
+
+		c = strings.Compare(lv, rv)
+	default:
+		return false
+	}
+
+	switch op {
+	case "<":
+		return c < 0
+	case "<=":
+		return c <= 0
+	case ">":
+		return c > 0
+	default:
+		return c >= 0
+	}
+}
+
+func reportFiltered() {
+	if Filtered > 0 {
+		util.Stderrf("%d document(s) not matching --where skipped\n", Filtered)
+	}
+}
+
+// filterDocs removes the documents not matching the Where predicate.
+func filterDocs(docs []json.RawMessage) ([]json.RawMessage, error) {
+	if whereExpr == nil {
+		return docs, nil
+	}
+
+	res := docs[:0]
+
+	for _, doc := range docs {
+		var m map[string]any
+
+		dec := json.NewDecoder(bytes.NewReader(doc))
+		dec.UseNumber()
+
+		if err := dec.Decode(&m); err != nil {
+			return nil, err
+		}
+
+		if whereExpr(m) == true {
+			res = append(res, doc)
+		} else {
+			Filtered++
+		}
+	}
+
+	return res, nil
+}
@@ -0,0 +1,118 @@
+// Copyright 2022-2023 Tigris Data, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package iterate
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFilterDocs(t *testing.T) {
+	defer func() {
+		Where = ""
+		whereExpr = nil
+		Filtered = 0
+	}()
+
+	docs := func() []json.RawMessage {
+		return []json.RawMessage{
+			json.RawMessage(`{"id":1,"status":"active","age":30,"admin":false,"addr":{"city":"SF"},"tags":["a","b"],` +
+				`"home":{"city":"SF"}}`),
+			json.RawMessage(`{"id":2,"status":"blocked","age":17,"admin":true,"tags":["a"],"home":{"city":"LA"}}`),
+			json.RawMessage(`{"id":3,"status":"active","age":12.5,"admin":false,"name":null}`),
+		}
+	}
+
+	cases := []struct {
+		where string
+		ids   []int
+	}{
+		{`status == "active"`, []int{1, 3}},
+		{`status != "active"`, []int{2}},
+		{`age >= 18`, []int{1}},
+		{`age < 17.5`, []int{2, 3}},
+		{`status == "active" && (age >= 18 || admin)`, []int{1}},
+		{`admin || age > 20`, []int{1, 2}},
+		{`!admin`, []int{1, 3}},
+		{`addr.city == "SF"`, []int{1}},
+		{`addr.city == null`, []int{2, 3}},
+		{`name == null`, []int{1, 2, 3}},
+		{`status > 5`, nil},
+		{`status >= "b"`, []int{2}},
+		{`id == 2 || id == 3`, []int{2, 3}},
+		{`"a\"b" != status`, []int{1, 2, 3}},
+		{`addr == home`, []int{1, 3}},
+		{`addr != home`, []int{2}},
+		{`tags == tags`, []int{1, 2, 3}},
+		{`tags != null`, []int{1, 2}},
+		{`tags > addr`, nil},
+	}
+
+	for _, c := range cases {
+		t.Run(c.where, func(t *testing.T) {
+			Where = c.where
+
+			require.NoError(t, parseWhere())
+
+			res, err := filterDocs(docs())
+			require.NoError(t, err)
+
+			var ids []int
+
+			for _, doc := range res {
+				var d struct {
+					ID int `json:"id"`
+				}
+
+				require.NoError(t, json.Unmarshal(doc, &d))
+
+				ids = append(ids, d.ID)
+			}
+
+			assert.Equal(t, c.ids, ids)
+			assert.Equal(t, int64(3-len(c.ids)), Filtered)
+		})
+	}
+}
+
+func TestParseWhereErrors(t *testing.T) {
+	defer func() {
+		Where = ""
+		whereExpr = nil
+	}()
+
+	cases := []struct {
+		where string
+		err   string
+	}{
+		{`status ==`, `invalid --where expression at position 10: expected field, value or "(", got "end of expression"`},
+		{`(a == 1`, `invalid --where expression at position 8: expected ")", got "end of expression"`},
+		{`a == "x`, `invalid --where expression at position 6: unterminated string`},
+		{`a = 1`, `invalid --where expression at position 3: unexpected character '='`},
+		{`a == 1 b`, `invalid --where expression at position 8: unexpected "b"`},
+		{`a == 1.2.3`, `invalid --where expression at position 6: invalid number 1.2.3`},
+	}
+
+	for _, c := range cases {
+		Where = c.where
+
+		err := parseWhere()
+		require.ErrorIs(t, err, ErrWhereSyntax)
+		assert.Equal(t, c.err, err.Error())
+	}
+}
@@ -105,6 +105,8 @@ func getToken(auth *Authenticator, code string) *oauth2.Token {
 	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
 	defer cancel()
 
+	ctx = context.WithValue(ctx, oauth2.HTTPClient, util.HTTPClient(0))
+
 	tkn, err := auth.Exchange(ctx, code)
 	util.Fatal(err, "retrieving token")
 
@@ -189,6 +191,10 @@ type Authenticator struct {
 }
 
 func Ensure(cctx context.Context, fn func(ctx context.Context) error) {
+	if config.DefaultConfig.TokenFile != "" {
+		checkTokenFile(cctx)
+	}
+
 	ctx, cancel := util.GetContext(cctx)
 
 	err := fn(ctx)
@@ -199,7 +205,14 @@ func Ensure(cctx context.Context, fn func(ctx context.Context) error) {
 		return
 	}
 
+	// the token file has been checked upfront, the command can't be retried,
+	// because it might have consumed its input already
 	var ep *driver.Error
+	if errors.As(err, &ep) && ep.Code == ecode.Unauthenticated && config.DefaultConfig.TokenFile != "" {
+		util.PrintError(err)
+		os.Exit(1) //nolint:revive
+	}
+
 	if !errors.As(err, &ep) || ep.Code != ecode.Unauthenticated ||
 		os.Getenv(driver.EnvClientID) != "" || os.Getenv(driver.EnvClientSecret) != "" ||
 		config.DefaultConfig.ClientID != "" || config.DefaultConfig.ClientSecret != "" || !util.IsTTY(os.Stdin) ||
@@ -231,6 +244,36 @@ func Ensure(cctx context.Context, fn func(ctx context.Context) error) {
 	}
 }
 
+// checkTokenFile checks the token read from the token file before running the command,
+// re-reading the file, which may have been rotated, when the token is rejected by the server.
+// The check is done upfront, because the command can't be retried after it has consumed
+// its input, like stdin.
+func checkTokenFile(cctx context.Context) {
+	ctx, cancel := util.GetContext(cctx)
+
+	_, err := client.Get().ListProjects(ctx)
+
+	cancel()
+
+	var ep *driver.Error
+	if !errors.As(err, &ep) || ep.Code != ecode.Unauthenticated {
+		// other errors are reported by the command
+		return
+	}
+
+	changed, rerr := config.ReadTokenFile()
+	util.Fatal(rerr, "read token file")
+
+	if !changed {
+		util.PrintError(err)
+		os.Exit(1) //nolint:revive
+	}
+
+	log.Debug().Str("token_file", config.DefaultConfig.TokenFile).Msg("token file changed")
+
+	util.Fatal(client.Init(&config.DefaultConfig), "init tigris client")
+}
+
 func GetHost(host string) string {
 	if host == "" {
 		host = config.DefaultURL
@@ -247,14 +290,14 @@ func GetHost(host string) string {
 }
 
 func waitCallbackServerUp() {
-	c := http.Client{Timeout: 5 * time.Second}
+	c := util.HTTPClient(5 * time.Second)
 
 	log.Debug().Msg("Waiting for callback server start")
 
 	for {
 		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
 
-		r, err := ctxhttp.Get(ctx, &c, "http://"+callbackHost+"/ping")
+		r, err := ctxhttp.Get(ctx, c, "http://"+callbackHost+"/ping")
 
 		cancel()
 
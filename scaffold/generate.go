@@ -0,0 +1,75 @@
+// Copyright 2022-2023 Tigris Data, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package scaffold
+
+import (
+	"sort"
+
+	"github.com/tigrisdata/tigris-client-go/schema"
+)
+
+const (
+	typeString  = "string"
+	typeInteger = "integer"
+	typeNumber  = "number"
+	typeBoolean = "boolean"
+	typeArray   = "array"
+	typeObject  = "object"
+
+	formatDateTime = "date-time"
+	formatUUID     = "uuid"
+)
+
+// modelClass is the class or the struct of the model, generated by the CLI.
+type modelClass struct {
+	name     string
+	coll     string
+	fields   map[string]*schema.Field
+	required []string
+	pk       []string
+}
+
+func indexOf(list []string, s string) int {
+	for k, v := range list {
+		if v == s {
+			return k
+		}
+	}
+
+	return -1
+}
+
+// modelFieldNames returns the primary key fields in the key order, followed by other fields sorted by name.
+func modelFieldNames(c *modelClass) []string {
+	names := make([]string, 0, len(c.fields))
+
+	for n := range c.fields {
+		if indexOf(c.pk, n) < 0 {
+			names = append(names, n)
+		}
+	}
+
+	sort.Strings(names)
+
+	var pk []string
+
+	for _, n := range c.pk {
+		if c.fields[n] != nil {
+			pk = append(pk, n)
+		}
+	}
+
+	return append(pk, names...)
+}
@@ -0,0 +1,48 @@
+// Copyright 2022-2023 Tigris Data, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package scaffold
+
+import (
+	"bufio"
+	"io"
+
+	api "github.com/tigrisdata/tigris-client-go/api/server/v1"
+)
+
+// Models writes the models of the collections, generated by the server in the language, to the writer.
+// The collections should be described with the language and json schema formats.
+func Models(w io.Writer, colls []*api.CollectionDescription, lang string) error {
+	genType := getGenerator(lang)
+
+	bw := bufio.NewWriter(w)
+
+	if h, ok := genType.(fileHeader); ok {
+		if _, err := bw.WriteString(h.Header()); err != nil {
+			return err
+		}
+	}
+
+	for k, c := range colls {
+		if k > 0 {
+			if _, err := bw.WriteString("\n"); err != nil {
+				return err
+			}
+		}
+
+		writeCollection(nil, bw, c, lang, genType)
+	}
+
+	return bw.Flush()
+}
@@ -0,0 +1,47 @@
+// Copyright 2022-2023 Tigris Data, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package scaffold
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	api "github.com/tigrisdata/tigris-client-go/api/server/v1"
+)
+
+func TestModels(t *testing.T) {
+	coll := func(name string, model string) *api.CollectionDescription {
+		js, err := json.Marshal(map[string]any{"title": name, "primary_key": []string{"id"}})
+		require.NoError(t, err)
+
+		sch, err := json.Marshal(map[string]string{"go": model, "json": string(js)})
+		require.NoError(t, err)
+
+		return &api.CollectionDescription{Collection: name, Schema: sch}
+	}
+
+	var buf bytes.Buffer
+
+	err := Models(&buf, []*api.CollectionDescription{
+		coll("users", "type User struct {\n}\n"),
+		coll("orders", "type Order struct {\n}\n"),
+	}, "go")
+	require.NoError(t, err)
+
+	assert.Equal(t, "type User struct {\n}\n\ntype Order struct {\n}\n", buf.String())
+}
@@ -0,0 +1,191 @@
+// Copyright 2022-2023 Tigris Data, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package scaffold
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/iancoleman/strcase"
+	"github.com/tigrisdata/tigris-client-go/schema"
+)
+
+var ErrPHPUnsupportedType = fmt.Errorf("unsupported field type for PHP model")
+
+// JSONToPHP generates PHP 8 classes with typed properties from the JSON schema of the collection.
+// Nested objects are generated as separate classes, named after the parent class and the field,
+// element types of the arrays are declared in the docblocks.
+// Fields which are not required are nullable and default to null.
+type JSONToPHP struct{}
+
+func (*JSONToPHP) HasTime(schema string) bool {
+	return strings.Contains(schema, "DateTimeImmutable")
+}
+
+func (*JSONToPHP) HasUUID(schema string) bool {
+	return strings.Contains(schema, "#[Uuid]")
+}
+
+func (*JSONToPHP) Header() string {
+	return "<?php\n\n"
+}
+
+type phpGenerator struct {
+	buf   strings.Builder
+	queue []*modelClass
+}
+
+// Generate generates the PHP classes of the collection.
+func (*JSONToPHP) Generate(sch *schema.Schema) (string, error) {
+	g := &phpGenerator{}
+
+	g.queue = append(g.queue, &modelClass{
+		name:     strcase.ToCamel(plural.Singular(sch.Name)),
+		coll:     sch.Name,
+		fields:   sch.Fields,
+		required: sch.Required,
+		pk:       sch.PrimaryKey,
+	})
+
+	for i := 0; i < len(g.queue); i++ {
+		if i > 0 {
+			g.buf.WriteString("\n")
+		}
+
+		if err := g.class(g.queue[i]); err != nil {
+			return "", err
+		}
+	}
+
+	return g.buf.String(), nil
+}
+
+func (g *phpGenerator) class(c *modelClass) error {
+	if c.coll != "" {
+		fmt.Fprintf(&g.buf, "#[Collection('%s')]\n", c.coll)
+	}
+
+	fmt.Fprintf(&g.buf, "class %s\n{\n", c.name)
+
+	for k, n := range modelFieldNames(c) {
+		f := c.fields[n]
+
+		typ, err := g.phpType(c.name, n, f)
+		if err != nil {
+			return err
+		}
+
+		if k > 0 {
+			g.buf.WriteString("\n")
+		}
+
+		pk := indexOf(c.pk, n)
+		if pk >= 0 {
+			fmt.Fprintf(&g.buf, "    #[PrimaryKey(order: %d)]\n", pk+1)
+		}
+
+		if f.AutoGenerate {
+			g.buf.WriteString("    #[AutoGenerate]\n")
+		}
+
+		if f.Type.First() == typeString && f.Format == formatUUID {
+			g.buf.WriteString("    #[Uuid]\n")
+		}
+
+		required := pk >= 0 || indexOf(c.required, n) >= 0
+
+		if f.Type.First() == typeArray || (f.Type.First() == typeObject && len(f.Fields) == 0) {
+			doc, err := g.docType(c.name, n, f)
+			if err != nil {
+				return err
+			}
+
+			if !required {
+				doc += "|null"
+			}
+
+			fmt.Fprintf(&g.buf, "    /** @var %s */\n", doc)
+		}
+
+		if required {
+			fmt.Fprintf(&g.buf, "    public %s $%s;\n", typ, n)
+		} else {
+			fmt.Fprintf(&g.buf, "    public ?%s $%s = null;\n", typ, n)
+		}
+	}
+
+	g.buf.WriteString("}\n")
+
+	return nil
+}
+
+// phpType returns the declared type of the property.
+func (g *phpGenerator) phpType(parent string, name string, f *schema.Field) (string, error) {
+	switch f.Type.First() {
+	case typeString:
+		if f.Format == formatDateTime {
+			return "DateTimeImmutable", nil
+		}
+
+		return "string", nil
+	case typeInteger:
+		return "int", nil
+	case typeNumber:
+		return "float", nil
+	case typeBoolean:
+		return "bool", nil
+	case typeArray:
+		return "array", nil
+	case typeObject:
+		if len(f.Fields) == 0 {
+			return "array", nil
+		}
+
+		return g.nestedClass(parent, name, f), nil
+	}
+
+	return "", fmt.Errorf("%w: %s.%s: %s", ErrPHPUnsupportedType, parent, name, f.Type.First())
+}
+
+// docType returns the type of the property in the docblock, which declares the types of the array elements.
+func (g *phpGenerator) docType(parent string, name string, f *schema.Field) (string, error) {
+	switch {
+	case f == nil:
+		return "mixed", nil
+	case f.Type.First() == typeArray:
+		t, err := g.docType(parent, plural.Singular(name), f.Items)
+		if err != nil {
+			return "", err
+		}
+
+		return t + "[]", nil
+	case f.Type.First() == typeObject && len(f.Fields) == 0:
+		return "array<string, mixed>", nil
+	}
+
+	return g.phpType(parent, name, f)
+}
+
+func (g *phpGenerator) nestedClass(parent string, name string, f *schema.Field) string {
+	c := &modelClass{
+		name:     parent + strcase.ToCamel(name),
+		fields:   f.Fields,
+		required: f.Required,
+	}
+
+	g.queue = append(g.queue, c)
+
+	return c.name
+}
@@ -0,0 +1,145 @@
+// Copyright 2022-2023 Tigris Data, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package scaffold
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	api "github.com/tigrisdata/tigris-client-go/api/server/v1"
+	"github.com/tigrisdata/tigris-client-go/schema"
+)
+
+func TestPHPGenerate(t *testing.T) {
+	js := `{
+	"title": "users",
+	"properties": {
+		"id": { "type": "string", "format": "uuid", "autoGenerate": true },
+		"name": { "type": "string" },
+		"age": { "type": "integer" },
+		"balance": { "type": "number" },
+		"created": { "type": "string", "format": "date-time" },
+		"tags": { "type": "array", "items": { "type": "string" } },
+		"meta": { "type": "object" },
+		"address": {
+			"type": "object",
+			"properties": {
+				"city": { "type": "string" },
+				"zip": { "type": "integer" }
+			},
+			"required": ["city"]
+		},
+		"orders": {
+			"type": "array",
+			"items": {
+				"type": "object",
+				"properties": {
+					"total": { "type": "number" }
+				}
+			}
+		}
+	},
+	"primary_key": ["id"],
+	"required": ["name"]
+}`
+
+	var sch schema.Schema
+
+	require.NoError(t, json.Unmarshal([]byte(js), &sch))
+
+	gen := &JSONToPHP{}
+
+	s, err := gen.Generate(&sch)
+	require.NoError(t, err)
+
+	assert.Equal(t, `#[Collection('users')]
+class User
+{
+    #[PrimaryKey(order: 1)]
+    #[AutoGenerate]
+    #[Uuid]
+    public string $id;
+
+    public ?UserAddress $address = null;
+
+    public ?int $age = null;
+
+    public ?float $balance = null;
+
+    public ?DateTimeImmutable $created = null;
+
+    /** @var array<string, mixed>|null */
+    public ?array $meta = null;
+
+    public string $name;
+
+    /** @var UserOrder[]|null */
+    public ?array $orders = null;
+
+    /** @var string[]|null */
+    public ?array $tags = null;
+}
+
+class UserAddress
+{
+    public string $city;
+
+    public ?int $zip = null;
+}
+
+class UserOrder
+{
+    public ?float $total = null;
+}
+`, s)
+
+	assert.True(t, gen.HasTime(s))
+	assert.True(t, gen.HasUUID(s))
+}
+
+func TestPHPGenerateNoTime(t *testing.T) {
+	var sch schema.Schema
+
+	require.NoError(t, json.Unmarshal([]byte(`{"title": "orders", "properties": {"id": {"type": "integer"}}}`), &sch))
+
+	gen := &JSONToPHP{}
+
+	s, err := gen.Generate(&sch)
+	require.NoError(t, err)
+
+	assert.Equal(t, "#[Collection('orders')]\nclass Order\n{\n    public ?int $id = null;\n}\n", s)
+	assert.False(t, gen.HasTime(s))
+	assert.False(t, gen.HasUUID(s))
+}
+
+func TestPHPModels(t *testing.T) {
+	sch, err := json.Marshal(map[string]string{
+		"json": `{"title": "users", "properties": {"id": {"type": "integer"}}, "primary_key": ["id"]}`,
+	})
+	require.NoError(t, err)
+
+	var buf bytes.Buffer
+
+	err = Models(&buf, []*api.CollectionDescription{{Collection: "users", Schema: sch}}, "php")
+	require.NoError(t, err)
+
+	assert.Equal(t, "<?php\n\n#[Collection('users')]\nclass User\n{\n    #[PrimaryKey(order: 1)]\n    public int $id;\n}\n",
+		buf.String())
+	assert.Equal(t, "json", SchemaFormat("php"))
+	assert.Equal(t, "go,json", SchemaFormat("go"))
+}
@@ -0,0 +1,199 @@
+// Copyright 2022-2023 Tigris Data, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package scaffold
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/iancoleman/strcase"
+	"github.com/tigrisdata/tigris-client-go/schema"
+)
+
+var (
+	ErrRustUnsupportedType = fmt.Errorf("unsupported field type for Rust model")
+
+	rustKeywords = map[string]bool{
+		"as": true, "async": true, "await": true, "break": true, "const": true, "continue": true,
+		"dyn": true, "else": true, "enum": true, "extern": true, "false": true, "fn": true,
+		"for": true, "if": true, "impl": true, "in": true, "let": true, "loop": true, "match": true,
+		"mod": true, "move": true, "mut": true, "pub": true, "ref": true, "return": true,
+		"static": true, "struct": true, "trait": true, "true": true, "type": true, "unsafe": true,
+		"use": true, "where": true, "while": true,
+	}
+
+	// rustReserved are the identifiers which can't be used even as raw identifiers,
+	// the fields are suffixed with underscore instead.
+	rustReserved = map[string]bool{
+		"crate": true, "self": true, "super": true, "module": true,
+	}
+)
+
+// JSONToRust generates Rust structs, deriving serde Serialize and Deserialize,
+// from the JSON schema of the collection.
+// Nested objects are generated as separate structs, named after the parent struct and the field.
+// Fields which are not required are wrapped in Option.
+type JSONToRust struct{}
+
+func (*JSONToRust) HasTime(schema string) bool {
+	return strings.Contains(schema, "chrono::DateTime")
+}
+
+func (*JSONToRust) HasUUID(schema string) bool {
+	return strings.Contains(schema, "uuid::Uuid")
+}
+
+func (*JSONToRust) Header() string {
+	return "use serde::{Deserialize, Serialize};\n\n"
+}
+
+type rustGenerator struct {
+	buf   strings.Builder
+	queue []*modelClass
+}
+
+// Generate generates the Rust structs of the collection.
+func (*JSONToRust) Generate(sch *schema.Schema) (string, error) {
+	g := &rustGenerator{}
+
+	g.queue = append(g.queue, &modelClass{
+		name:     strcase.ToCamel(plural.Singular(sch.Name)),
+		coll:     sch.Name,
+		fields:   sch.Fields,
+		required: sch.Required,
+		pk:       sch.PrimaryKey,
+	})
+
+	for i := 0; i < len(g.queue); i++ {
+		if i > 0 {
+			g.buf.WriteString("\n")
+		}
+
+		if err := g.structure(g.queue[i]); err != nil {
+			return "", err
+		}
+	}
+
+	return g.buf.String(), nil
+}
+
+// rustFieldName returns the snake case identifier of the field,
+// escaping the identifiers which are Rust keywords.
+func rustFieldName(name string) string {
+	n := strcase.ToSnake(name)
+	if rustReserved[n] {
+		return n + "_"
+	}
+
+	if rustKeywords[n] {
+		return "r#" + n
+	}
+
+	return n
+}
+
+func (g *rustGenerator) structure(c *modelClass) error {
+	if c.coll != "" {
+		fmt.Fprintf(&g.buf, "/// Model of the %s collection.\n", c.coll)
+	}
+
+	g.buf.WriteString("#[derive(Debug, Clone, Serialize, Deserialize)]\n")
+	fmt.Fprintf(&g.buf, "pub struct %s {\n", c.name)
+
+	for _, n := range modelFieldNames(c) {
+		f := c.fields[n]
+
+		typ, err := g.rustType(c.name, n, f)
+		if err != nil {
+			return err
+		}
+
+		if pk := indexOf(c.pk, n); pk >= 0 {
+			fmt.Fprintf(&g.buf, "    /// Primary key, order %d.\n", pk+1)
+		}
+
+		required := indexOf(c.pk, n) >= 0 || indexOf(c.required, n) >= 0
+
+		var attrs []string
+
+		name := rustFieldName(n)
+		if strings.TrimPrefix(name, "r#") != n {
+			attrs = append(attrs, fmt.Sprintf("rename = %q", n))
+		}
+
+		if !required {
+			attrs = append(attrs, `skip_serializing_if = "Option::is_none"`)
+			typ = "Option<" + typ + ">"
+		}
+
+		if len(attrs) > 0 {
+			fmt.Fprintf(&g.buf, "    #[serde(%s)]\n", strings.Join(attrs, ", "))
+		}
+
+		fmt.Fprintf(&g.buf, "    pub %s: %s,\n", name, typ)
+	}
+
+	g.buf.WriteString("}\n")
+
+	return nil
+}
+
+// rustType returns the type of the struct field.
+func (g *rustGenerator) rustType(parent string, name string, f *schema.Field) (string, error) {
+	if f == nil {
+		return "serde_json::Value", nil
+	}
+
+	switch f.Type.First() {
+	case typeString:
+		switch f.Format {
+		case formatDateTime:
+			return "chrono::DateTime<chrono::Utc>", nil
+		case formatUUID:
+			return "uuid::Uuid", nil
+		}
+
+		return "String", nil
+	case typeInteger:
+		return "i64", nil
+	case typeNumber:
+		return "f64", nil
+	case typeBoolean:
+		return "bool", nil
+	case typeArray:
+		t, err := g.rustType(parent, plural.Singular(name), f.Items)
+		if err != nil {
+			return "", err
+		}
+
+		return "Vec<" + t + ">", nil
+	case typeObject:
+		if len(f.Fields) == 0 {
+			return "serde_json::Map<String, serde_json::Value>", nil
+		}
+
+		c := &modelClass{
+			name:     parent + strcase.ToCamel(name),
+			fields:   f.Fields,
+			required: f.Required,
+		}
+
+		g.queue = append(g.queue, c)
+
+		return c.name, nil
+	}
+
+	return "", fmt.Errorf("%w: %s.%s: %s", ErrRustUnsupportedType, parent, name, f.Type.First())
+}
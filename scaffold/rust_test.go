@@ -0,0 +1,128 @@
+// Copyright 2022-2023 Tigris Data, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package scaffold
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tigrisdata/tigris-client-go/schema"
+)
+
+func TestRustGenerate(t *testing.T) {
+	js := `{
+	"title": "users",
+	"properties": {
+		"id": { "type": "string", "format": "uuid" },
+		"name": { "type": "string" },
+		"age": { "type": "integer" },
+		"createdAt": { "type": "string", "format": "date-time" },
+		"type": { "type": "string" },
+		"self": { "type": "string" },
+		"super": { "type": "string" },
+		"module": { "type": "string" },
+		"crate": { "type": "string" },
+		"tags": { "type": "array", "items": { "type": "string" } },
+		"address": {
+			"type": "object",
+			"properties": {
+				"city": { "type": "string" },
+				"zip": { "type": "integer" },
+				"Self": { "type": "string" }
+			},
+			"required": ["city"]
+		},
+		"orders": {
+			"type": "array",
+			"items": {
+				"type": "object",
+				"properties": {
+					"total": { "type": "number" }
+				}
+			}
+		}
+	},
+	"primary_key": ["id"],
+	"required": ["name"]
+}`
+
+	var sch schema.Schema
+
+	require.NoError(t, json.Unmarshal([]byte(js), &sch))
+
+	gen := &JSONToRust{}
+
+	s, err := gen.Generate(&sch)
+	require.NoError(t, err)
+
+	assert.Equal(t, `/// Model of the users collection.
+#[derive(Debug, Clone, Serialize, Deserialize)]
+pub struct User {
+    /// Primary key, order 1.
+    pub id: uuid::Uuid,
+    #[serde(skip_serializing_if = "Option::is_none")]
+    pub address: Option<UserAddress>,
+    #[serde(skip_serializing_if = "Option::is_none")]
+    pub age: Option<i64>,
+    #[serde(rename = "crate", skip_serializing_if = "Option::is_none")]
+    pub crate_: Option<String>,
+    #[serde(rename = "createdAt", skip_serializing_if = "Option::is_none")]
+    pub created_at: Option<chrono::DateTime<chrono::Utc>>,
+    #[serde(rename = "module", skip_serializing_if = "Option::is_none")]
+    pub module_: Option<String>,
+    pub name: String,
+    #[serde(skip_serializing_if = "Option::is_none")]
+    pub orders: Option<Vec<UserOrder>>,
+    #[serde(rename = "self", skip_serializing_if = "Option::is_none")]
+    pub self_: Option<String>,
+    #[serde(rename = "super", skip_serializing_if = "Option::is_none")]
+    pub super_: Option<String>,
+    #[serde(skip_serializing_if = "Option::is_none")]
+    pub tags: Option<Vec<String>>,
+    #[serde(skip_serializing_if = "Option::is_none")]
+    pub r#type: Option<String>,
+}
+
+#[derive(Debug, Clone, Serialize, Deserialize)]
+pub struct UserAddress {
+    #[serde(rename = "Self", skip_serializing_if = "Option::is_none")]
+    pub self_: Option<String>,
+    pub city: String,
+    #[serde(skip_serializing_if = "Option::is_none")]
+    pub zip: Option<i64>,
+}
+
+#[derive(Debug, Clone, Serialize, Deserialize)]
+pub struct UserOrder {
+    #[serde(skip_serializing_if = "Option::is_none")]
+    pub total: Option<f64>,
+}
+`, s)
+
+	assert.True(t, gen.HasTime(s))
+	assert.True(t, gen.HasUUID(s))
+	assert.Equal(t, "json", SchemaFormat("rust"))
+}
+
+func TestRustGenerateUnsupportedType(t *testing.T) {
+	var sch schema.Schema
+
+	require.NoError(t, json.Unmarshal([]byte(`{"title": "orders", "properties": {"id": {"type": "null"}}}`), &sch))
+
+	_, err := (&JSONToRust{}).Generate(&sch)
+	require.ErrorIs(t, err, ErrRustUnsupportedType)
+}
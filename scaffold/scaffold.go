@@ -42,7 +42,7 @@ import (
 )
 
 var (
-	ErrUnsupportedFormat    = fmt.Errorf("unsupported language. supported are: TypeScript, Go, Java")
+	ErrUnsupportedFormat    = fmt.Errorf("unsupported language. supported are: TypeScript, Go, Java, PHP, Rust")
 	ErrTemplatesInvalidPath = fmt.Errorf("only local templates path substitution is allowed")
 
 	templatesRepoURL = "https://github.com/tigrisdata/tigris-templates"
@@ -51,7 +51,7 @@ var (
 	plural = pluralize.NewClient()
 
 	ErrUnknownFramewrok = fmt.Errorf("unknown framework")
-	ErrDirAlreadyExists = fmt.Errorf("output directory already exists")
+	ErrDirAlreadyExists = fmt.Errorf("output directory already exists. use --force to overwrite")
 )
 
 type Config struct {
@@ -67,6 +67,8 @@ type Config struct {
 	ClientSecret    string
 	Components      []string
 	Collections     []*api.CollectionDescription
+	// Force allows to scaffold into existing output directory, overwriting the files
+	Force bool
 }
 
 type TmplVars struct {
@@ -86,6 +88,28 @@ type JSONToLangType interface {
 	HasUUID(string) bool
 }
 
+// localGenerator is implemented by the languages, the models of which
+// are generated by the CLI from the JSON schema, rather than by the server.
+type localGenerator interface {
+	Generate(sch *schema.Schema) (string, error)
+}
+
+// fileHeader is implemented by the languages, which require the header
+// in the beginning of the file with the models.
+type fileHeader interface {
+	Header() string
+}
+
+// SchemaFormat returns the formats of the schemas to be requested from the server,
+// to generate the models in the language.
+func SchemaFormat(lang string) string {
+	if _, ok := getGenerator(lang).(localGenerator); ok {
+		return "json"
+	}
+
+	return lang + ",json"
+}
+
 type Collection struct {
 	Name       string // UserName
 	NameDecap  string // userName
@@ -150,6 +174,10 @@ func getGenerator(lang string) JSONToLangType {
 		genType = &JSONToTypeScript{}
 	case "java":
 		genType = &JSONToJava{}
+	case "php":
+		genType = &JSONToPHP{}
+	case "rust":
+		genType = &JSONToRust{}
 	default:
 		util.Fatal(ErrUnsupportedFormat, "")
 	}
@@ -183,7 +211,21 @@ func decodeSchemas(inSchema []byte, lang string) (string, *schema.Schema, string
 func writeCollection(_ *TmplVars, w *bufio.Writer, collection *api.CollectionDescription,
 	lang string, genType JSONToLangType,
 ) *Collection {
-	s, js, jss := decodeSchemas(collection.Schema, lang)
+	var (
+		s, jss string
+		js     *schema.Schema
+	)
+
+	if g, ok := genType.(localGenerator); ok {
+		_, js, jss = decodeSchemas(collection.Schema, "json")
+
+		var err error
+
+		s, err = g.Generate(js)
+		util.Fatal(err, "generate %s model of collection: %s", lang, collection.Collection)
+	} else {
+		s, js, jss = decodeSchemas(collection.Schema, lang)
+	}
 
 	if w != nil {
 		_, err := w.WriteString(s)
@@ -388,7 +430,7 @@ func Project(cfg *Config) {
 
 	log.Debug().Str("outDir", cfg.OutputDirectory).Msg("check output directory exists")
 
-	if _, err := os.Stat(cfg.OutputDirectory); err == nil {
+	if _, err := os.Stat(cfg.OutputDirectory); err == nil && !cfg.Force {
 		util.Fatal(fmt.Errorf("%w: %s", ErrDirAlreadyExists, cfg.OutputDirectory), "output directory already exists")
 	}
 
@@ -401,6 +443,10 @@ func InitGit(outDir string) {
 	log.Debug().Msgf("Initializing git repository %s", outDir)
 
 	repo, err := git.PlainInit(outDir, false)
+	if errors.Is(err, git.ErrRepositoryAlreadyExists) {
+		repo, err = git.PlainOpen(outDir)
+	}
+
 	util.Fatal(err, "init git repo: %s", outDir)
 
 	w, err := repo.Worktree()
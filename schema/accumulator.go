@@ -0,0 +1,205 @@
+// Copyright 2022-2023 Tigris Data, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package schema
+
+import (
+	"encoding/json"
+	"fmt"
+	"sync"
+
+	"github.com/rs/zerolog/log"
+	"github.com/tigrisdata/tigris-cli/util"
+	"github.com/tigrisdata/tigris-client-go/schema"
+)
+
+var (
+	// MaxFields is the number of top level fields of inferred schema,
+	// exceeding which is reported as a warning, or as an error in strict mode.
+	// Zero means no limit.
+	MaxFields int
+
+	ErrTooManyFields = fmt.Errorf("inferred schema has too many top level fields. " +
+		"this usually means that the documents should be modeled differently")
+)
+
+// Accumulator accumulates the schema inferred across the batches of a single import run.
+// It's safe for concurrent use.
+type Accumulator struct {
+	mu  sync.Mutex
+	sch schema.Schema
+
+	secondaryIndex []string
+	searchOptions  *SearchOptions
+	enums          *enumSampler
+
+	maxFieldsWarned bool
+}
+
+func NewAccumulator() *Accumulator {
+	return &Accumulator{}
+}
+
+// Load initializes accumulated schema from the schema of existing collection or index.
+func (a *Accumulator) Load(b []byte) error {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	return json.Unmarshal(b, &a.sch)
+}
+
+// Infer extends accumulated schema with the schema of the docs and returns marshalled result.
+func (a *Accumulator) Infer(name string, docs []json.RawMessage, primaryKey []string, autoGenerate []string,
+	depth int,
+) ([]byte, error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	if err := Infer(&a.sch, name, docs, primaryKey, autoGenerate, depth); err != nil {
+		return nil, err
+	}
+
+	// the index of the field, which is not in the documents seen so far,
+	// is set by the subsequent batches, once the field appears
+	if missing := SetSecondaryIndex(&a.sch, a.secondaryIndex); len(missing) > 0 {
+		log.Debug().Strs("fields", missing).Msg("secondary index fields not found in the schema yet")
+	}
+
+	if err := SetSearchOptions(&a.sch, a.searchOptions); err != nil {
+		return nil, err
+	}
+
+	if err := a.checkMaxFields(); err != nil {
+		return nil, err
+	}
+
+	if !DetectEnums {
+		return json.Marshal(&a.sch)
+	}
+
+	if a.enums == nil {
+		a.enums = newEnumSampler()
+	}
+
+	if err := a.enums.sample(docs, depth); err != nil {
+		return nil, err
+	}
+
+	b, err := json.Marshal(&a.sch)
+	if err != nil {
+		return nil, err
+	}
+
+	return a.enums.apply(b)
+}
+
+// checkMaxFields warns once, when the number of top level fields exceeds MaxFields.
+func (a *Accumulator) checkMaxFields() error {
+	if MaxFields <= 0 || len(a.sch.Fields) <= MaxFields || a.maxFieldsWarned {
+		return nil
+	}
+
+	err := util.Warning(fmt.Errorf("%w: %d, limit: %d", ErrTooManyFields, len(a.sch.Fields), MaxFields))
+	if err != nil {
+		return err
+	}
+
+	a.maxFieldsWarned = true
+
+	return nil
+}
+
+// SetSecondaryIndex sets the fields to be marked as indexed in the inferred schema.
+func (a *Accumulator) SetSecondaryIndex(fields []string) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	a.secondaryIndex = fields
+}
+
+// SetSearchOptions sets the fields to be marked as facetable, searchable or sortable
+// in the inferred search index schema.
+func (a *Accumulator) SetSearchOptions(opts *SearchOptions) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	a.searchOptions = opts
+}
+
+// CoerceDocs converts the values of the documents to the types of the fields of accumulated schema,
+// when CoerceValues is enabled. Documents which can't be converted are passed to skip and removed,
+// or error is returned, when skip is nil.
+func (a *Accumulator) CoerceDocs(docs []json.RawMessage, skip func(json.RawMessage, error) error,
+) ([]json.RawMessage, error) {
+	if !CoerceValues {
+		return docs, nil
+	}
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	res := docs[:0]
+
+	for _, doc := range docs {
+		c, err := Coerce(&a.sch, doc)
+		if err != nil {
+			if skip == nil {
+				return nil, err
+			}
+
+			if err = skip(doc, err); err != nil {
+				return nil, err
+			}
+
+			continue
+		}
+
+		res = append(res, c)
+	}
+
+	return res, nil
+}
+
+// EncodeBytes validates the values of the byte fields of the documents of accumulated schema
+// and converts them to base64, when ByteEncoding is set.
+// The documents are not modified, the converted documents are returned.
+func (a *Accumulator) EncodeBytes(docs []json.RawMessage) ([]json.RawMessage, error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	if ByteEncoding == "" || !hasByteFields(a.sch.Fields) {
+		return docs, nil
+	}
+
+	res := make([]json.RawMessage, 0, len(docs))
+
+	for _, doc := range docs {
+		c, err := EncodeBytes(&a.sch, doc)
+		if err != nil {
+			return nil, err
+		}
+
+		res = append(res, c)
+	}
+
+	return res, nil
+}
+
+// GenerateInitDoc generates init document from accumulated schema.
+func (a *Accumulator) GenerateInitDoc(doc json.RawMessage) ([]byte, error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	return GenerateInitDoc(&a.sch, doc)
+}
@@ -0,0 +1,124 @@
+// Copyright 2022-2023 Tigris Data, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package schema
+
+import (
+	"encoding/json"
+	"fmt"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tigrisdata/tigris-cli/util"
+	"github.com/tigrisdata/tigris-client-go/schema"
+)
+
+func TestAccumulatorConcurrent(t *testing.T) {
+	acc := NewAccumulator()
+
+	require.NoError(t, acc.Load([]byte(`{"title":"coll","properties":{"existing":{"type":"boolean"}}}`)))
+
+	var wg sync.WaitGroup
+
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+
+		go func(i int) {
+			defer wg.Done()
+
+			doc := json.RawMessage(fmt.Sprintf(`{"field_%d": %d}`, i, i))
+
+			_, err := acc.Infer("coll", []json.RawMessage{doc}, nil, nil, 0)
+			assert.NoError(t, err)
+		}(i)
+	}
+
+	wg.Wait()
+
+	b, err := acc.Infer("coll", nil, nil, nil, 0)
+	require.NoError(t, err)
+
+	var sch schema.Schema
+
+	require.NoError(t, json.Unmarshal(b, &sch))
+
+	assert.Len(t, sch.Fields, 11)
+	assert.Equal(t, typeBoolean, sch.Fields["existing"].Type.First())
+
+	for i := 0; i < 10; i++ {
+		assert.Equal(t, typeInteger, sch.Fields[fmt.Sprintf("field_%d", i)].Type.First())
+	}
+}
+
+func TestAccumulatorSecondaryIndex(t *testing.T) {
+	acc := NewAccumulator()
+	acc.SetSecondaryIndex([]string{"name", "address.city"})
+
+	// fields missing in the first batch don't fail the inference
+	b, err := acc.Infer("coll", []json.RawMessage{json.RawMessage(`{"id":1}`)}, []string{"id"}, nil, 0)
+	require.NoError(t, err)
+
+	var first schema.Schema
+
+	require.NoError(t, json.Unmarshal(b, &first))
+	assert.Nil(t, first.Fields["name"])
+
+	// and are indexed once they appear
+	_, err = acc.Infer("coll", []json.RawMessage{
+		json.RawMessage(`{"id":1, "name":"a", "address":{"city":"b", "street":"c"}}`),
+	}, []string{"id"}, nil, 0)
+	require.NoError(t, err)
+
+	// index is preserved when schema evolves
+	b, err = acc.Infer("coll", []json.RawMessage{json.RawMessage(`{"id":2, "name":"d", "age":3}`)}, []string{"id"}, nil, 0)
+	require.NoError(t, err)
+
+	var sch schema.Schema
+
+	require.NoError(t, json.Unmarshal(b, &sch))
+
+	assert.True(t, sch.Fields["name"].Index)
+	assert.True(t, sch.Fields["address"].Fields["city"].Index)
+	assert.False(t, sch.Fields["address"].Fields["street"].Index)
+	assert.False(t, sch.Fields["age"].Index)
+}
+
+func TestAccumulatorMaxFields(t *testing.T) {
+	MaxFields = 2
+
+	defer func() {
+		MaxFields = 0
+		util.Strict = false
+	}()
+
+	acc := NewAccumulator()
+
+	_, err := acc.Infer("coll", []json.RawMessage{json.RawMessage(`{"a":1, "b":2}`)}, nil, nil, 0)
+	require.NoError(t, err)
+
+	// only warns in non-strict mode
+	_, err = acc.Infer("coll", []json.RawMessage{json.RawMessage(`{"c":{"d":1, "e":2, "f":3}}`)}, nil, nil, 0)
+	require.NoError(t, err)
+
+	util.Strict = true
+	acc = NewAccumulator()
+
+	_, err = acc.Infer("coll", []json.RawMessage{json.RawMessage(`{"a":1, "b":{"c":1, "d":2, "e":3}}`)}, nil, nil, 0)
+	require.NoError(t, err)
+
+	_, err = acc.Infer("coll", []json.RawMessage{json.RawMessage(`{"f":1}`)}, nil, nil, 0)
+	require.ErrorIs(t, err, ErrTooManyFields)
+}
@@ -0,0 +1,153 @@
+// Copyright 2022-2023 Tigris Data, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package schema
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+
+	"github.com/tigrisdata/tigris-cli/util"
+	"github.com/tigrisdata/tigris-client-go/schema"
+)
+
+const (
+	ByteEncodingBase64 = "base64"
+	ByteEncodingHex    = "hex"
+)
+
+var (
+	// ByteEncoding is the encoding of the byte array values of the documents.
+	// When set, the values of the byte fields are validated, and hex values are converted
+	// to base64 expected by the server, before insert. Byte fields are detected in this
+	// encoding with --detect-byte-arrays. Empty value is base64 without validation.
+	ByteEncoding string
+
+	ErrInvalidByteEncoding = fmt.Errorf("invalid byte encoding. allowed values: %s, %s",
+		ByteEncodingBase64, ByteEncodingHex)
+	ErrInvalidBytes = fmt.Errorf("invalid byte array value")
+)
+
+func ValidateByteEncoding() error {
+	switch ByteEncoding {
+	case "", ByteEncodingBase64, ByteEncodingHex:
+		return nil
+	}
+
+	return ErrInvalidByteEncoding
+}
+
+// decodeBytes decodes the byte array value in the ByteEncoding.
+func decodeBytes(s string) ([]byte, error) {
+	if ByteEncoding == ByteEncodingHex {
+		return hex.DecodeString(s)
+	}
+
+	return base64.StdEncoding.DecodeString(s)
+}
+
+// isBytes returns true if the string is the byte array value in the ByteEncoding.
+func isBytes(s string) bool {
+	_, err := decodeBytes(s)
+
+	return len(s) != 0 && err == nil
+}
+
+// hasByteFields returns true if the fields or their subfields are byte arrays.
+func hasByteFields(fields map[string]*schema.Field) bool {
+	for _, f := range fields {
+		for f.Items != nil {
+			f = f.Items
+		}
+
+		if f.Format == formatByte || hasByteFields(f.Fields) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// encodeBytesValue validates the value of the byte field and converts it to base64.
+func encodeBytesValue(name string, f *schema.Field, v any) (any, error) {
+	if f == nil || v == nil {
+		return v, nil
+	}
+
+	switch t := v.(type) {
+	case string:
+		if f.Type.First() != typeString || f.Format != formatByte {
+			return v, nil
+		}
+
+		b, err := decodeBytes(t)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %s: expected %s: %s", ErrInvalidBytes, name, ByteEncoding, err.Error())
+		}
+
+		return base64.StdEncoding.EncodeToString(b), nil
+	case map[string]any:
+		return t, encodeBytesFields(name+".", f.Fields, t)
+	case []any:
+		for k := range t {
+			var err error
+
+			if t[k], err = encodeBytesValue(name+"[]", f.Items, t[k]); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	return v, nil
+}
+
+func encodeBytesFields(prefix string, fields map[string]*schema.Field, m map[string]any) error {
+	for k, v := range m {
+		c, err := encodeBytesValue(prefix+k, fields[k], v)
+		if err != nil {
+			return err
+		}
+
+		m[k] = c
+	}
+
+	return nil
+}
+
+// EncodeBytes validates the values of the byte fields of the document
+// and converts them from the ByteEncoding to base64.
+func EncodeBytes(sch *schema.Schema, doc json.RawMessage) (json.RawMessage, error) {
+	var m map[string]any
+
+	dec := json.NewDecoder(bytes.NewReader(doc))
+	dec.UseNumber()
+
+	if err := dec.Decode(&m); err != nil {
+		return nil, err
+	}
+
+	if err := encodeBytesFields("", sch.Fields, m); err != nil {
+		return nil, err
+	}
+
+	b, err := json.Marshal(m)
+	if err != nil {
+		return nil, err
+	}
+
+	return util.KeepOrder(doc, b)
+}
@@ -0,0 +1,77 @@
+// Copyright 2022-2023 Tigris Data, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package schema
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestByteEncoding(t *testing.T) {
+	defer func() {
+		ByteEncoding = ""
+		DetectByteArrays = false
+	}()
+
+	for _, v := range []string{"", ByteEncodingBase64, ByteEncodingHex} {
+		ByteEncoding = v
+		require.NoError(t, ValidateByteEncoding())
+	}
+
+	ByteEncoding = "base32"
+	require.ErrorIs(t, ValidateByteEncoding(), ErrInvalidByteEncoding)
+
+	ByteEncoding = ByteEncodingHex
+	DetectByteArrays = true
+
+	a := NewAccumulator()
+
+	_, err := a.Infer("coll", []json.RawMessage{
+		[]byte(`{"id":1,"data":"deadbeef","name":"not hex","nested":{"list":["cafe"]}}`),
+	}, nil, nil, 0)
+	require.NoError(t, err)
+
+	assert.Equal(t, formatByte, a.sch.Fields["data"].Format)
+	assert.Equal(t, "", a.sch.Fields["name"].Format)
+	assert.Equal(t, formatByte, a.sch.Fields["nested"].Fields["list"].Items.Format)
+
+	docs := []json.RawMessage{[]byte(`{"id":1,"data":"deadbeef","name":"x","nested":{"list":["cafe",null]}}`)}
+
+	res, err := a.EncodeBytes(docs)
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"id":1,"data":"3q2+7w==","name":"x","nested":{"list":["yv4=",null]}}`, string(res[0]))
+	assert.JSONEq(t, `{"id":1,"data":"deadbeef","name":"x","nested":{"list":["cafe",null]}}`, string(docs[0]))
+
+	_, err = a.EncodeBytes([]json.RawMessage{[]byte(`{"data":"xyz"}`)})
+	require.ErrorIs(t, err, ErrInvalidBytes)
+
+	ByteEncoding = ByteEncodingBase64
+
+	res, err = a.EncodeBytes([]json.RawMessage{[]byte(`{"data":"3q2+7w=="}`)})
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"data":"3q2+7w=="}`, string(res[0]))
+
+	_, err = a.EncodeBytes([]json.RawMessage{[]byte(`{"data":"!!"}`)})
+	require.ErrorIs(t, err, ErrInvalidBytes)
+
+	ByteEncoding = ""
+
+	res, err = a.EncodeBytes([]json.RawMessage{[]byte(`{"data":"!!"}`)})
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"data":"!!"}`, string(res[0]))
+}
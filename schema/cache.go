@@ -0,0 +1,138 @@
+// Copyright 2022-2023 Tigris Data, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package schema
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/rs/zerolog/log"
+)
+
+var (
+	// Cache enables caching of the schemas of the collections and the indexes,
+	// fetched from the server, on disk, so repeated imports skip describe request.
+	Cache bool
+	// CacheTTL is the time the cached schema is used, before it's fetched from the server again.
+	CacheTTL = 10 * time.Minute
+)
+
+// cacheEntry is the content of the schema cache file.
+type cacheEntry struct {
+	FetchedAt time.Time       `json:"fetched_at"`
+	Schema    json.RawMessage `json:"schema"`
+}
+
+// cachePath returns the path of the cache file of the schema identified by the key,
+// which is the kind of the schema, the server URL, the project and the name of the collection or the index.
+func cachePath(key []string) (string, error) {
+	dir, err := os.UserCacheDir()
+	if err != nil {
+		return "", err
+	}
+
+	h := sha256.Sum256([]byte(strings.Join(key, "\x00")))
+
+	return filepath.Join(dir, "tigris", "schemas", hex.EncodeToString(h[:])+".json"), nil
+}
+
+// CacheGet returns the cached schema identified by the key.
+// Returns nil if the cache is disabled, or the schema is not cached or expired.
+func CacheGet(key ...string) []byte {
+	if !Cache {
+		return nil
+	}
+
+	path, err := cachePath(key)
+	if err != nil {
+		log.Debug().Err(err).Msg("schema cache path")
+		return nil
+	}
+
+	b, err := os.ReadFile(path)
+	if err != nil {
+		if !errors.Is(err, os.ErrNotExist) {
+			log.Debug().Err(err).Str("path", path).Msg("read schema cache")
+		}
+
+		return nil
+	}
+
+	var e cacheEntry
+
+	if err = json.Unmarshal(b, &e); err != nil {
+		log.Debug().Err(err).Str("path", path).Msg("unmarshal schema cache")
+		return nil
+	}
+
+	if time.Since(e.FetchedAt) > CacheTTL || len(e.Schema) == 0 {
+		log.Debug().Strs("key", key).Time("fetched_at", e.FetchedAt).Msg("schema cache expired")
+		return nil
+	}
+
+	log.Debug().Strs("key", key).Time("fetched_at", e.FetchedAt).Msg("using cached schema")
+
+	return e.Schema
+}
+
+// CachePut caches the schema identified by the key.
+// Failure to write the cache is not fatal, the schema is fetched from the server next time.
+func CachePut(sch []byte, key ...string) {
+	if !Cache {
+		return
+	}
+
+	path, err := cachePath(key)
+	if err != nil {
+		log.Debug().Err(err).Msg("schema cache path")
+		return
+	}
+
+	b, err := json.Marshal(&cacheEntry{FetchedAt: time.Now(), Schema: sch})
+	if err != nil {
+		log.Debug().Err(err).Msg("marshal schema cache")
+		return
+	}
+
+	if err = os.MkdirAll(filepath.Dir(path), 0o700); err == nil {
+		err = os.WriteFile(path, b, 0o600)
+	}
+
+	if err != nil {
+		log.Debug().Err(err).Str("path", path).Msg("write schema cache")
+	}
+}
+
+// CacheInvalidate removes the cached schema identified by the key.
+func CacheInvalidate(key ...string) {
+	if !Cache {
+		return
+	}
+
+	path, err := cachePath(key)
+	if err != nil {
+		return
+	}
+
+	if err = os.Remove(path); err != nil && !errors.Is(err, os.ErrNotExist) {
+		log.Debug().Err(err).Str("path", path).Msg("remove schema cache")
+	}
+}
@@ -0,0 +1,60 @@
+// Copyright 2022-2023 Tigris Data, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package schema
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCache(t *testing.T) {
+	dir := t.TempDir()
+	t.Setenv("XDG_CACHE_HOME", dir)
+	t.Setenv("HOME", dir)
+
+	defer func() {
+		Cache = false
+		CacheTTL = 10 * time.Minute
+	}()
+
+	key := []string{"collection", "localhost:8081", "proj", "users"}
+	sch := []byte(`{"title":"users","properties":{"id":{"type":"integer"}}}`)
+
+	// disabled cache is noop
+	CachePut(sch, key...)
+	assert.Nil(t, CacheGet(key...))
+
+	Cache = true
+
+	assert.Nil(t, CacheGet(key...))
+
+	CachePut(sch, key...)
+	assert.JSONEq(t, string(sch), string(CacheGet(key...)))
+
+	// the key is the project and the collection
+	assert.Nil(t, CacheGet("collection", "localhost:8081", "proj", "orders"))
+	assert.Nil(t, CacheGet("index", "localhost:8081", "proj", "users"))
+
+	CacheTTL = 0
+	assert.Nil(t, CacheGet(key...))
+
+	CacheTTL = time.Minute
+	assert.NotNil(t, CacheGet(key...))
+
+	CacheInvalidate(key...)
+	assert.Nil(t, CacheGet(key...))
+}
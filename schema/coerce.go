@@ -0,0 +1,160 @@
+// Copyright 2022-2023 Tigris Data, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package schema
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/tigrisdata/tigris-cli/util"
+	"github.com/tigrisdata/tigris-client-go/schema"
+)
+
+var (
+	// CoerceValues enables converting the values of the documents to the types of the fields in the schema,
+	// like "42" to 42 for the integer field.
+	CoerceValues bool
+
+	ErrCoerce = fmt.Errorf("value can't be converted to the type of the field")
+)
+
+// coerceValue converts the value to the type of the field.
+// Values of the fields absent in the schema and nulls are returned as is.
+func coerceValue(name string, f *schema.Field, v any) (any, error) {
+	if f == nil || v == nil {
+		return v, nil
+	}
+
+	switch f.Type.First() {
+	case typeInteger:
+		return coerceInteger(name, v)
+	case typeNumber:
+		return coerceNumber(name, v)
+	case typeBoolean:
+		if s, ok := v.(string); ok {
+			b, err := strconv.ParseBool(strings.TrimSpace(s))
+			if err != nil {
+				return nil, fmt.Errorf("%w: %s: %q to %s", ErrCoerce, name, s, typeBoolean)
+			}
+
+			return b, nil
+		}
+	case typeString:
+		if f.Format != "" {
+			return v, nil
+		}
+
+		switch t := v.(type) {
+		case json.Number:
+			return t.String(), nil
+		case bool:
+			return strconv.FormatBool(t), nil
+		}
+	case typeObject:
+		if m, ok := v.(map[string]any); ok && len(f.Fields) > 0 {
+			return m, coerceFields(name+".", f.Fields, m)
+		}
+	case typeArray:
+		if a, ok := v.([]any); ok && f.Items != nil {
+			for k := range a {
+				var err error
+
+				if a[k], err = coerceValue(name+"[]", f.Items, a[k]); err != nil {
+					return nil, err
+				}
+			}
+		}
+	}
+
+	return v, nil
+}
+
+func coerceInteger(name string, v any) (any, error) {
+	var s string
+
+	switch t := v.(type) {
+	case string:
+		s = strings.TrimSpace(t)
+	case json.Number:
+		s = t.String()
+	default:
+		return v, nil
+	}
+
+	if _, err := strconv.ParseInt(s, 10, 64); err == nil {
+		return json.Number(s), nil
+	}
+
+	// integral floating point values, like 42.0
+	if f, err := strconv.ParseFloat(s, 64); err == nil && f == float64(int64(f)) {
+		return json.Number(strconv.FormatInt(int64(f), 10)), nil
+	}
+
+	return nil, fmt.Errorf("%w: %s: %q to %s", ErrCoerce, name, s, typeInteger)
+}
+
+func coerceNumber(name string, v any) (any, error) {
+	s, ok := v.(string)
+	if !ok {
+		return v, nil
+	}
+
+	s = strings.TrimSpace(s)
+
+	if _, err := strconv.ParseFloat(s, 64); err != nil {
+		return nil, fmt.Errorf("%w: %s: %q to %s", ErrCoerce, name, s, typeNumber)
+	}
+
+	return json.Number(s), nil
+}
+
+func coerceFields(prefix string, fields map[string]*schema.Field, m map[string]any) error {
+	for k, v := range m {
+		c, err := coerceValue(prefix+k, fields[k], v)
+		if err != nil {
+			return err
+		}
+
+		m[k] = c
+	}
+
+	return nil
+}
+
+// Coerce converts the values of the document to the types of the fields of the schema.
+func Coerce(sch *schema.Schema, doc json.RawMessage) (json.RawMessage, error) {
+	var m map[string]any
+
+	dec := json.NewDecoder(bytes.NewReader(doc))
+	dec.UseNumber()
+
+	if err := dec.Decode(&m); err != nil {
+		return nil, err
+	}
+
+	if err := coerceFields("", sch.Fields, m); err != nil {
+		return nil, err
+	}
+
+	b, err := json.Marshal(m)
+	if err != nil {
+		return nil, err
+	}
+
+	return util.KeepOrder(doc, b)
+}
@@ -0,0 +1,77 @@
+// Copyright 2022-2023 Tigris Data, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package schema
+
+import (
+	"encoding/json"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCoerceDocs(t *testing.T) {
+	CoerceValues = true
+
+	defer func() { CoerceValues = false }()
+
+	acc := NewAccumulator()
+
+	require.NoError(t, acc.Load([]byte(`{"title":"coll","properties":{
+		"i":{"type":"integer"},
+		"n":{"type":"number"},
+		"b":{"type":"boolean"},
+		"s":{"type":"string"},
+		"t":{"type":"string","format":"date-time"},
+		"o":{"type":"object","properties":{"i":{"type":"integer"}}},
+		"a":{"type":"array","items":{"type":"number"}}
+	}}`)))
+
+	docs := []json.RawMessage{
+		json.RawMessage(`{"i":"42","n":"1.5","b":"true","s":7,"t":"2023-01-01T00:00:00Z","o":{"i":" 3 "},` +
+			`"a":["1","2.5",3],"x":"5"}`),
+		json.RawMessage(`{"i":42.0,"s":false,"b":null}`),
+		json.RawMessage(`{"i":"forty two"}`),
+		json.RawMessage(`{"o":{"i":"1.5"}}`),
+	}
+
+	_, err := acc.CoerceDocs(append([]json.RawMessage{}, docs...), nil)
+	require.ErrorIs(t, err, ErrCoerce)
+	assert.Equal(t, `value can't be converted to the type of the field: i: "forty two" to integer`, err.Error())
+
+	var skipped []string
+
+	res, err := acc.CoerceDocs(docs, func(doc json.RawMessage, err error) error {
+		skipped = append(skipped, fmt.Sprintf("%s: %s", doc, err))
+		return nil
+	})
+	require.NoError(t, err)
+
+	require.Len(t, res, 2)
+	assert.JSONEq(t, `{"i":42,"n":1.5,"b":true,"s":"7","t":"2023-01-01T00:00:00Z","o":{"i":3},"a":[1,2.5,3],"x":"5"}`,
+		string(res[0]))
+	assert.JSONEq(t, `{"i":42,"s":"false","b":null}`, string(res[1]))
+	assert.Equal(t, []string{
+		`{"i":"forty two"}: value can't be converted to the type of the field: i: "forty two" to integer`,
+		`{"o":{"i":"1.5"}}: value can't be converted to the type of the field: o.i: "1.5" to integer`,
+	}, skipped)
+
+	CoerceValues = false
+
+	res, err = acc.CoerceDocs([]json.RawMessage{json.RawMessage(`{"i":"42"}`)}, nil)
+	require.NoError(t, err)
+	assert.Equal(t, `{"i":"42"}`, string(res[0]))
+}
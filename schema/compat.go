@@ -0,0 +1,248 @@
+// Copyright 2022-2023 Tigris Data, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package schema
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/tigrisdata/tigris-cli/util"
+	"github.com/tigrisdata/tigris-client-go/schema"
+)
+
+// Compatibility classes of the schema change.
+// Backward compatible change allows the new schema to read the data written with the old schema,
+// forward compatible change allows the old schema to read the data written with the new one.
+const (
+	CompatFull     = "full"
+	CompatBackward = "backward"
+	CompatForward  = "forward"
+	CompatBreaking = "breaking"
+)
+
+var (
+	// RequireCompat refuses to apply the evolved schema,
+	// which is not compatible in the required direction: backward, forward or full.
+	RequireCompat string
+
+	ErrInvalidRequireCompat = fmt.Errorf("invalid --require-compat value. allowed values: backward, forward, full")
+	ErrSchemaNotCompatible  = fmt.Errorf("schema change is not compatible")
+)
+
+// SchemaChange is a single change of the field between the old and the new schemas.
+type SchemaChange struct {
+	Field  string
+	Change string
+	Compat string
+}
+
+func (c SchemaChange) String() string {
+	return fmt.Sprintf("%s: %s (%s)", c.Field, c.Change, c.Compat)
+}
+
+// ValidateRequireCompat checks that RequireCompat is one of the allowed values.
+func ValidateRequireCompat() error {
+	switch RequireCompat {
+	case "", CompatBackward, CompatForward, CompatFull:
+		return nil
+	default:
+		return fmt.Errorf("%w: %s", ErrInvalidRequireCompat, RequireCompat)
+	}
+}
+
+// fieldType returns the type of the field qualified with the format, like string:date-time.
+func fieldType(f *schema.Field) string {
+	if f.Format == "" {
+		return f.Type.First()
+	}
+
+	return f.Type.First() + ":" + f.Format
+}
+
+// typeChangeCompat classifies the change of the type of the field.
+// Widening, like integer to number or date-time to string, keeps reading the old data, so it's backward compatible,
+// narrowing is forward compatible, any other type change is breaking.
+func typeChangeCompat(oldType string, newType string) string {
+	wider := func(narrow string, wide string) bool {
+		return narrow == typeInteger && wide == typeNumber ||
+			strings.HasPrefix(narrow, typeString+":") && wide == typeString
+	}
+
+	switch {
+	case wider(oldType, newType):
+		return CompatBackward
+	case wider(newType, oldType):
+		return CompatForward
+	default:
+		return CompatBreaking
+	}
+}
+
+func contains(list []string, s string) bool {
+	for _, v := range list {
+		if v == s {
+			return true
+		}
+	}
+
+	return false
+}
+
+func compareFields(prefix string, oldFields map[string]*schema.Field, oldRequired []string,
+	newFields map[string]*schema.Field, newRequired []string,
+) []SchemaChange {
+	names := make([]string, 0, len(oldFields)+len(newFields))
+
+	for name := range oldFields {
+		names = append(names, name)
+	}
+
+	for name := range newFields {
+		if oldFields[name] == nil {
+			names = append(names, name)
+		}
+	}
+
+	sort.Strings(names)
+
+	var changes []SchemaChange
+
+	for _, name := range names {
+		path := prefix + name
+		of, nf := oldFields[name], newFields[name]
+
+		switch {
+		case of == nil && contains(newRequired, name):
+			changes = append(changes, SchemaChange{path, "required field added", CompatForward})
+		case of == nil:
+			changes = append(changes, SchemaChange{path, "field added", CompatFull})
+		case nf == nil:
+			changes = append(changes, SchemaChange{path, "field removed", CompatBackward})
+		default:
+			changes = append(changes, compareField(path, of, nf)...)
+
+			if !contains(oldRequired, name) && contains(newRequired, name) {
+				changes = append(changes, SchemaChange{path, "field became required", CompatForward})
+			} else if contains(oldRequired, name) && !contains(newRequired, name) {
+				changes = append(changes, SchemaChange{path, "field became optional", CompatBackward})
+			}
+		}
+	}
+
+	return changes
+}
+
+func compareField(path string, of *schema.Field, nf *schema.Field) []SchemaChange {
+	if ot, nt := fieldType(of), fieldType(nf); ot != nt {
+		return []SchemaChange{{path, fmt.Sprintf("type changed from %s to %s", ot, nt), typeChangeCompat(ot, nt)}}
+	}
+
+	switch of.Type.First() {
+	case typeObject:
+		return compareFields(path+".", of.Fields, of.Required, nf.Fields, nf.Required)
+	case typeArray:
+		if of.Items != nil && nf.Items != nil {
+			return compareField(path+"[]", of.Items, nf.Items)
+		}
+	}
+
+	return nil
+}
+
+// mergeCompat returns the compatibility of the schema change consisting of the changes of both classes.
+func mergeCompat(a string, b string) string {
+	switch {
+	case a == b || b == CompatFull:
+		return a
+	case a == CompatFull:
+		return b
+	default:
+		return CompatBreaking
+	}
+}
+
+// CompareSchemas classifies the change from the old to the new schema
+// and returns the compatibility class with the changes of the fields.
+func CompareSchemas(oldSchema []byte, newSchema []byte) (string, []SchemaChange, error) {
+	var o, n schema.Schema
+
+	if err := json.Unmarshal(oldSchema, &o); err != nil {
+		return "", nil, err
+	}
+
+	if err := json.Unmarshal(newSchema, &n); err != nil {
+		return "", nil, err
+	}
+
+	changes := compareFields("", o.Fields, o.Required, n.Fields, n.Required)
+
+	if strings.Join(o.PrimaryKey, ",") != strings.Join(n.PrimaryKey, ",") {
+		changes = append(changes, SchemaChange{
+			"primary_key",
+			fmt.Sprintf("changed from %v to %v", o.PrimaryKey, n.PrimaryKey),
+			CompatBreaking,
+		})
+	}
+
+	compat := CompatFull
+	for _, c := range changes {
+		compat = mergeCompat(compat, c.Compat)
+	}
+
+	return compat, changes, nil
+}
+
+func compatString(compat string) string {
+	switch compat {
+	case CompatFull:
+		return "fully compatible"
+	case CompatBreaking:
+		return "breaking"
+	default:
+		return compat + " compatible"
+	}
+}
+
+// CheckCompat classifies the change from the old to the new schema, when RequireCompat is set,
+// reports the classification and returns error, if the change is not compatible as required.
+// Nothing is checked when there is no old schema, like for the collection created by the import.
+func CheckCompat(name string, oldSchema []byte, newSchema []byte) error {
+	if RequireCompat == "" || len(oldSchema) == 0 {
+		return nil
+	}
+
+	compat, changes, err := CompareSchemas(oldSchema, newSchema)
+	if err != nil {
+		return util.Error(err, "compare schemas")
+	}
+
+	if len(changes) == 0 {
+		return nil
+	}
+
+	util.Stderrf("Schema change of %s is %s:\n", name, compatString(compat))
+
+	for _, c := range changes {
+		util.Stderrf("  %s\n", c)
+	}
+
+	if compat == RequireCompat || compat == CompatFull {
+		return nil
+	}
+
+	return fmt.Errorf("%w: %s, required: %s", ErrSchemaNotCompatible, compat, RequireCompat)
+}
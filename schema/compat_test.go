@@ -0,0 +1,137 @@
+// Copyright 2022-2023 Tigris Data, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package schema
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCompareSchemas(t *testing.T) {
+	base := `{"title":"c","properties":{
+		"id":{"type":"integer"},
+		"age":{"type":"integer"},
+		"created":{"type":"string","format":"date-time"},
+		"addr":{"type":"object","properties":{"city":{"type":"string"}}},
+		"tags":{"type":"array","items":{"type":"integer"}}
+	},"primary_key":["id"]}`
+
+	cases := []struct {
+		name    string
+		schema  string
+		compat  string
+		changes []string
+	}{
+		{"unchanged", base, CompatFull, nil},
+		{
+			"optional field added",
+			`{"title":"c","properties":{
+				"id":{"type":"integer"},
+				"age":{"type":"integer"},
+				"created":{"type":"string","format":"date-time"},
+				"addr":{"type":"object","properties":{"city":{"type":"string"},"zip":{"type":"string"}}},
+				"tags":{"type":"array","items":{"type":"integer"}},
+				"name":{"type":"string"}
+			},"primary_key":["id"]}`,
+			CompatFull,
+			[]string{"addr.zip: field added (full)", "name: field added (full)"},
+		},
+		{
+			"widened",
+			`{"title":"c","properties":{
+				"id":{"type":"integer"},
+				"age":{"type":"number"},
+				"created":{"type":"string"},
+				"addr":{"type":"object","properties":{"city":{"type":"string"}}},
+				"tags":{"type":"array","items":{"type":"number"}}
+			},"primary_key":["id"]}`,
+			CompatBackward,
+			[]string{
+				"age: type changed from integer to number (backward)",
+				"created: type changed from string:date-time to string (backward)",
+				"tags[]: type changed from integer to number (backward)",
+			},
+		},
+		{
+			"required field added",
+			`{"title":"c","properties":{
+				"id":{"type":"integer"},
+				"age":{"type":"integer"},
+				"created":{"type":"string","format":"date-time"},
+				"addr":{"type":"object","properties":{"city":{"type":"string"}}},
+				"tags":{"type":"array","items":{"type":"integer"}},
+				"name":{"type":"string"}
+			},"primary_key":["id"],"required":["name"]}`,
+			CompatForward,
+			[]string{"name: required field added (forward)"},
+		},
+		{
+			"breaking",
+			`{"title":"c","properties":{
+				"id":{"type":"integer"},
+				"age":{"type":"string"},
+				"created":{"type":"string","format":"date-time"},
+				"addr":{"type":"object","properties":{"city":{"type":"string"}}},
+				"tags":{"type":"array","items":{"type":"integer"}}
+			},"primary_key":["id","age"]}`,
+			CompatBreaking,
+			[]string{
+				"age: type changed from integer to string (breaking)",
+				"primary_key: changed from [id] to [id age] (breaking)",
+			},
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			compat, changes, err := CompareSchemas([]byte(base), []byte(c.schema))
+			require.NoError(t, err)
+			assert.Equal(t, c.compat, compat)
+
+			var res []string
+			for _, ch := range changes {
+				res = append(res, ch.String())
+			}
+
+			assert.Equal(t, c.changes, res)
+		})
+	}
+}
+
+func TestCheckCompat(t *testing.T) {
+	defer func() { RequireCompat = "" }()
+
+	oldSchema := []byte(`{"title":"c","properties":{"n":{"type":"integer"}}}`)
+	widened := []byte(`{"title":"c","properties":{"n":{"type":"number"}}}`)
+
+	RequireCompat = "sideways"
+	require.ErrorIs(t, ValidateRequireCompat(), ErrInvalidRequireCompat)
+
+	RequireCompat = ""
+	require.NoError(t, CheckCompat("c", oldSchema, []byte(`{"title":"c","properties":{"n":{"type":"string"}}}`)))
+
+	RequireCompat = CompatBackward
+	require.NoError(t, ValidateRequireCompat())
+	require.NoError(t, CheckCompat("c", nil, widened), "new collection")
+	require.NoError(t, CheckCompat("c", oldSchema, widened))
+
+	RequireCompat = CompatForward
+	require.ErrorIs(t, CheckCompat("c", oldSchema, widened), ErrSchemaNotCompatible)
+
+	RequireCompat = CompatFull
+	require.ErrorIs(t, CheckCompat("c", oldSchema, widened), ErrSchemaNotCompatible)
+}
@@ -0,0 +1,57 @@
+// Copyright 2022-2023 Tigris Data, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package schema
+
+import (
+	"bytes"
+	"encoding/json"
+)
+
+// CollectionDescription is the human-readable description of the collection,
+// set in the schema of the collection created by the import.
+var CollectionDescription string
+
+// WithDescription sets the description of the collection in the schema.
+func WithDescription(b []byte) ([]byte, error) {
+	if CollectionDescription == "" {
+		return b, nil
+	}
+
+	var sch map[string]any
+
+	dec := json.NewDecoder(bytes.NewReader(b))
+	dec.UseNumber()
+
+	if err := dec.Decode(&sch); err != nil {
+		return nil, err
+	}
+
+	sch["description"] = CollectionDescription
+
+	return json.Marshal(sch)
+}
+
+// Description returns the description of the collection from the schema.
+func Description(b []byte) (string, error) {
+	var sch struct {
+		Description string `json:"description"`
+	}
+
+	if err := json.Unmarshal(b, &sch); err != nil {
+		return "", err
+	}
+
+	return sch.Description, nil
+}
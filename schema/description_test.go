@@ -0,0 +1,43 @@
+// Copyright 2022-2023 Tigris Data, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package schema
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWithDescription(t *testing.T) {
+	in := []byte(`{"title":"coll1","properties":{"id":{"type":"integer"}}}`)
+
+	b, err := WithDescription(in)
+	require.NoError(t, err)
+	assert.Equal(t, in, b)
+
+	CollectionDescription = "Users imported from CRM"
+
+	defer func() { CollectionDescription = "" }()
+
+	b, err = WithDescription(in)
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"title":"coll1","description":"Users imported from CRM","properties":{"id":{"type":"integer"}}}`,
+		string(b))
+
+	d, err := Description(b)
+	require.NoError(t, err)
+	assert.Equal(t, "Users imported from CRM", d)
+}
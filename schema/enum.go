@@ -0,0 +1,154 @@
+// Copyright 2022-2023 Tigris Data, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package schema
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"sort"
+)
+
+var (
+	// DetectEnums enables emitting of the enum constraint for the string fields
+	// with at most EnumThreshold distinct values sampled.
+	DetectEnums bool
+	// EnumThreshold is the maximum number of distinct values of the enum field.
+	EnumThreshold = 10
+
+	ErrEnumThreshold = fmt.Errorf("--enum-threshold should be positive")
+)
+
+// ValidateEnums checks that the enum detection options are consistent.
+func ValidateEnums() error {
+	if DetectEnums && EnumThreshold < 1 {
+		return ErrEnumThreshold
+	}
+
+	return nil
+}
+
+// enumSampler collects distinct values of the string fields of the documents.
+// Fields are identified by the dot separated path, fields of arrays are not sampled.
+type enumSampler struct {
+	values   map[string]map[string]struct{}
+	exceeded map[string]bool
+}
+
+func newEnumSampler() *enumSampler {
+	return &enumSampler{values: make(map[string]map[string]struct{}), exceeded: make(map[string]bool)}
+}
+
+func (e *enumSampler) sample(docs []json.RawMessage, depth int) error {
+	for i := 0; (depth == 0 || i < depth) && i < len(docs); i++ {
+		var m map[string]any
+
+		dec := json.NewDecoder(bytes.NewReader(docs[i]))
+		dec.UseNumber()
+
+		if err := dec.Decode(&m); err != nil {
+			return err
+		}
+
+		e.sampleObject("", m)
+	}
+
+	return nil
+}
+
+func (e *enumSampler) sampleObject(prefix string, m map[string]any) {
+	for k, v := range m {
+		path := prefix + k
+
+		switch val := v.(type) {
+		case string:
+			e.add(path, val)
+		case map[string]any:
+			e.sampleObject(path+".", val)
+		}
+	}
+}
+
+func (e *enumSampler) add(path string, val string) {
+	if e.exceeded[path] {
+		return
+	}
+
+	vals := e.values[path]
+	if vals == nil {
+		vals = make(map[string]struct{})
+		e.values[path] = vals
+	}
+
+	vals[val] = struct{}{}
+
+	if len(vals) > EnumThreshold {
+		e.exceeded[path] = true
+		delete(e.values, path)
+	}
+}
+
+// apply sets the enum constraint of the plain string fields of the marshalled schema,
+// which have sampled values within the threshold.
+func (e *enumSampler) apply(b []byte) ([]byte, error) {
+	if len(e.values) == 0 {
+		return b, nil
+	}
+
+	var sch map[string]any
+
+	dec := json.NewDecoder(bytes.NewReader(b))
+	dec.UseNumber()
+
+	if err := dec.Decode(&sch); err != nil {
+		return nil, err
+	}
+
+	e.applyProperties("", sch)
+
+	return json.Marshal(sch)
+}
+
+func (e *enumSampler) applyProperties(prefix string, obj map[string]any) {
+	props, _ := obj["properties"].(map[string]any)
+
+	for name, v := range props {
+		field, _ := v.(map[string]any)
+		if field == nil {
+			continue
+		}
+
+		path := prefix + name
+
+		if field["type"] == typeObject {
+			e.applyProperties(path+".", field)
+			continue
+		}
+
+		vals := e.values[path]
+		if field["type"] != typeString || field["format"] != nil || len(vals) == 0 {
+			continue
+		}
+
+		enum := make([]string, 0, len(vals))
+		for val := range vals {
+			enum = append(enum, val)
+		}
+
+		sort.Strings(enum)
+
+		field["enum"] = enum
+	}
+}
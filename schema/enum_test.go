@@ -0,0 +1,80 @@
+// Copyright 2022-2023 Tigris Data, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package schema
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDetectEnums(t *testing.T) {
+	DetectEnums = true
+	EnumThreshold = 2
+
+	defer func() {
+		DetectEnums = false
+		EnumThreshold = 10
+	}()
+
+	acc := NewAccumulator()
+
+	_, err := acc.Infer("coll", []json.RawMessage{
+		json.RawMessage(`{"status": "active", "name": "a", "addr": {"country": "US"}, "created": "2023-01-01T00:00:00Z"}`),
+		json.RawMessage(`{"status": "inactive", "name": "b", "addr": {"country": "CA"}}`),
+	}, nil, nil, 0)
+	require.NoError(t, err)
+
+	// values are accumulated across the batches
+	b, err := acc.Infer("coll", []json.RawMessage{
+		json.RawMessage(`{"status": "active", "name": "c", "count": 1}`),
+	}, nil, nil, 0)
+	require.NoError(t, err)
+
+	var sch struct {
+		Properties map[string]struct {
+			Enum       []string `json:"enum"`
+			Properties map[string]struct {
+				Enum []string `json:"enum"`
+			} `json:"properties"`
+		} `json:"properties"`
+	}
+
+	require.NoError(t, json.Unmarshal(b, &sch))
+
+	assert.Equal(t, []string{"active", "inactive"}, sch.Properties["status"].Enum)
+	assert.Equal(t, []string{"CA", "US"}, sch.Properties["addr"].Properties["country"].Enum)
+	assert.Nil(t, sch.Properties["name"].Enum)
+	assert.Nil(t, sch.Properties["created"].Enum)
+	assert.Nil(t, sch.Properties["count"].Enum)
+}
+
+func TestValidateEnums(t *testing.T) {
+	DetectEnums = true
+	EnumThreshold = 0
+
+	defer func() {
+		DetectEnums = false
+		EnumThreshold = 10
+	}()
+
+	require.ErrorIs(t, ValidateEnums(), ErrEnumThreshold)
+
+	EnumThreshold = 1
+
+	require.NoError(t, ValidateEnums())
+}
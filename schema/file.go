@@ -0,0 +1,44 @@
+// Copyright 2022-2023 Tigris Data, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package schema
+
+import (
+	"encoding/json"
+	"os"
+)
+
+// ReadFile reads the schema from the file and sets its title to the given
+// collection or index name, so the same schema file can be used for multiple collections.
+func ReadFile(name string, title string) ([]byte, error) {
+	b, err := os.ReadFile(name)
+	if err != nil {
+		return nil, err
+	}
+
+	return WithTitle(b, title)
+}
+
+// WithTitle sets the title of the schema to the given collection or index name.
+func WithTitle(b []byte, title string) ([]byte, error) {
+	var sch map[string]any
+
+	if err := json.Unmarshal(b, &sch); err != nil {
+		return nil, err
+	}
+
+	sch["title"] = title
+
+	return json.Marshal(sch)
+}
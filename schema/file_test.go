@@ -0,0 +1,49 @@
+// Copyright 2022-2023 Tigris Data, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package schema
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReadFile(t *testing.T) {
+	name := filepath.Join(t.TempDir(), "schema.json")
+
+	err := os.WriteFile(name, []byte(`{
+		"title": "template",
+		"properties": { "id": { "type": "integer" } },
+		"primary_key": ["id"]
+	}`), 0o600)
+	require.NoError(t, err)
+
+	b, err := ReadFile(name, "users")
+	require.NoError(t, err)
+
+	assert.JSONEq(t, `{
+		"title": "users",
+		"properties": { "id": { "type": "integer" } },
+		"primary_key": ["id"]
+	}`, string(b))
+
+	require.NoError(t, os.WriteFile(name, []byte(`not a json`), 0o600))
+
+	_, err = ReadFile(name, "users")
+	require.Error(t, err)
+}
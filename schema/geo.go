@@ -0,0 +1,143 @@
+// Copyright 2022-2023 Tigris Data, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package schema
+
+import (
+	"encoding/json"
+
+	"github.com/tigrisdata/tigris-client-go/schema"
+)
+
+const (
+	geoType        = "type"
+	geoCoordinates = "coordinates"
+	geoBBox        = "bbox"
+)
+
+// Nesting depth of the coordinates arrays of GeoJSON geometry types.
+var geoDepth = map[string]int{
+	"Point":           1,
+	"LineString":      2,
+	"MultiPoint":      2,
+	"Polygon":         3,
+	"MultiLineString": 3,
+	"MultiPolygon":    4,
+}
+
+// isCoordinates checks that v is the array of numbers nested to the given depth.
+func isCoordinates(v any, depth int) bool {
+	if depth == 0 {
+		_, ok := v.(json.Number)
+		return ok
+	}
+
+	arr, ok := v.([]any)
+	if !ok || len(arr) == 0 {
+		return false
+	}
+
+	for _, e := range arr {
+		if !isCoordinates(e, depth-1) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// geoJSONDepth returns the coordinates depth if the value is GeoJSON geometry object,
+// or zero otherwise.
+func geoJSONDepth(v any) int {
+	m, ok := v.(map[string]any)
+	if !ok {
+		return 0
+	}
+
+	for k := range m {
+		if k != geoType && k != geoCoordinates && k != geoBBox {
+			return 0
+		}
+	}
+
+	tp, _ := m[geoType].(string)
+
+	depth := geoDepth[tp]
+	if depth == 0 || !isCoordinates(m[geoCoordinates], depth) {
+		return 0
+	}
+
+	return depth
+}
+
+func geoCoordinatesField(depth int) *schema.Field {
+	if depth == 0 {
+		return &schema.Field{Type: schema.NewMultiType(typeNumber)}
+	}
+
+	return &schema.Field{Type: schema.NewMultiType(typeArray), Items: geoCoordinatesField(depth - 1)}
+}
+
+// fieldGeoDepth returns the coordinates depth if the field has the schema of GeoJSON geometry.
+func fieldGeoDepth(f *schema.Field) int {
+	if f == nil || f.Type.First() != typeObject || f.Fields[geoType] == nil {
+		return 0
+	}
+
+	for k := range f.Fields {
+		if k != geoType && k != geoCoordinates && k != geoBBox {
+			return 0
+		}
+	}
+
+	depth := 0
+
+	for c := f.Fields[geoCoordinates]; c != nil && c.Type.First() == typeArray; c = c.Items {
+		depth++
+	}
+
+	return depth
+}
+
+// geoField returns the schema of GeoJSON geometry field,
+// if the value is GeoJSON geometry object: {"type":"Point","coordinates":[1, 2]}.
+// Coordinates are always typed as numbers, so integer coordinates in the first documents
+// don't narrow the type.
+// Server doesn't have dedicated geo type, so geometry is typed as an object.
+func geoField(name string, v any, existing *schema.Field) (*schema.Field, bool, error) {
+	depth := geoJSONDepth(v)
+	if depth == 0 {
+		return nil, false, nil
+	}
+
+	f := existing
+
+	if f == nil {
+		f = &schema.Field{
+			Type: schema.NewMultiType(typeObject),
+			Fields: map[string]*schema.Field{
+				geoType:        {Type: schema.NewMultiType(typeString)},
+				geoCoordinates: geoCoordinatesField(depth),
+			},
+		}
+	} else if d := fieldGeoDepth(f); d != depth {
+		return nil, false, newInompatibleSchemaError(name, f.Type.First(), f.Format, typeObject, "")
+	}
+
+	if m, _ := v.(map[string]any); m[geoBBox] != nil && f.Fields[geoBBox] == nil {
+		f.Fields[geoBBox] = geoCoordinatesField(1)
+	}
+
+	return f, true, nil
+}
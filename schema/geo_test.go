@@ -0,0 +1,111 @@
+// Copyright 2022-2023 Tigris Data, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package schema
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tigrisdata/tigris-client-go/schema"
+)
+
+func TestDetectGeo(t *testing.T) {
+	DetectGeo = true
+
+	defer func() { DetectGeo = false }()
+
+	cases := []struct {
+		name string
+		in   []string
+		exp  string
+		err  error
+	}{
+		{
+			"point", []string{
+				`{"loc": {"type": "Point", "coordinates": [10, 20]}}`,
+				`{"loc": {"type": "Point", "coordinates": [10.5, 20.1]}}`,
+			},
+			`{"title":"t","properties":{"loc":{"type":"object","properties":{
+				"type":{"type":"string"},
+				"coordinates":{"type":"array","items":{"type":"number"}}
+			}}}}`,
+			nil,
+		},
+		{
+			"polygon", []string{
+				`{"area": {"type": "Polygon", "coordinates": [[[0, 0], [1, 0], [1, 1], [0, 0]]], "bbox": [0, 0, 1, 1]}}`,
+			},
+			`{"title":"t","properties":{"area":{"type":"object","properties":{
+				"type":{"type":"string"},
+				"coordinates":{"type":"array","items":{"type":"array","items":{"type":"array","items":{"type":"number"}}}},
+				"bbox":{"type":"array","items":{"type":"number"}}
+			}}}}`,
+			nil,
+		},
+		{
+			// coordinates of unexpected depth and unknown geometry types aren't detected as geo
+			"not_geo", []string{
+				`{"pt": {"type": "Point", "coordinates": [[10, 20]]}, "sh": {"type": "Circle", "coordinates": [1, 2]},
+				"coords": [10, 20]}`,
+			},
+			`{"title":"t","properties":{
+				"pt":{"type":"object","properties":{
+					"type":{"type":"string"},
+					"coordinates":{"type":"array","items":{"type":"array"}}
+				}},
+				"sh":{"type":"object","properties":{
+					"type":{"type":"string"},
+					"coordinates":{"type":"array","items":{"type":"integer"}}
+				}},
+				"coords":{"type":"array","items":{"type":"integer"}}
+			}}`,
+			nil,
+		},
+		{
+			"mixed_geometries", []string{
+				`{"loc": {"type": "Point", "coordinates": [10, 20]}}`,
+				`{"loc": {"type": "LineString", "coordinates": [[10, 20], [30, 40]]}}`,
+			},
+			"",
+			ErrIncompatibleSchema,
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			var sch schema.Schema
+
+			docs := make([]json.RawMessage, 0, len(c.in))
+			for _, v := range c.in {
+				docs = append(docs, json.RawMessage(v))
+			}
+
+			err := Infer(&sch, "t", docs, nil, nil, 0)
+			if c.err != nil {
+				require.ErrorIs(t, err, c.err)
+				return
+			}
+
+			require.NoError(t, err)
+
+			b, err := json.Marshal(&sch)
+			require.NoError(t, err)
+
+			assert.JSONEq(t, c.exp, string(b))
+		})
+	}
+}
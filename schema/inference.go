@@ -16,10 +16,10 @@ package schema
 
 import (
 	"bytes"
-	"encoding/base64"
 	"encoding/json"
 	"fmt"
 	"reflect"
+	"strings"
 	"time"
 
 	"github.com/google/uuid"
@@ -49,6 +49,7 @@ var (
 	DetectUUIDs      = true
 	DetectTimes      = true
 	DetectIntegers   = true
+	DetectGeo        = false
 
 	ErrIncompatibleSchema = fmt.Errorf("error incompatible schema")
 	ErrExpectedString     = fmt.Errorf("expected string type")
@@ -56,6 +57,10 @@ var (
 	ErrUnsupportedType    = fmt.Errorf("unsupported type")
 
 	HasArrayOfObjects bool
+
+	// DateTimeFields are the string fields which are always inferred as date-time,
+	// regardless of --detect-times. Nested fields are specified using dot notation.
+	DateTimeFields []string
 )
 
 func newInompatibleSchemaError(name, oldType, oldFormat, newType, newFormat string) error {
@@ -114,11 +119,8 @@ func translateStringType(v any, existing *schema.Field) (string, string, error)
 		return typeString, formatUUID, nil
 	}
 
-	if len(s) != 0 && needNarrowing(DetectByteArrays, existing, formatByte) {
-		b := make([]byte, base64.StdEncoding.DecodedLen(len(s)))
-		if _, err := base64.StdEncoding.Decode(b, []byte(s)); err == nil {
-			return typeString, formatByte, nil
-		}
+	if needNarrowing(DetectByteArrays, existing, formatByte) && isBytes(s) {
+		return typeString, formatByte, nil
 	}
 
 	return typeString, "", nil
@@ -325,25 +327,55 @@ func traverseFields(sch map[string]*schema.Field, fields map[string]any, autoGen
 	for name, val := range fields {
 		// handle `null` JSON value
 		if val == nil {
+			trace.field(name, nil, "", "", sch[name], nil, "null value skipped")
 			continue
 		}
 
+		if DetectGeo {
+			gf, ok, err := geoField(name, val, sch[name])
+			if err != nil {
+				trace.field(name, val, "", "", sch[name], nil, err.Error())
+				return err
+			}
+
+			if ok {
+				trace.field(name, val, gf.Type.First(), gf.Format, sch[name], gf, "geojson")
+				sch[name] = gf
+
+				continue
+			}
+		}
+
 		t, format, err := translateType(val, sch[name])
 		if err != nil {
+			trace.field(name, val, "", "", sch[name], nil, err.Error())
 			return err
 		}
 
 		f := &schema.Field{Type: schema.NewMultiType(t), Format: format}
 
+		if t == typeArray {
+			trace.enter(name + "[]")
+		} else {
+			trace.enter(name)
+		}
+
 		skip, err := traverseFieldsLow(t, format, name, f, val, sch)
+
+		trace.leave()
+
 		if err != nil {
+			trace.field(name, val, t, format, sch[name], nil, err.Error())
 			return err
 		}
 
 		if skip {
+			trace.field(name, val, t, format, sch[name], nil, "empty value skipped")
 			continue
 		}
 
+		trace.field(name, val, t, format, sch[name], f, "")
+
 		setAutoGenerate(autoGen, name, f)
 
 		sch[name] = f
@@ -362,6 +394,8 @@ func docToSchema(sch *schema.Schema, name string, data []byte, pk []string, auto
 		return err
 	}
 
+	trace.setCollection(name)
+
 	sch.Name = name
 	if pk != nil {
 		sch.PrimaryKey = pk
@@ -391,13 +425,60 @@ func Infer(sch *schema.Schema, name string, docs []json.RawMessage, primaryKey [
 	for i := 0; (depth == 0 || i < depth) && i < len(docs); i++ {
 		err := docToSchema(sch, name, docs[i], primaryKey, autoGenerate)
 		if err != nil {
+			trace.flush()
 			return err
 		}
 	}
 
+	setDateTimeFields(sch)
+
+	trace.flush()
+
 	return nil
 }
 
+// SetSecondaryIndex marks the fields as indexed.
+// Nested object fields can be specified using dot notation: "address.city".
+// Returns the fields which are not in the schema yet, so as the caller
+// can mark them once the documents containing the fields are inferred.
+func SetSecondaryIndex(sch *schema.Schema, fields []string) []string {
+	var missing []string
+
+	for _, name := range fields {
+		f := lookupField(sch, name)
+		if f == nil {
+			missing = append(missing, name)
+			continue
+		}
+
+		f.Index = true
+	}
+
+	return missing
+}
+
+// setDateTimeFields sets the date-time format of the DateTimeFields string fields of the schema.
+func setDateTimeFields(sch *schema.Schema) {
+	for _, name := range DateTimeFields {
+		if f := lookupField(sch, name); f != nil && f.Type.First() == typeString {
+			f.Format = formatDateTime
+		}
+	}
+}
+
+// lookupField returns the field of the schema by the dot separated path, or nil if it doesn't exist.
+func lookupField(sch *schema.Schema, name string) *schema.Field {
+	path := strings.Split(name, ".")
+
+	f := sch.Fields[path[0]]
+
+	for i := 1; f != nil && i < len(path); i++ {
+		f = f.Fields[path[i]]
+	}
+
+	return f
+}
+
 func GenerateInitDoc(sch *schema.Schema, doc json.RawMessage) ([]byte, error) {
 	if sch.Fields == nil {
 		return nil, nil
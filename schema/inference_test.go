@@ -459,3 +459,27 @@ func TestSchemaTags(t *testing.T) {
 		})
 	}
 }
+
+func TestSchemaInferenceDateTimeFields(t *testing.T) {
+	defer func() {
+		DetectTimes = true
+		DateTimeFields = nil
+	}()
+
+	DetectTimes = false
+	DateTimeFields = []string{"created_at", "meta.updated_at", "id", "missing"}
+
+	var sch schema.Schema
+
+	err := Infer(&sch, "coll", []json.RawMessage{
+		[]byte(`{"id":1,"created_at":"2023-05-06T06:08:09Z","name":"2023-05-06T06:08:09Z",
+"meta":{"updated_at":"2023-05-06T06:08:09Z"}}`),
+	}, nil, nil, 0)
+	require.NoError(t, err)
+
+	assert.Equal(t, formatDateTime, sch.Fields["created_at"].Format)
+	assert.Equal(t, formatDateTime, sch.Fields["meta"].Fields["updated_at"].Format)
+	assert.Equal(t, "", sch.Fields["name"].Format)
+	assert.Equal(t, typeInteger, sch.Fields["id"].Type.First())
+	assert.Equal(t, "", sch.Fields["id"].Format)
+}
@@ -0,0 +1,155 @@
+// Copyright 2022-2023 Tigris Data, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package schema
+
+import (
+	"math"
+
+	"github.com/tigrisdata/tigris-client-go/schema"
+)
+
+const (
+	JSONSchemaDraft = "https://json-schema.org/draft/2020-12/schema"
+
+	formatInt32  = "int32"
+	formatInt64  = "int64"
+	formatVector = "vector"
+
+	encodingBase64 = "base64"
+)
+
+// JSONSchema is a standard JSON Schema document.
+type JSONSchema struct {
+	Schema string `json:"$schema,omitempty"`
+	Title  string `json:"title,omitempty"`
+	Desc   string `json:"description,omitempty"`
+
+	Type            *schema.FieldMultiType `json:"type,omitempty"`
+	Format          string                 `json:"format,omitempty"`
+	ContentEncoding string                 `json:"contentEncoding,omitempty"`
+
+	Minimum   *int64 `json:"minimum,omitempty"`
+	Maximum   *int64 `json:"maximum,omitempty"`
+	MaxLength int    `json:"maxLength,omitempty"`
+	MinItems  int    `json:"minItems,omitempty"`
+	MaxItems  int    `json:"maxItems,omitempty"`
+	Default   any    `json:"default,omitempty"`
+
+	Properties map[string]*JSONSchema `json:"properties,omitempty"`
+	Items      *JSONSchema            `json:"items,omitempty"`
+	Required   []string               `json:"required,omitempty"`
+}
+
+func appendUnique(l []string, values ...string) []string {
+	for _, v := range values {
+		found := false
+
+		for _, e := range l {
+			if e == v {
+				found = true
+				break
+			}
+		}
+
+		if !found {
+			l = append(l, v)
+		}
+	}
+
+	return l
+}
+
+func fieldToJSONSchema(f *schema.Field) *JSONSchema {
+	js := &JSONSchema{
+		Desc:      f.Desc,
+		Format:    f.Format,
+		MaxLength: f.MaxLength,
+		MaxItems:  f.MaxItems,
+		Default:   f.Default,
+		Required:  f.Required,
+	}
+
+	if len(f.Type.Type) > 0 {
+		tp := f.Type
+		js.Type = &tp
+	}
+
+	switch f.Format {
+	case formatByte:
+		js.Format = ""
+		js.ContentEncoding = encodingBase64
+	case formatInt32:
+		js.Format = ""
+		minInt, maxInt := int64(math.MinInt32), int64(math.MaxInt32)
+		js.Minimum, js.Maximum = &minInt, &maxInt
+	case formatInt64:
+		js.Format = ""
+	case formatVector:
+		js.Format = ""
+		js.Items = &JSONSchema{Type: &schema.FieldMultiType{Type: []string{typeNumber}}}
+		js.MinItems, js.MaxItems = f.Dimensions, f.Dimensions
+	}
+
+	if f.Fields != nil {
+		js.Properties = make(map[string]*JSONSchema, len(f.Fields))
+
+		for name, v := range f.Fields {
+			js.Properties[name] = fieldToJSONSchema(v)
+		}
+	}
+
+	if f.Items != nil {
+		js.Items = fieldToJSONSchema(f.Items)
+	}
+
+	return js
+}
+
+// requiredFields returns the names, except the fields autogenerated by the server,
+// which the documents don't have to contain.
+func requiredFields(sch *schema.Schema, names []string) []string {
+	var req []string
+
+	for _, name := range names {
+		if f := sch.Fields[name]; f != nil && f.AutoGenerate {
+			continue
+		}
+
+		req = append(req, name)
+	}
+
+	return req
+}
+
+// ToJSONSchema converts Tigris collection schema to the standard JSON Schema document.
+// Primary key fields are converted to required fields of the top level object,
+// unless they are autogenerated.
+func ToJSONSchema(sch *schema.Schema) *JSONSchema {
+	js := &JSONSchema{
+		Schema:     JSONSchemaDraft,
+		Title:      sch.Name,
+		Desc:       sch.Desc,
+		Type:       &schema.FieldMultiType{Type: []string{typeObject}},
+		Properties: make(map[string]*JSONSchema, len(sch.Fields)),
+		Required: appendUnique(appendUnique(nil, requiredFields(sch, sch.PrimaryKey)...),
+			requiredFields(sch, sch.Required)...),
+	}
+
+	for name, f := range sch.Fields {
+		js.Properties[name] = fieldToJSONSchema(f)
+	}
+
+	return js
+}
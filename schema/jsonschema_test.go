@@ -0,0 +1,162 @@
+// Copyright 2022-2023 Tigris Data, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package schema
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tigrisdata/tigris-client-go/schema"
+)
+
+func TestToJSONSchema(t *testing.T) {
+	cases := []struct {
+		name string
+		in   string
+		exp  string
+	}{
+		{
+			"primitive", `{
+				"title": "coll",
+				"description": "coll desc",
+				"properties": {
+					"id": { "type": "string", "format": "uuid", "autoGenerate": true },
+					"i32": { "type": "integer", "format": "int32" },
+					"i64": { "type": "integer", "format": "int64" },
+					"bin": { "type": "string", "format": "byte" },
+					"time": { "type": ["string", "null"], "format": "date-time" },
+					"str": { "type": "string", "maxLength": 10, "description": "str desc" }
+				},
+				"primary_key": ["id"]
+			}`, `{
+				"$schema": "https://json-schema.org/draft/2020-12/schema",
+				"title": "coll",
+				"description": "coll desc",
+				"type": "object",
+				"properties": {
+					"id": { "type": "string", "format": "uuid" },
+					"i32": { "type": "integer", "minimum": -2147483648, "maximum": 2147483647 },
+					"i64": { "type": "integer" },
+					"bin": { "type": "string", "contentEncoding": "base64" },
+					"time": { "type": ["string", "null"], "format": "date-time" },
+					"str": { "type": "string", "maxLength": 10, "description": "str desc" }
+				}
+			}`,
+		},
+		{
+			"nested_object", `{
+				"title": "coll",
+				"properties": {
+					"obj": {
+						"type": "object",
+						"properties": {
+							"nested": {
+								"type": "object",
+								"properties": { "b": { "type": "boolean" } },
+								"required": ["b"]
+							},
+							"n": { "type": "number" }
+						}
+					}
+				},
+				"primary_key": ["id"],
+				"required": ["obj", "id"]
+			}`, `{
+				"$schema": "https://json-schema.org/draft/2020-12/schema",
+				"title": "coll",
+				"type": "object",
+				"properties": {
+					"obj": {
+						"type": "object",
+						"properties": {
+							"nested": {
+								"type": "object",
+								"properties": { "b": { "type": "boolean" } },
+								"required": ["b"]
+							},
+							"n": { "type": "number" }
+						}
+					}
+				},
+				"required": ["id", "obj"]
+			}`,
+		},
+		{
+			"autogenerated_key", `{
+				"title": "coll",
+				"properties": {
+					"id": { "type": "integer", "autoGenerate": true },
+					"tenant": { "type": "string" }
+				},
+				"primary_key": ["tenant", "id"]
+			}`, `{
+				"$schema": "https://json-schema.org/draft/2020-12/schema",
+				"title": "coll",
+				"type": "object",
+				"properties": {
+					"id": { "type": "integer" },
+					"tenant": { "type": "string" }
+				},
+				"required": ["tenant"]
+			}`,
+		},
+		{
+			"arrays", `{
+				"title": "coll",
+				"properties": {
+					"arr": { "type": "array", "items": { "type": "integer", "format": "int32" }, "maxItems": 5 },
+					"arr_obj": {
+						"type": "array",
+						"items": { "type": "object", "properties": { "s": { "type": "string", "format": "byte" } } }
+					},
+					"arr_arr": { "type": "array", "items": { "type": "array", "items": { "type": "string" } } },
+					"vec": { "type": "array", "format": "vector", "dimensions": 3 }
+				}
+			}`, `{
+				"$schema": "https://json-schema.org/draft/2020-12/schema",
+				"title": "coll",
+				"type": "object",
+				"properties": {
+					"arr": {
+						"type": "array",
+						"items": { "type": "integer", "minimum": -2147483648, "maximum": 2147483647 },
+						"maxItems": 5
+					},
+					"arr_obj": {
+						"type": "array",
+						"items": { "type": "object", "properties": { "s": { "type": "string", "contentEncoding": "base64" } } }
+					},
+					"arr_arr": { "type": "array", "items": { "type": "array", "items": { "type": "string" } } },
+					"vec": { "type": "array", "items": { "type": "number" }, "minItems": 3, "maxItems": 3 }
+				}
+			}`,
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			var sch schema.Schema
+
+			require.NoError(t, json.Unmarshal([]byte(c.in), &sch))
+
+			b, err := json.Marshal(ToJSONSchema(&sch))
+			require.NoError(t, err)
+
+			assert.JSONEq(t, c.exp, string(b))
+		})
+	}
+}
@@ -0,0 +1,79 @@
+// Copyright 2022-2023 Tigris Data, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package schema
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+)
+
+const (
+	PrintSchemaEach  = "each"
+	PrintSchemaFinal = "final"
+)
+
+var (
+	// PrintSchema controls printing of the schema inferred during import to stderr:
+	// "each" - on every schema change, "final" - once, when the import is finished.
+	// Schema is not printed if empty.
+	PrintSchema string
+
+	ErrInvalidPrintSchema = fmt.Errorf("invalid print schema mode. allowed values: %s, %s",
+		PrintSchemaEach, PrintSchemaFinal)
+)
+
+func ValidatePrintSchema() error {
+	switch PrintSchema {
+	case "", PrintSchemaEach, PrintSchemaFinal:
+		return nil
+	}
+
+	return ErrInvalidPrintSchema
+}
+
+func printSchema(w io.Writer, b []byte) error {
+	var buf bytes.Buffer
+
+	if err := json.Indent(&buf, b, "", "  "); err != nil {
+		return err
+	}
+
+	buf.WriteByte('\n')
+
+	_, err := w.Write(buf.Bytes())
+
+	return err
+}
+
+// PrintChanged prints the schema to stderr, if printing of every schema change is requested.
+func PrintChanged(b []byte) error {
+	if PrintSchema != PrintSchemaEach {
+		return nil
+	}
+
+	return printSchema(os.Stderr, b)
+}
+
+// PrintFinal prints the schema to stderr, if printing of the final schema is requested.
+func PrintFinal(b []byte) error {
+	if PrintSchema != PrintSchemaFinal || b == nil {
+		return nil
+	}
+
+	return printSchema(os.Stderr, b)
+}
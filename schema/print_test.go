@@ -0,0 +1,48 @@
+// Copyright 2022-2023 Tigris Data, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package schema
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPrintSchema(t *testing.T) {
+	defer func() { PrintSchema = "" }()
+
+	for _, v := range []string{"", PrintSchemaEach, PrintSchemaFinal} {
+		PrintSchema = v
+		require.NoError(t, ValidatePrintSchema())
+	}
+
+	PrintSchema = "all"
+	require.ErrorIs(t, ValidatePrintSchema(), ErrInvalidPrintSchema)
+
+	var buf bytes.Buffer
+
+	require.NoError(t, printSchema(&buf, []byte(`{"title":"coll","properties":{"id":{"type":"integer"}}}`)))
+	assert.Equal(t, `{
+  "title": "coll",
+  "properties": {
+    "id": {
+      "type": "integer"
+    }
+  }
+}
+`, buf.String())
+}
@@ -0,0 +1,66 @@
+// Copyright 2022-2023 Tigris Data, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package schema
+
+import (
+	"fmt"
+
+	"github.com/tigrisdata/tigris-client-go/schema"
+)
+
+var ErrSearchFieldNotFound = fmt.Errorf("search index option field not found in the schema")
+
+// SearchOptions are the fields of the search index to be marked
+// as facetable, searchable or sortable in the inferred schema.
+// Nested object fields can be specified using dot notation: "address.city".
+type SearchOptions struct {
+	Facet      []string
+	Searchable []string
+	Sort       []string
+}
+
+func (o *SearchOptions) empty() bool {
+	return o == nil || len(o.Facet) == 0 && len(o.Searchable) == 0 && len(o.Sort) == 0
+}
+
+// SetSearchOptions marks the fields of the search index schema according to the options.
+func SetSearchOptions(sch *schema.Schema, opts *SearchOptions) error {
+	if opts.empty() {
+		return nil
+	}
+
+	mark := func(option string, fields []string, set func(f *schema.Field)) error {
+		for _, name := range fields {
+			f := lookupField(sch, name)
+			if f == nil {
+				return fmt.Errorf("%w: --%s=%s", ErrSearchFieldNotFound, option, name)
+			}
+
+			set(f)
+		}
+
+		return nil
+	}
+
+	if err := mark("facet", opts.Facet, func(f *schema.Field) { f.Facet = true }); err != nil {
+		return err
+	}
+
+	if err := mark("searchable", opts.Searchable, func(f *schema.Field) { f.SearchIndex = true }); err != nil {
+		return err
+	}
+
+	return mark("sort", opts.Sort, func(f *schema.Field) { f.Sort = true })
+}
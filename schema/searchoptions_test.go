@@ -0,0 +1,52 @@
+// Copyright 2022-2023 Tigris Data, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package schema
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tigrisdata/tigris-client-go/schema"
+)
+
+func TestAccumulatorSearchOptions(t *testing.T) {
+	acc := NewAccumulator()
+	acc.SetSearchOptions(&SearchOptions{
+		Facet:      []string{"brand"},
+		Searchable: []string{"title", "address.city"},
+		Sort:       []string{"price"},
+	})
+
+	_, err := acc.Infer("idx", []json.RawMessage{json.RawMessage(`{"title":"a"}`)}, nil, nil, 0)
+	require.ErrorIs(t, err, ErrSearchFieldNotFound)
+
+	b, err := acc.Infer("idx", []json.RawMessage{
+		json.RawMessage(`{"title":"a", "brand":"b", "price":1.5, "address":{"city":"c", "street":"d"}}`),
+	}, nil, nil, 0)
+	require.NoError(t, err)
+
+	var sch schema.Schema
+
+	require.NoError(t, json.Unmarshal(b, &sch))
+
+	assert.True(t, sch.Fields["brand"].Facet)
+	assert.True(t, sch.Fields["title"].SearchIndex)
+	assert.True(t, sch.Fields["address"].Fields["city"].SearchIndex)
+	assert.False(t, sch.Fields["address"].Fields["street"].SearchIndex)
+	assert.True(t, sch.Fields["price"].Sort)
+	assert.False(t, sch.Fields["price"].Facet)
+}
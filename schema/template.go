@@ -0,0 +1,98 @@
+// Copyright 2022-2023 Tigris Data, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package schema
+
+import (
+	"encoding/json"
+
+	"github.com/tigrisdata/tigris-client-go/schema"
+)
+
+// Placeholder values of the document template.
+const (
+	templateString   = "string"
+	templateDateTime = "2006-01-02T15:04:05Z"
+	templateUUID     = "00000000-0000-0000-0000-000000000000"
+	templateByte     = "AA=="
+	templateNumber   = json.Number("0.0")
+)
+
+func templateValue(f *schema.Field) any {
+	if f.Default != nil {
+		return f.Default
+	}
+
+	switch f.Type.First() {
+	case typeString:
+		switch f.Format {
+		case formatDateTime:
+			return templateDateTime
+		case formatUUID:
+			return templateUUID
+		case formatByte:
+			return templateByte
+		}
+
+		return templateString
+	case typeInteger:
+		return 0
+	case typeNumber:
+		return templateNumber
+	case typeBoolean:
+		return false
+	case typeObject:
+		return templateFields(f.Fields)
+	case typeArray:
+		if f.Format == formatVector {
+			n := f.Dimensions
+			if n == 0 {
+				n = 1
+			}
+
+			v := make([]any, 0, n)
+			for i := 0; i < n; i++ {
+				v = append(v, templateNumber)
+			}
+
+			return v
+		}
+
+		if f.Items == nil {
+			return []any{}
+		}
+
+		return []any{templateValue(f.Items)}
+	}
+
+	return nil
+}
+
+func templateFields(fields map[string]*schema.Field) map[string]any {
+	doc := make(map[string]any, len(fields))
+
+	for name, f := range fields {
+		doc[name] = templateValue(f)
+	}
+
+	return doc
+}
+
+// Template returns sample document matching the schema.
+// Fields are filled with the default values, if set,
+// or with the placeholder values of the field type.
+// Arrays contain one example element.
+func Template(sch *schema.Schema) map[string]any {
+	return templateFields(sch.Fields)
+}
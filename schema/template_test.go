@@ -0,0 +1,61 @@
+// Copyright 2022-2023 Tigris Data, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package schema
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tigrisdata/tigris-client-go/schema"
+)
+
+func TestTemplate(t *testing.T) {
+	var sch schema.Schema
+
+	require.NoError(t, json.Unmarshal([]byte(`{"title":"coll","properties":{
+		"id":{"type":"string","format":"uuid"},
+		"name":{"type":"string"},
+		"status":{"type":"string","default":"active"},
+		"age":{"type":"integer","format":"int32"},
+		"score":{"type":"number"},
+		"admin":{"type":"boolean"},
+		"created":{"type":"string","format":"date-time"},
+		"avatar":{"type":"string","format":"byte"},
+		"embedding":{"type":"array","format":"vector","dimensions":3},
+		"tags":{"type":"array","items":{"type":"string"}},
+		"address":{"type":"object","properties":{"city":{"type":"string"}}},
+		"orders":{"type":"array","items":{"type":"object","properties":{"total":{"type":"number"}}}}
+	}}`), &sch))
+
+	b, err := json.Marshal(Template(&sch))
+	require.NoError(t, err)
+
+	assert.JSONEq(t, `{
+		"id":"00000000-0000-0000-0000-000000000000",
+		"name":"string",
+		"status":"active",
+		"age":0,
+		"score":0.0,
+		"admin":false,
+		"created":"2006-01-02T15:04:05Z",
+		"avatar":"AA==",
+		"embedding":[0.0,0.0,0.0],
+		"tags":["string"],
+		"address":{"city":"string"},
+		"orders":[{"total":0.0}]
+	}`, string(b))
+}
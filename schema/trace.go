@@ -0,0 +1,162 @@
+// Copyright 2022-2023 Tigris Data, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package schema
+
+import (
+	"bufio"
+	"encoding/json"
+	"os"
+	"strings"
+	"sync"
+
+	"github.com/tigrisdata/tigris-client-go/schema"
+)
+
+// InferenceLog is the name of the file to write the inference trace to.
+// Every sample value considered by the inference is written as a JSON line,
+// along with the type detected from the value and the resulting type of the field.
+var InferenceLog string
+
+// traceRecord is a single line of the inference log.
+type traceRecord struct {
+	Collection   string `json:"collection"`
+	Field        string `json:"field"`
+	Value        any    `json:"value,omitempty"`
+	Type         string `json:"type,omitempty"`
+	Format       string `json:"format,omitempty"`
+	PrevType     string `json:"prev_type,omitempty"`
+	PrevFormat   string `json:"prev_format,omitempty"`
+	ResultType   string `json:"result_type,omitempty"`
+	ResultFormat string `json:"result_format,omitempty"`
+	ItemsType    string `json:"items_type,omitempty"`
+	Note         string `json:"note,omitempty"`
+}
+
+type tracer struct {
+	mu   sync.Mutex
+	w    *bufio.Writer
+	enc  *json.Encoder
+	coll string
+	path []string
+}
+
+// trace is nil, when the inference log is disabled,
+// all the methods are no-op in this case.
+var trace *tracer
+
+func newTracer(w *bufio.Writer) *tracer {
+	return &tracer{w: w, enc: json.NewEncoder(w)}
+}
+
+// OpenInferenceLog creates the inference log file, if requested.
+func OpenInferenceLog() error {
+	if InferenceLog == "" {
+		return nil
+	}
+
+	f, err := os.Create(InferenceLog)
+	if err != nil {
+		return err
+	}
+
+	trace = newTracer(bufio.NewWriter(f))
+
+	return nil
+}
+
+func (t *tracer) setCollection(name string) {
+	if t == nil {
+		return
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.coll = name
+}
+
+// enter descends into the object or array field.
+func (t *tracer) enter(name string) {
+	if t == nil {
+		return
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.path = append(t.path, name)
+}
+
+func (t *tracer) leave() {
+	if t == nil {
+		return
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.path = t.path[:len(t.path)-1]
+}
+
+// field records the sample value of the field, the type detected from it,
+// the type of the field before and after the sample is considered.
+func (t *tracer) field(name string, v any, tp string, format string, prev *schema.Field, res *schema.Field,
+	note string,
+) {
+	if t == nil {
+		return
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	r := traceRecord{
+		Collection: t.coll,
+		Field:      strings.Join(append(t.path[:len(t.path):len(t.path)], name), "."),
+		Type:       tp,
+		Format:     format,
+		Note:       note,
+	}
+
+	if _, ok := v.(map[string]any); !ok {
+		r.Value = v
+	}
+
+	if prev != nil {
+		r.PrevType, r.PrevFormat = prev.Type.First(), prev.Format
+	}
+
+	if res != nil {
+		r.ResultType, r.ResultFormat = res.Type.First(), res.Format
+
+		if res.Items != nil {
+			r.ItemsType = res.Items.Type.First()
+		}
+	}
+
+	// Tracing is best effort, it shouldn't fail the import
+	_ = t.enc.Encode(&r)
+}
+
+func (t *tracer) flush() {
+	if t == nil {
+		return
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	_ = t.w.Flush()
+}
@@ -0,0 +1,88 @@
+// Copyright 2022-2023 Tigris Data, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package schema
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tigrisdata/tigris-client-go/schema"
+)
+
+func TestInferenceTrace(t *testing.T) {
+	var buf bytes.Buffer
+
+	trace = newTracer(bufio.NewWriter(&buf))
+
+	defer func() { trace = nil }()
+
+	var sch schema.Schema
+
+	err := Infer(&sch, "coll", []json.RawMessage{
+		json.RawMessage(`{"a":1, "b":{"c":"2023-01-01T00:00:00Z"}, "d":[{"e":true}], "f":null}`),
+		json.RawMessage(`{"a":1.5}`),
+	}, nil, nil, 0)
+	require.NoError(t, err)
+
+	records := make(map[string][]traceRecord)
+
+	dec := json.NewDecoder(&buf)
+
+	for dec.More() {
+		var r traceRecord
+
+		require.NoError(t, dec.Decode(&r))
+		assert.Equal(t, "coll", r.Collection)
+
+		records[r.Field] = append(records[r.Field], r)
+	}
+
+	require.Len(t, records["a"], 2)
+	assert.Equal(t, traceRecord{
+		Collection: "coll", Field: "a", Value: "1", Type: typeInteger, ResultType: typeInteger,
+	}, normalizeValue(records["a"][0]))
+	assert.Equal(t, traceRecord{
+		Collection: "coll", Field: "a", Value: "1.5", Type: typeNumber,
+		PrevType: typeInteger, ResultType: typeNumber,
+	}, normalizeValue(records["a"][1]))
+
+	require.Len(t, records["b.c"], 1)
+	assert.Equal(t, formatDateTime, records["b.c"][0].ResultFormat)
+
+	require.Len(t, records["b"], 1)
+	assert.Nil(t, records["b"][0].Value)
+	assert.Equal(t, typeObject, records["b"][0].ResultType)
+
+	require.Len(t, records["d[].e"], 1)
+	assert.Equal(t, typeBoolean, records["d[].e"][0].ResultType)
+	assert.Equal(t, typeObject, records["d"][0].ItemsType)
+
+	require.Len(t, records["f"], 1)
+	assert.Equal(t, "null value skipped", records["f"][0].Note)
+}
+
+// normalizeValue converts numeric values decoded as float64 to string for comparison.
+func normalizeValue(r traceRecord) traceRecord {
+	if v, ok := r.Value.(float64); ok {
+		b, _ := json.Marshal(v)
+		r.Value = string(b)
+	}
+
+	return r
+}
@@ -0,0 +1,72 @@
+// Copyright 2022-2023 Tigris Data, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package schema
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+var (
+	// TTLField is the date-time field of the document, the time to live is counted from.
+	TTLField string
+	// TTL is the time to live of the documents of the collection.
+	TTL time.Duration
+
+	ErrTTLOptions = fmt.Errorf("--ttl and --ttl-field should be specified together")
+	ErrTTLField   = fmt.Errorf("ttl field should be top level date-time field of the schema")
+)
+
+// ValidateTTL checks that the TTL options are consistent.
+func ValidateTTL() error {
+	if (TTLField == "") != (TTL == 0) || TTL < 0 {
+		return ErrTTLOptions
+	}
+
+	return nil
+}
+
+// WithTTL sets the time-to-live policy of the collection in the schema.
+// The policy requires server support, servers without it reject the schema.
+func WithTTL(b []byte) ([]byte, error) {
+	if TTLField == "" {
+		return b, nil
+	}
+
+	var sch map[string]any
+
+	dec := json.NewDecoder(bytes.NewReader(b))
+	dec.UseNumber()
+
+	if err := dec.Decode(&sch); err != nil {
+		return nil, err
+	}
+
+	props, _ := sch["properties"].(map[string]any)
+	field, _ := props[TTLField].(map[string]any)
+
+	if field["type"] != typeString || field["format"] != formatDateTime {
+		return nil, fmt.Errorf("%w: %s", ErrTTLField, TTLField)
+	}
+
+	sch["ttl"] = map[string]any{
+		"field":   TTLField,
+		"seconds": int64(TTL / time.Second),
+	}
+
+	return json.Marshal(sch)
+}
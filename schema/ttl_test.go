@@ -0,0 +1,58 @@
+// Copyright 2022-2023 Tigris Data, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package schema
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWithTTL(t *testing.T) {
+	defer func() {
+		TTLField = ""
+		TTL = 0
+	}()
+
+	sch := []byte(`{"title":"logs","properties":{"id":{"type":"integer"},` +
+		`"created_at":{"type":"string","format":"date-time"}},"primary_key":["id"]}`)
+
+	// no-op when not requested
+	b, err := WithTTL(sch)
+	require.NoError(t, err)
+	assert.Equal(t, sch, b)
+
+	TTL = 24 * time.Hour
+	require.ErrorIs(t, ValidateTTL(), ErrTTLOptions)
+
+	TTLField = "created_at"
+	require.NoError(t, ValidateTTL())
+
+	b, err = WithTTL(sch)
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"title":"logs","properties":{"id":{"type":"integer"},`+
+		`"created_at":{"type":"string","format":"date-time"}},"primary_key":["id"],`+
+		`"ttl":{"field":"created_at","seconds":86400}}`, string(b))
+
+	TTLField = "id"
+	_, err = WithTTL(sch)
+	require.ErrorIs(t, err, ErrTTLField)
+
+	TTLField = "missing"
+	_, err = WithTTL(sch)
+	require.ErrorIs(t, err, ErrTTLField)
+}
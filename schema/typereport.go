@@ -0,0 +1,142 @@
+// Copyright 2022-2023 Tigris Data, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package schema
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"text/tabwriter"
+
+	"github.com/tigrisdata/tigris-cli/util"
+	"github.com/tigrisdata/tigris-client-go/schema"
+)
+
+const (
+	FieldTypeReportTable = "table"
+	FieldTypeReportJSON  = "json"
+	FieldTypeReportNone  = "none"
+)
+
+var (
+	// FieldTypeReport controls printing of the field types of the final schema to stderr
+	// when the import is finished. By default the table is printed in interactive mode only.
+	FieldTypeReport string
+
+	ErrInvalidFieldTypeReport = fmt.Errorf("invalid field type report format. allowed values: %s, %s, %s",
+		FieldTypeReportTable, FieldTypeReportJSON, FieldTypeReportNone)
+)
+
+// FieldType is the entry of the field type report.
+// Nested fields are dot separated, array items are denoted by the "[]" suffix.
+type FieldType struct {
+	Field string `json:"field"`
+	Type  string `json:"type"`
+}
+
+func ValidateFieldTypeReport() error {
+	switch FieldTypeReport {
+	case "", FieldTypeReportTable, FieldTypeReportJSON, FieldTypeReportNone:
+		return nil
+	}
+
+	return ErrInvalidFieldTypeReport
+}
+
+func flattenFieldTypes(prefix string, fields map[string]*schema.Field, res []FieldType) []FieldType {
+	names := make([]string, 0, len(fields))
+	for k := range fields {
+		names = append(names, k)
+	}
+
+	sort.Strings(names)
+
+	for _, k := range names {
+		name := prefix + k
+		f := fields[k]
+
+		for f.Type.First() == typeArray && f.Items != nil {
+			name += "[]"
+			f = f.Items
+		}
+
+		if len(f.Fields) > 0 {
+			res = flattenFieldTypes(name+".", f.Fields, res)
+		} else {
+			res = append(res, FieldType{name, fieldType(f)})
+		}
+	}
+
+	return res
+}
+
+// FieldTypes returns the flattened list of the fields of the schema with their types.
+func FieldTypes(b []byte) ([]FieldType, error) {
+	var sch schema.Schema
+
+	if err := json.Unmarshal(b, &sch); err != nil {
+		return nil, err
+	}
+
+	return flattenFieldTypes("", sch.Fields, nil), nil
+}
+
+func printFieldTypes(w io.Writer, format string, b []byte) error {
+	types, err := FieldTypes(b)
+	if err != nil {
+		return err
+	}
+
+	if format == FieldTypeReportJSON {
+		if types == nil {
+			types = []FieldType{}
+		}
+
+		return json.NewEncoder(w).Encode(types)
+	}
+
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+
+	_, _ = fmt.Fprintln(tw, "FIELD\tTYPE")
+
+	for _, v := range types {
+		_, _ = fmt.Fprintf(tw, "%s\t%s\n", v.Field, v.Type)
+	}
+
+	return tw.Flush()
+}
+
+// PrintFieldTypes prints the field types report of the final schema to stderr.
+// Without explicit --field-type-report the table is printed, when stderr is a terminal
+// and --quiet is not set.
+func PrintFieldTypes(b []byte) error {
+	format := FieldTypeReport
+
+	if format == "" {
+		if util.Quiet || !util.IsTTY(os.Stderr) {
+			return nil
+		}
+
+		format = FieldTypeReportTable
+	}
+
+	if format == FieldTypeReportNone || b == nil {
+		return nil
+	}
+
+	return printFieldTypes(os.Stderr, format, b)
+}
@@ -0,0 +1,77 @@
+// Copyright 2022-2023 Tigris Data, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package schema
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFieldTypeReport(t *testing.T) {
+	defer func() { FieldTypeReport = "" }()
+
+	for _, v := range []string{"", FieldTypeReportTable, FieldTypeReportJSON, FieldTypeReportNone} {
+		FieldTypeReport = v
+		require.NoError(t, ValidateFieldTypeReport())
+	}
+
+	FieldTypeReport = "yaml"
+	require.ErrorIs(t, ValidateFieldTypeReport(), ErrInvalidFieldTypeReport)
+
+	sch := []byte(`{"title":"coll","properties":{
+		"id":{"type":"integer"},
+		"name":{"type":"string"},
+		"created":{"type":"string","format":"date-time"},
+		"tags":{"type":"array","items":{"type":"string"}},
+		"addr":{"type":"object","properties":{"city":{"type":"string"},"zip":{"type":"integer"}}},
+		"items":{"type":"array","items":{"type":"object","properties":{"qty":{"type":"number"}}}},
+		"empty":{"type":"array"}
+	}}`)
+
+	types, err := FieldTypes(sch)
+	require.NoError(t, err)
+	assert.Equal(t, []FieldType{
+		{"addr.city", "string"},
+		{"addr.zip", "integer"},
+		{"created", "string:date-time"},
+		{"empty", "array"},
+		{"id", "integer"},
+		{"items[].qty", "number"},
+		{"name", "string"},
+		{"tags[]", "string"},
+	}, types)
+
+	var buf bytes.Buffer
+
+	require.NoError(t, printFieldTypes(&buf, FieldTypeReportTable, []byte(`{"properties":{"id":{"type":"integer"},"created_at":{"type":"string","format":"date-time"}}}`)))
+	assert.Equal(t, `FIELD       TYPE
+created_at  string:date-time
+id          integer
+`, buf.String())
+
+	buf.Reset()
+
+	require.NoError(t, printFieldTypes(&buf, FieldTypeReportJSON, []byte(`{"properties":{"id":{"type":"integer"}}}`)))
+	assert.Equal(t, `[{"field":"id","type":"integer"}]
+`, buf.String())
+
+	buf.Reset()
+
+	require.NoError(t, printFieldTypes(&buf, FieldTypeReportJSON, []byte(`{}`)))
+	assert.Equal(t, "[]\n", buf.String())
+}
@@ -0,0 +1,73 @@
+// Copyright 2022-2023 Tigris Data, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package util
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"runtime"
+)
+
+var ErrClipboardUnavailable = fmt.Errorf("clipboard is not available")
+
+// clipboardCommands are the commands, printing the content of the system clipboard,
+// tried in order, as there is no clipboard API available to the CLI directly.
+func clipboardCommands() [][]string {
+	switch runtime.GOOS {
+	case "darwin":
+		return [][]string{{"pbpaste"}}
+	case "windows":
+		return [][]string{{"powershell.exe", "-NoProfile", "-Command", "Get-Clipboard -Raw"}}
+	}
+
+	var cmds [][]string
+
+	if os.Getenv("WAYLAND_DISPLAY") != "" {
+		cmds = append(cmds, []string{"wl-paste", "--no-newline"})
+	}
+
+	if os.Getenv("DISPLAY") != "" {
+		cmds = append(cmds,
+			[]string{"xclip", "-out", "-selection", "clipboard"},
+			[]string{"xsel", "--output", "--clipboard"},
+		)
+	}
+
+	return cmds
+}
+
+// ReadClipboard returns the content of the system clipboard.
+// Returns ErrClipboardUnavailable on the headless systems
+// and when none of the clipboard utilities is installed.
+func ReadClipboard(ctx context.Context) ([]byte, error) {
+	for _, c := range clipboardCommands() {
+		path, err := exec.LookPath(c[0])
+		if err != nil {
+			continue
+		}
+
+		b, err := exec.CommandContext(ctx, path, c[1:]...).Output()
+		if err != nil {
+			return nil, fmt.Errorf("%w: %s: %s", ErrClipboardUnavailable, c[0], err.Error())
+		}
+
+		return b, nil
+	}
+
+	return nil, fmt.Errorf("%w: no display or clipboard utility found, "+
+		"install wl-clipboard, xclip or xsel", ErrClipboardUnavailable)
+}
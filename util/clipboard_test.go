@@ -0,0 +1,50 @@
+// Copyright 2022-2023 Tigris Data, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package util
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReadClipboard(t *testing.T) {
+	if runtime.GOOS != "linux" {
+		t.Skip("clipboard utilities are faked on linux only")
+	}
+
+	t.Setenv("WAYLAND_DISPLAY", "")
+	t.Setenv("DISPLAY", "")
+
+	_, err := ReadClipboard(context.Background())
+	require.ErrorIs(t, err, ErrClipboardUnavailable)
+
+	dir := t.TempDir()
+	script := "#!/bin/sh\nprintf '{\"a\":1}'\n"
+
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "xsel"), []byte(script), 0o700)) //nolint:gosec
+
+	t.Setenv("PATH", dir)
+	t.Setenv("DISPLAY", ":0")
+
+	b, err := ReadClipboard(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, `{"a":1}`, string(b))
+}
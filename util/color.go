@@ -0,0 +1,91 @@
+// Copyright 2022-2023 Tigris Data, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package util
+
+import (
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/schollz/progressbar/v3"
+)
+
+const (
+	ColorAuto   = "auto"
+	ColorAlways = "always"
+	ColorNever  = "never"
+
+	EnvNoColor = "NO_COLOR"
+)
+
+var (
+	ErrInvalidColor = fmt.Errorf("invalid color mode. allowed values: %s, %s, %s", ColorAuto, ColorAlways, ColorNever)
+
+	// Color controls colored output: auto, always or never.
+	Color = ColorAuto
+)
+
+func ValidateColor() error {
+	switch Color {
+	case ColorAuto, ColorAlways, ColorNever:
+		return nil
+	}
+
+	return ErrInvalidColor
+}
+
+// ColorEnabled returns true if colored output should be written to the file.
+// In the auto mode color is enabled when the file is a terminal
+// and NO_COLOR environment variable is not set to a non-empty value.
+func ColorEnabled(f *os.File) bool {
+	switch Color {
+	case ColorAlways:
+		return true
+	case ColorNever:
+		return false
+	}
+
+	if noColor() {
+		return false
+	}
+
+	return IsTTY(f)
+}
+
+// noColor returns true when NO_COLOR environment variable is set to a non-empty value,
+// see https://no-color.org.
+func noColor() bool {
+	return os.Getenv(EnvNoColor) != ""
+}
+
+// NewProgressBar returns progress bar writing to stderr,
+// which respects color settings.
+func NewProgressBar(max int64) *progressbar.ProgressBar {
+	return progressbar.NewOptions64(
+		max,
+		progressbar.OptionSetWriter(os.Stderr),
+		progressbar.OptionSetWidth(10),
+		progressbar.OptionThrottle(65*time.Millisecond),
+		progressbar.OptionShowCount(),
+		progressbar.OptionShowIts(),
+		progressbar.OptionOnCompletion(func() {
+			_, _ = fmt.Fprint(os.Stderr, "\n")
+		}),
+		progressbar.OptionSpinnerType(14),
+		progressbar.OptionFullWidth(),
+		progressbar.OptionSetRenderBlankState(true),
+		progressbar.OptionEnableColorCodes(ColorEnabled(os.Stderr)),
+	)
+}
@@ -0,0 +1,57 @@
+// Copyright 2022-2023 Tigris Data, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package util
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestColorEnabled(t *testing.T) {
+	f, err := os.Create(filepath.Join(t.TempDir(), "out.log"))
+	require.NoError(t, err)
+
+	defer func() {
+		_ = f.Close()
+		Color = ColorAuto
+	}()
+
+	Color = ColorAuto
+	assert.False(t, ColorEnabled(f))
+
+	Color = ColorAlways
+	assert.True(t, ColorEnabled(f))
+
+	t.Setenv(EnvNoColor, "1")
+	assert.True(t, ColorEnabled(f))
+
+	Color = ColorNever
+	assert.False(t, ColorEnabled(f))
+
+	Color = "rainbow"
+	assert.Equal(t, ErrInvalidColor, ValidateColor())
+}
+
+func TestNoColor(t *testing.T) {
+	t.Setenv(EnvNoColor, "")
+	assert.False(t, noColor())
+
+	t.Setenv(EnvNoColor, "1")
+	assert.True(t, noColor())
+}
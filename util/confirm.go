@@ -0,0 +1,71 @@
+// Copyright 2022-2023 Tigris Data, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package util
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+)
+
+var ErrNotConfirmed = fmt.Errorf("operation is not confirmed")
+
+func confirm(r io.Reader, w io.Writer, prompt string) bool {
+	_, _ = fmt.Fprintf(w, "%s [y/N]: ", prompt)
+
+	line, _ := bufio.NewReader(r).ReadString('\n')
+
+	switch strings.ToLower(strings.TrimSpace(line)) {
+	case "y", "yes":
+		return true
+	}
+
+	return false
+}
+
+// Confirm asks the user to confirm the operation, when the standard input is a terminal.
+// Returns ErrNotConfirmed if the user doesn't confirm.
+// Non-interactive invocations are not asked and are considered confirmed.
+func Confirm(format string, args ...any) error {
+	if !IsTTY(os.Stdin) || confirm(os.Stdin, os.Stderr, fmt.Sprintf(format, args...)) {
+		return nil
+	}
+
+	return ErrNotConfirmed
+}
+
+// StdinArg reports whether the standard input is among the arguments, marked by "-".
+func StdinArg(args []string) bool {
+	for _, v := range args {
+		if v == "-" {
+			return true
+		}
+	}
+
+	return false
+}
+
+// ConfirmArgs asks to confirm the operation on the arguments, like Confirm.
+// The user is not asked, when the arguments are read from the standard input,
+// so the answer is not mixed up with the arguments.
+func ConfirmArgs(args []string, format string, fmtArgs ...any) error {
+	if StdinArg(args) {
+		return nil
+	}
+
+	return Confirm(format, fmtArgs...)
+}
@@ -0,0 +1,42 @@
+// Copyright 2022-2023 Tigris Data, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package util
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestConfirm(t *testing.T) {
+	var w bytes.Buffer
+
+	assert.True(t, confirm(strings.NewReader("y\n"), &w, "Drop collection users?"))
+	assert.Equal(t, "Drop collection users? [y/N]: ", w.String())
+
+	assert.True(t, confirm(strings.NewReader(" YES \n"), &w, "?"))
+	assert.False(t, confirm(strings.NewReader("n\n"), &w, "?"))
+	assert.False(t, confirm(strings.NewReader("\n"), &w, "?"))
+	assert.False(t, confirm(strings.NewReader(""), &w, "?"))
+}
+
+func TestStdinArg(t *testing.T) {
+	assert.False(t, StdinArg(nil))
+	assert.False(t, StdinArg([]string{"users", "orders"}))
+	assert.True(t, StdinArg([]string{"-"}))
+	assert.True(t, StdinArg([]string{"users", "-"}))
+}
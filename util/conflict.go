@@ -0,0 +1,51 @@
+// Copyright 2022-2023 Tigris Data, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package util
+
+import (
+	"fmt"
+)
+
+const (
+	OnConflictError   = "error"
+	OnConflictSkip    = "skip"
+	OnConflictReplace = "replace"
+)
+
+var (
+	// OnConflict is the policy of handling the documents with the primary key
+	// or the id which already exists in the collection or index:
+	//   error - fail the import (default),
+	//   skip - leave the existing document untouched,
+	//   replace - replace the existing document with the new one.
+	OnConflict = OnConflictError
+
+	ErrInvalidOnConflict = fmt.Errorf("invalid --on-conflict value. allowed values: error, skip, replace")
+)
+
+// ValidateOnConflict checks that OnConflict is one of the allowed policies.
+func ValidateOnConflict() error {
+	return CheckOnConflict(OnConflict)
+}
+
+// CheckOnConflict checks that the policy is one of the allowed policies.
+func CheckOnConflict(policy string) error {
+	switch policy {
+	case OnConflictError, OnConflictSkip, OnConflictReplace:
+		return nil
+	default:
+		return fmt.Errorf("%w: %s", ErrInvalidOnConflict, policy)
+	}
+}
@@ -0,0 +1,36 @@
+// Copyright 2022-2023 Tigris Data, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package util
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidateOnConflict(t *testing.T) {
+	defer func() { OnConflict = OnConflictError }()
+
+	for _, v := range []string{OnConflictError, OnConflictSkip, OnConflictReplace} {
+		OnConflict = v
+		require.NoError(t, ValidateOnConflict())
+	}
+
+	OnConflict = "ignore"
+	require.ErrorIs(t, ValidateOnConflict(), ErrInvalidOnConflict)
+
+	require.NoError(t, CheckOnConflict(OnConflictSkip))
+	require.ErrorIs(t, CheckOnConflict("ignore"), ErrInvalidOnConflict)
+}
@@ -0,0 +1,230 @@
+// Copyright 2022-2023 Tigris Data, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package util
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+)
+
+// Encrypted values are strings: EncryptedPrefix followed by base64 encoded
+// 12 bytes nonce and AES-256-GCM sealed JSON representation of the original value.
+// Deterministic mode derives the nonce from HMAC-SHA256 of the value,
+// so equal values have equal ciphertext and can be matched by filters,
+// at the cost of revealing which documents have equal values.
+const (
+	EncryptedPrefix = "enc:v1:"
+
+	EncryptRandom        = "random"
+	EncryptDeterministic = "deterministic"
+
+	EncryptionKeyEnv = "TIGRIS_ENCRYPTION_KEY"
+
+	encryptionKeySize = 32
+)
+
+var (
+	ErrEncryptionKey        = fmt.Errorf("encryption key should be 32 bytes, base64 or hex encoded")
+	ErrEncryptionKeyMissing = fmt.Errorf("encryption key is required. use --encryption-key, " +
+		"--encryption-key-file or " + EncryptionKeyEnv + " environment variable")
+	ErrEncryptMode = fmt.Errorf("invalid encryption mode. allowed values: %s, %s",
+		EncryptRandom, EncryptDeterministic)
+	ErrDecrypt = fmt.Errorf("failed to decrypt field value")
+)
+
+// LoadEncryptionKey returns the key from the flag, the file or the environment, in this order of precedence.
+func LoadEncryptionKey(key string, file string) ([]byte, error) {
+	if key == "" && file != "" {
+		b, err := os.ReadFile(file)
+		if err != nil {
+			return nil, err
+		}
+
+		key = string(b)
+	}
+
+	if key == "" {
+		key = os.Getenv(EncryptionKeyEnv)
+	}
+
+	key = strings.TrimSpace(key)
+
+	if key == "" {
+		return nil, ErrEncryptionKeyMissing
+	}
+
+	if b, err := hex.DecodeString(key); err == nil && len(b) == encryptionKeySize {
+		return b, nil
+	}
+
+	if b, err := base64.StdEncoding.DecodeString(key); err == nil && len(b) == encryptionKeySize {
+		return b, nil
+	}
+
+	return nil, ErrEncryptionKey
+}
+
+// FieldCipher encrypts and decrypts the values of the fields of the documents.
+type FieldCipher struct {
+	aead          cipher.AEAD
+	key           []byte
+	deterministic bool
+	fields        [][]string
+}
+
+// NewFieldCipher creates the cipher of the fields. Nested fields are specified using dot notation.
+func NewFieldCipher(key []byte, mode string, fields []string) (*FieldCipher, error) {
+	if mode != EncryptRandom && mode != EncryptDeterministic {
+		return nil, ErrEncryptMode
+	}
+
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+
+	aead, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, err
+	}
+
+	c := &FieldCipher{aead: aead, key: key, deterministic: mode == EncryptDeterministic}
+
+	for _, f := range fields {
+		c.fields = append(c.fields, strings.Split(f, "."))
+	}
+
+	return c, nil
+}
+
+func (c *FieldCipher) encryptValue(v any) (string, error) {
+	plain, err := json.Marshal(v)
+	if err != nil {
+		return "", err
+	}
+
+	nonce := make([]byte, c.aead.NonceSize())
+
+	if c.deterministic {
+		mac := hmac.New(sha256.New, c.key)
+		mac.Write(plain)
+		copy(nonce, mac.Sum(nil))
+	} else if _, err = io.ReadFull(rand.Reader, nonce); err != nil {
+		return "", err
+	}
+
+	return EncryptedPrefix + base64.StdEncoding.EncodeToString(c.aead.Seal(nonce, nonce, plain, nil)), nil
+}
+
+func (c *FieldCipher) decryptValue(s string) (any, error) {
+	b, err := base64.StdEncoding.DecodeString(strings.TrimPrefix(s, EncryptedPrefix))
+	if err != nil || len(b) < c.aead.NonceSize() {
+		return nil, ErrDecrypt
+	}
+
+	plain, err := c.aead.Open(nil, b[:c.aead.NonceSize()], b[c.aead.NonceSize():], nil)
+	if err != nil {
+		return nil, ErrDecrypt
+	}
+
+	var v any
+
+	dec := json.NewDecoder(bytes.NewReader(plain))
+	dec.UseNumber()
+
+	if err = dec.Decode(&v); err != nil {
+		return nil, err
+	}
+
+	return v, nil
+}
+
+// apply replaces the values of the fields with the result of the fn.
+// Absent and null fields are left as is.
+func (c *FieldCipher) apply(doc json.RawMessage, fn func(v any) (any, error)) (json.RawMessage, error) {
+	var m map[string]any
+
+	dec := json.NewDecoder(bytes.NewReader(doc))
+	dec.UseNumber()
+
+	if err := dec.Decode(&m); err != nil {
+		return nil, err
+	}
+
+	changed := false
+
+	for _, path := range c.fields {
+		parent := m
+
+		for i := 0; i < len(path)-1 && parent != nil; i++ {
+			parent, _ = parent[path[i]].(map[string]any)
+		}
+
+		name := path[len(path)-1]
+		if parent == nil || parent[name] == nil {
+			continue
+		}
+
+		v, err := fn(parent[name])
+		if err != nil {
+			return nil, fmt.Errorf("%w: %s", err, strings.Join(path, "."))
+		}
+
+		parent[name] = v
+		changed = true
+	}
+
+	if !changed {
+		return doc, nil
+	}
+
+	b, err := json.Marshal(m)
+	if err != nil {
+		return nil, err
+	}
+
+	return KeepOrder(doc, b)
+}
+
+// Encrypt encrypts the values of the fields of the document.
+func (c *FieldCipher) Encrypt(doc json.RawMessage) (json.RawMessage, error) {
+	return c.apply(doc, func(v any) (any, error) {
+		return c.encryptValue(v)
+	})
+}
+
+// Decrypt decrypts the values of the fields of the document.
+// Values which are not encrypted are left as is.
+func (c *FieldCipher) Decrypt(doc json.RawMessage) (json.RawMessage, error) {
+	return c.apply(doc, func(v any) (any, error) {
+		s, ok := v.(string)
+		if !ok || !strings.HasPrefix(s, EncryptedPrefix) {
+			return v, nil
+		}
+
+		return c.decryptValue(s)
+	})
+}
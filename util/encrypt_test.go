@@ -0,0 +1,97 @@
+// Copyright 2022-2023 Tigris Data, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package util
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testEncryptionKey = "000102030405060708090a0b0c0d0e0f101112131415161718191a1b1c1d1e1f"
+
+func TestLoadEncryptionKey(t *testing.T) {
+	t.Setenv(EncryptionKeyEnv, "")
+
+	_, err := LoadEncryptionKey("", "")
+	require.ErrorIs(t, err, ErrEncryptionKeyMissing)
+
+	key, err := LoadEncryptionKey(testEncryptionKey, "")
+	require.NoError(t, err)
+	assert.Equal(t, testEncryptionKey, hex.EncodeToString(key))
+
+	file := filepath.Join(t.TempDir(), "key")
+	require.NoError(t, os.WriteFile(file, []byte("AAECAwQFBgcICQoLDA0ODxAREhMUFRYXGBkaGxwdHh8=\n"), 0o600))
+
+	key, err = LoadEncryptionKey("", file)
+	require.NoError(t, err)
+	assert.Equal(t, testEncryptionKey, hex.EncodeToString(key))
+
+	t.Setenv(EncryptionKeyEnv, testEncryptionKey)
+
+	_, err = LoadEncryptionKey("", "")
+	require.NoError(t, err)
+
+	_, err = LoadEncryptionKey("short", "")
+	require.ErrorIs(t, err, ErrEncryptionKey)
+}
+
+func TestFieldCipher(t *testing.T) {
+	key, err := hex.DecodeString(testEncryptionKey)
+	require.NoError(t, err)
+
+	_, err = NewFieldCipher(key, "ecb", nil)
+	require.ErrorIs(t, err, ErrEncryptMode)
+
+	for _, mode := range []string{EncryptRandom, EncryptDeterministic} {
+		t.Run(mode, func(t *testing.T) {
+			c, err := NewFieldCipher(key, mode, []string{"ssn", "address.zip", "missing", "empty"})
+			require.NoError(t, err)
+
+			doc := json.RawMessage(`{"id":1,"ssn":"123-45-6789","address":{"zip":12345,"city":"x"},"empty":null}`)
+
+			enc, err := c.Encrypt(doc)
+			require.NoError(t, err)
+
+			var m map[string]any
+			require.NoError(t, json.Unmarshal(enc, &m))
+
+			assert.True(t, strings.HasPrefix(m["ssn"].(string), EncryptedPrefix))
+			assert.True(t, strings.HasPrefix(m["address"].(map[string]any)["zip"].(string), EncryptedPrefix))
+			assert.Equal(t, "x", m["address"].(map[string]any)["city"])
+			assert.Nil(t, m["empty"])
+
+			enc2, err := c.Encrypt(doc)
+			require.NoError(t, err)
+			assert.Equal(t, mode == EncryptDeterministic, string(enc) == string(enc2))
+
+			dec, err := c.Decrypt(enc)
+			require.NoError(t, err)
+			assert.JSONEq(t, string(doc), string(dec))
+		})
+	}
+
+	c, err := NewFieldCipher(key, EncryptRandom, []string{"ssn"})
+	require.NoError(t, err)
+
+	_, err = c.Decrypt(json.RawMessage(`{"ssn":"` + EncryptedPrefix + `AAAA"}`))
+	require.ErrorIs(t, err, ErrDecrypt)
+}
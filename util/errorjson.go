@@ -0,0 +1,84 @@
+// Copyright 2022-2023 Tigris Data, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package util
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+
+	api "github.com/tigrisdata/tigris-client-go/api/server/v1"
+	"github.com/tigrisdata/tigris-client-go/driver"
+)
+
+const (
+	LogFormatConsole = "console"
+	LogFormatJSON    = "json"
+)
+
+var (
+	ErrInvalidLogFormat = fmt.Errorf("invalid log format. allowed values: %s, %s", LogFormatConsole, LogFormatJSON)
+
+	// jsonErrors enables printing of the fatal errors as JSON objects, set by --log-format=json.
+	jsonErrors bool
+)
+
+// ValidateLogFormat checks the log format. Empty format is the auto mode:
+// console output to terminal and JSON output to pipe.
+func ValidateLogFormat(format string) error {
+	switch format {
+	case "", LogFormatConsole, LogFormatJSON:
+		return nil
+	}
+
+	return ErrInvalidLogFormat
+}
+
+// errorJSON is the structured representation of the fatal error.
+type errorJSON struct {
+	Code      string `json:"code"`
+	Message   string `json:"message"`
+	Context   string `json:"context,omitempty"`
+	Retryable bool   `json:"retryable"`
+}
+
+// newErrorJSON returns the structured error. The code is the code of the server error,
+// like NOT_FOUND, DEADLINE_EXCEEDED for timeouts or UNKNOWN for the errors of the CLI.
+// Throttling, unavailability and timeouts are retryable.
+func newErrorJSON(err error, msg string) *errorJSON {
+	e := &errorJSON{Code: api.Code_UNKNOWN.String(), Message: err.Error(), Context: msg}
+
+	var ep *driver.Error
+
+	switch {
+	case errors.As(err, &ep) && ep.TigrisError != nil:
+		e.Code = ep.Code.String()
+		e.Retryable = ep.Code == api.Code_RESOURCE_EXHAUSTED || ep.Code == api.Code_UNAVAILABLE ||
+			ep.Code == api.Code_DEADLINE_EXCEEDED || ep.Code == api.Code_ABORTED
+	case errors.Is(err, context.DeadlineExceeded):
+		e.Code = api.Code_DEADLINE_EXCEEDED.String()
+		e.Retryable = true
+	}
+
+	return e
+}
+
+func printErrorJSON(w io.Writer, err error, msg string) {
+	b, _ := json.Marshal(newErrorJSON(err, msg))
+
+	_, _ = fmt.Fprintf(w, "%s\n", string(b))
+}
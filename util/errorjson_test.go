@@ -0,0 +1,51 @@
+// Copyright 2022-2023 Tigris Data, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package util
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	api "github.com/tigrisdata/tigris-client-go/api/server/v1"
+	"github.com/tigrisdata/tigris-client-go/driver"
+)
+
+func TestErrorJSON(t *testing.T) {
+	require.NoError(t, ValidateLogFormat(""))
+	require.NoError(t, ValidateLogFormat(LogFormatConsole))
+	require.NoError(t, ValidateLogFormat(LogFormatJSON))
+	require.ErrorIs(t, ValidateLogFormat("text"), ErrInvalidLogFormat)
+
+	var buf bytes.Buffer
+
+	printErrorJSON(&buf, fmt.Errorf("invalid input"), "read file")
+	assert.Equal(t, `{"code":"UNKNOWN","message":"invalid input","context":"read file","retryable":false}`+"\n",
+		buf.String())
+
+	e := newErrorJSON(fmt.Errorf("insert: %w", &driver.Error{TigrisError: api.Errorf(api.Code_UNAVAILABLE, "down")}),
+		"insert documents")
+	assert.Equal(t, &errorJSON{Code: "UNAVAILABLE", Message: "insert: down", Context: "insert documents", Retryable: true}, e)
+
+	e = newErrorJSON(&driver.Error{TigrisError: api.Errorf(api.Code_NOT_FOUND, "collection doesn't exist")}, "")
+	assert.Equal(t, &errorJSON{Code: "NOT_FOUND", Message: "collection doesn't exist"}, e)
+
+	e = newErrorJSON(fmt.Errorf("describe: %w", context.DeadlineExceeded), "describe")
+	assert.Equal(t, &errorJSON{Code: "DEADLINE_EXCEEDED", Message: "describe: context deadline exceeded",
+		Context: "describe", Retryable: true}, e)
+}
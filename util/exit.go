@@ -0,0 +1,55 @@
+// Copyright 2022-2023 Tigris Data, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package util
+
+import "sync"
+
+var (
+	exitHooksMu sync.Mutex
+	exitHooks   = map[int]func(err error){}
+	exitHookID  int
+)
+
+// OnExit registers the hook to be called with the error, when the process
+// is about to exit on a fatal error, before the command returns.
+// Returns the function which unregisters the hook.
+func OnExit(fn func(err error)) func() {
+	exitHooksMu.Lock()
+	defer exitHooksMu.Unlock()
+
+	exitHookID++
+	id := exitHookID
+	exitHooks[id] = fn
+
+	return func() {
+		exitHooksMu.Lock()
+		defer exitHooksMu.Unlock()
+
+		delete(exitHooks, id)
+	}
+}
+
+// RunExitHooks calls registered exit hooks with the error.
+// Every hook is called at most once, even if the hook itself fails fatally.
+func RunExitHooks(err error) {
+	exitHooksMu.Lock()
+	hooks := exitHooks
+	exitHooks = map[int]func(err error){}
+	exitHooksMu.Unlock()
+
+	for _, fn := range hooks {
+		fn(err)
+	}
+}
@@ -0,0 +1,43 @@
+// Copyright 2022-2023 Tigris Data, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package util
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestExitHooks(t *testing.T) {
+	errExit := fmt.Errorf("fatal")
+
+	var called []error
+
+	OnExit(func(err error) { called = append(called, err) })
+	cancel := OnExit(func(err error) { called = append(called, fmt.Errorf("cancelled hook called")) })
+	cancel()
+
+	// hook failing fatally again doesn't call the hooks again
+	OnExit(func(err error) { RunExitHooks(err) })
+
+	RunExitHooks(errExit)
+	require.Len(t, called, 1)
+	assert.ErrorIs(t, called[0], errExit)
+
+	RunExitHooks(errExit)
+	assert.Len(t, called, 1)
+}
@@ -60,8 +60,11 @@ func CleanupNULLValues(doc json.RawMessage) json.RawMessage {
 
 	cleanupNULLValuesLow(m)
 
-	doc, err = jsoniter.Marshal(&m)
+	res, err := jsoniter.Marshal(&m)
 	Fatal(err, "marshal doc after cleanup")
 
-	return doc
+	res, err = KeepOrder(doc, res)
+	Fatal(err, "restore fields order after cleanup")
+
+	return res
 }
@@ -0,0 +1,38 @@
+// Copyright 2022-2023 Tigris Data, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package util
+
+import (
+	"fmt"
+)
+
+var (
+	// Optimize requests the server to optimize the collection or the index,
+	// like merging the segments of the search index, after the import.
+	Optimize bool
+
+	ErrOptimizeNotSupported = fmt.Errorf("the server doesn't support optimize, --optimize is ignored")
+)
+
+// CheckOptimize warns that --optimize is not supported by the server.
+// Neither the collections nor the search indexes API exposes an optimize operation,
+// so it is checked before the import, in order to fail early in --strict mode.
+func CheckOptimize() error {
+	if !Optimize {
+		return nil
+	}
+
+	return Warning(ErrOptimizeNotSupported)
+}
@@ -0,0 +1,212 @@
+// Copyright 2022-2023 Tigris Data, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package util
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+)
+
+// PreserveOrder enables restoring the original order of the document fields
+// after the document is modified by the CLI.
+// Documents are modified through Go maps, which don't preserve the order of the keys.
+var PreserveOrder bool
+
+var ErrUnexpectedToken = fmt.Errorf("unexpected JSON token")
+
+// OrderedObject is a JSON object, which preserves the order of the keys.
+type OrderedObject struct {
+	Keys   []string
+	Values map[string]any
+}
+
+func NewOrderedObject() *OrderedObject {
+	return &OrderedObject{Values: make(map[string]any)}
+}
+
+// Set sets the value of the key. New keys are appended to the end.
+func (o *OrderedObject) Set(key string, value any) {
+	if _, ok := o.Values[key]; !ok {
+		o.Keys = append(o.Keys, key)
+	}
+
+	o.Values[key] = value
+}
+
+// Object returns nested object of the key, creating it if it doesn't exist.
+func (o *OrderedObject) Object(key string) *OrderedObject {
+	if v, ok := o.Values[key].(*OrderedObject); ok {
+		return v
+	}
+
+	v := NewOrderedObject()
+	o.Set(key, v)
+
+	return v
+}
+
+func (o *OrderedObject) MarshalJSON() ([]byte, error) {
+	var buf bytes.Buffer
+
+	buf.WriteByte('{')
+
+	for i, k := range o.Keys {
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+
+		b, err := json.Marshal(k)
+		if err != nil {
+			return nil, err
+		}
+
+		buf.Write(b)
+		buf.WriteByte(':')
+
+		if b, err = json.Marshal(o.Values[k]); err != nil {
+			return nil, err
+		}
+
+		buf.Write(b)
+	}
+
+	buf.WriteByte('}')
+
+	return buf.Bytes(), nil
+}
+
+func decodeOrderedValue(dec *json.Decoder) (any, error) {
+	t, err := dec.Token()
+	if err != nil {
+		return nil, err
+	}
+
+	switch t {
+	case json.Delim('{'):
+		o := NewOrderedObject()
+
+		for dec.More() {
+			k, err := dec.Token()
+			if err != nil {
+				return nil, err
+			}
+
+			key, ok := k.(string)
+			if !ok {
+				return nil, fmt.Errorf("%w: %v", ErrUnexpectedToken, k)
+			}
+
+			v, err := decodeOrderedValue(dec)
+			if err != nil {
+				return nil, err
+			}
+
+			o.Set(key, v)
+		}
+
+		_, err = dec.Token() // closing '}'
+
+		return o, err
+	case json.Delim('['):
+		a := make([]any, 0)
+
+		for dec.More() {
+			v, err := decodeOrderedValue(dec)
+			if err != nil {
+				return nil, err
+			}
+
+			a = append(a, v)
+		}
+
+		_, err = dec.Token() // closing ']'
+
+		return a, err
+	}
+
+	return t, nil
+}
+
+// DecodeOrdered decodes the JSON document, preserving the order of the object keys.
+// Objects are decoded as *OrderedObject and numbers as json.Number.
+func DecodeOrdered(doc []byte) (any, error) {
+	dec := json.NewDecoder(bytes.NewReader(doc))
+	dec.UseNumber()
+
+	return decodeOrderedValue(dec)
+}
+
+func reorder(v any, order any) any {
+	switch val := v.(type) {
+	case *OrderedObject:
+		oo, _ := order.(*OrderedObject)
+		if oo == nil {
+			return val
+		}
+
+		res := NewOrderedObject()
+
+		for _, k := range oo.Keys {
+			if fv, ok := val.Values[k]; ok {
+				res.Set(k, reorder(fv, oo.Values[k]))
+			}
+		}
+
+		// keys missing in the order go last, in the order of the document
+		for _, k := range val.Keys {
+			if _, ok := oo.Values[k]; !ok {
+				res.Set(k, val.Values[k])
+			}
+		}
+
+		return res
+	case []any:
+		oa, _ := order.([]any)
+
+		for i := 0; i < len(val) && i < len(oa); i++ {
+			val[i] = reorder(val[i], oa[i])
+		}
+	}
+
+	return v
+}
+
+// Reorder reorders the fields of the document to match the order of the keys in the order value.
+// Order value is usually the result of DecodeOrdered.
+func Reorder(doc []byte, order any) ([]byte, error) {
+	v, err := DecodeOrdered(doc)
+	if err != nil {
+		return nil, err
+	}
+
+	return json.Marshal(reorder(v, order))
+}
+
+// KeepOrder restores the order of the fields of the modified document
+// from the original document, when PreserveOrder is enabled.
+// Fields added by the modification go after the original fields.
+func KeepOrder(orig []byte, doc []byte) ([]byte, error) {
+	if !PreserveOrder {
+		return doc, nil
+	}
+
+	order, err := DecodeOrdered(orig)
+	if err != nil {
+		return nil, err
+	}
+
+	return Reorder(doc, order)
+}
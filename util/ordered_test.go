@@ -0,0 +1,52 @@
+// Copyright 2022-2023 Tigris Data, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package util
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestKeepOrder(t *testing.T) {
+	PreserveOrder = true
+
+	defer func() { PreserveOrder = false }()
+
+	orig := []byte(`{"z":1,"b":{"y":null,"x":"s","w":[{"d":1,"c":2}]},"a":1.50,"n":null}`)
+
+	doc := CleanupNULLValues(orig)
+	assert.Equal(t, `{"z":1,"b":{"x":"s","w":[{"d":1,"c":2}]},"a":1.5}`, string(doc))
+
+	doc, err := KeepOrder(orig, []byte(`{"a":1.50,"added":true,"b":{"w":[{"c":2,"d":1}],"x":"s"},"z":1}`))
+	require.NoError(t, err)
+	assert.Equal(t, `{"z":1,"b":{"x":"s","w":[{"d":1,"c":2}]},"a":1.50,"added":true}`, string(doc))
+
+	tmpl := NewOrderedObject()
+	tmpl.Set("id", nil)
+	tmpl.Object("addr").Set("street", nil)
+	tmpl.Object("addr").Set("city", nil)
+
+	doc, err = Reorder([]byte(`{"addr":{"city":"a","street":"b"},"id":1}`), tmpl)
+	require.NoError(t, err)
+	assert.Equal(t, `{"id":1,"addr":{"street":"b","city":"a"}}`, string(doc))
+
+	PreserveOrder = false
+
+	doc, err = KeepOrder(orig, []byte(`{"a":1}`))
+	require.NoError(t, err)
+	assert.Equal(t, `{"a":1}`, string(doc))
+}
@@ -0,0 +1,104 @@
+// Copyright 2022-2023 Tigris Data, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package util
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"text/tabwriter"
+)
+
+const (
+	// StdoutName is the name of the output, which stands for the standard output.
+	StdoutName = "-"
+
+	OutputText = "text"
+	OutputJSON = "json"
+)
+
+var (
+	ErrOutputFileExists = fmt.Errorf("output file already exists. use --force to overwrite")
+	ErrInvalidOutput    = fmt.Errorf("invalid output format. allowed values: %s, %s", OutputText, OutputJSON)
+
+	// Output is the format of the output of the list commands: text or json.
+	Output = OutputText
+)
+
+func ValidateOutput() error {
+	switch Output {
+	case OutputText, OutputJSON:
+		return nil
+	}
+
+	return ErrInvalidOutput
+}
+
+// WriteList writes the list in the Output format: the rows of the text table,
+// aligned by the columns, under the header, if it's given, or the JSON array of the items.
+func WriteList(w io.Writer, header []string, rows [][]string, items any) error {
+	if Output == OutputJSON {
+		b, err := json.MarshalIndent(items, "", "  ")
+		if err != nil {
+			return err
+		}
+
+		_, err = fmt.Fprintf(w, "%s\n", b)
+
+		return err
+	}
+
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+
+	if len(header) > 0 {
+		_, _ = fmt.Fprintln(tw, strings.Join(header, "\t"))
+	}
+
+	for _, r := range rows {
+		_, _ = fmt.Fprintln(tw, strings.Join(r, "\t"))
+	}
+
+	return tw.Flush()
+}
+
+type nopWriteCloser struct {
+	io.Writer
+}
+
+func (nopWriteCloser) Close() error {
+	return nil
+}
+
+// CreateOutput opens the output: the standard output for empty name or "-",
+// or the file with the name otherwise. Existing file is overwritten only with force.
+func CreateOutput(name string, force bool) (io.WriteCloser, error) {
+	if name == "" || name == StdoutName {
+		return nopWriteCloser{os.Stdout}, nil
+	}
+
+	flags := os.O_CREATE | os.O_WRONLY | os.O_TRUNC
+	if !force {
+		flags |= os.O_EXCL
+	}
+
+	f, err := os.OpenFile(name, flags, 0o644) //nolint:gosec
+	if os.IsExist(err) {
+		return nil, fmt.Errorf("%w: %s", ErrOutputFileExists, name)
+	}
+
+	return f, err
+}
@@ -0,0 +1,90 @@
+// Copyright 2022-2023 Tigris Data, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package util
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCreateOutput(t *testing.T) {
+	for _, name := range []string{"", StdoutName} {
+		w, err := CreateOutput(name, false)
+		require.NoError(t, err)
+		assert.NoError(t, w.Close())
+	}
+
+	name := filepath.Join(t.TempDir(), "out.txt")
+
+	w, err := CreateOutput(name, false)
+	require.NoError(t, err)
+
+	_, err = w.Write([]byte("first"))
+	require.NoError(t, err)
+	require.NoError(t, w.Close())
+
+	_, err = CreateOutput(name, false)
+	require.ErrorIs(t, err, ErrOutputFileExists)
+
+	w, err = CreateOutput(name, true)
+	require.NoError(t, err)
+
+	_, err = w.Write([]byte("2nd"))
+	require.NoError(t, err)
+	require.NoError(t, w.Close())
+
+	b, err := os.ReadFile(name)
+	require.NoError(t, err)
+	assert.Equal(t, "2nd", string(b))
+}
+
+func TestWriteList(t *testing.T) {
+	defer func() { Output = OutputText }()
+
+	type item struct {
+		Name  string `json:"name"`
+		Count int64  `json:"count"`
+	}
+
+	items := []item{{"users", 10}, {"orders", 2000}}
+	rows := [][]string{{"users", "10"}, {"orders", "2000"}}
+
+	var buf bytes.Buffer
+
+	require.NoError(t, ValidateOutput())
+	require.NoError(t, WriteList(&buf, []string{"NAME", "COUNT"}, rows, items))
+	assert.Equal(t, "NAME    COUNT\nusers   10\norders  2000\n", buf.String())
+
+	buf.Reset()
+
+	require.NoError(t, WriteList(&buf, nil, [][]string{{"users"}}, items))
+	assert.Equal(t, "users\n", buf.String())
+
+	Output = OutputJSON
+
+	buf.Reset()
+
+	require.NoError(t, ValidateOutput())
+	require.NoError(t, WriteList(&buf, []string{"NAME", "COUNT"}, rows, items))
+	assert.JSONEq(t, `[{"name":"users","count":10},{"name":"orders","count":2000}]`, buf.String())
+
+	Output = "yaml"
+	require.ErrorIs(t, ValidateOutput(), ErrInvalidOutput)
+}
@@ -0,0 +1,137 @@
+// Copyright 2022-2023 Tigris Data, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package util
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+var (
+	ErrInvalidRename  = fmt.Errorf("invalid rename. expected old=new")
+	ErrRenameConflict = fmt.Errorf("conflicting rename target")
+)
+
+type rename struct {
+	from []string
+	to   []string
+}
+
+// Renames is the list of field renames, applied to the documents in order.
+type Renames []rename
+
+// ParseRenames parses old=new field renames.
+// Nested fields are specified using dot notation: address.zip=address.postal_code.
+func ParseRenames(defs []string) (Renames, error) {
+	res := make(Renames, 0, len(defs))
+	targets := make(map[string]string, len(defs))
+
+	for _, def := range defs {
+		from, to, ok := strings.Cut(def, "=")
+		from, to = strings.TrimSpace(from), strings.TrimSpace(to)
+
+		if !ok || from == "" || to == "" || from == to {
+			return nil, fmt.Errorf("%w: %s", ErrInvalidRename, def)
+		}
+
+		if prev, ok := targets[to]; ok {
+			return nil, fmt.Errorf("%w: both %s and %s renamed to %s", ErrRenameConflict, prev, from, to)
+		}
+
+		targets[to] = from
+
+		res = append(res, rename{from: strings.Split(from, "."), to: strings.Split(to, ".")})
+	}
+
+	return res, nil
+}
+
+// removePath removes the field from the document and returns its value.
+func removePath(m map[string]any, path []string) (any, bool) {
+	for i := 0; i < len(path)-1; i++ {
+		if m, _ = m[path[i]].(map[string]any); m == nil {
+			return nil, false
+		}
+	}
+
+	v, ok := m[path[len(path)-1]]
+	delete(m, path[len(path)-1])
+
+	return v, ok
+}
+
+// setPath sets the field of the document, creating intermediate objects as needed.
+// Returns false if the field already exists.
+func setPath(m map[string]any, path []string, v any) bool {
+	for i := 0; i < len(path)-1; i++ {
+		next, ok := m[path[i]].(map[string]any)
+		if !ok {
+			if m[path[i]] != nil {
+				return false
+			}
+
+			next = make(map[string]any)
+			m[path[i]] = next
+		}
+
+		m = next
+	}
+
+	if _, ok := m[path[len(path)-1]]; ok {
+		return false
+	}
+
+	m[path[len(path)-1]] = v
+
+	return true
+}
+
+// Apply renames the fields of the document.
+// Fields missing in the document are skipped.
+// It's an error if the target field already exists in the document.
+func (r Renames) Apply(doc []byte) ([]byte, error) {
+	if len(r) == 0 {
+		return doc, nil
+	}
+
+	var m map[string]any
+
+	dec := json.NewDecoder(bytes.NewReader(doc))
+	dec.UseNumber()
+
+	if err := dec.Decode(&m); err != nil {
+		return nil, err
+	}
+
+	for _, v := range r {
+		val, ok := removePath(m, v.from)
+		if !ok {
+			continue
+		}
+
+		if !setPath(m, v.to, val) {
+			return nil, fmt.Errorf("%w: field %s already exists", ErrRenameConflict, strings.Join(v.to, "."))
+		}
+	}
+
+	res, err := json.Marshal(m)
+	if err != nil {
+		return nil, err
+	}
+
+	return KeepOrder(doc, res)
+}
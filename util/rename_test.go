@@ -0,0 +1,51 @@
+// Copyright 2022-2023 Tigris Data, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package util
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRenames(t *testing.T) {
+	r, err := ParseRenames([]string{"name=full_name", "address.zip=address.postal_code", "tags=meta.tags", "missing=x"})
+	require.NoError(t, err)
+
+	doc, err := r.Apply([]byte(`{"id":12345678901234567,"name":"a","address":{"zip":"123","city":"b"},"tags":["t"]}`))
+	require.NoError(t, err)
+
+	assert.JSONEq(t, `{
+		"id": 12345678901234567,
+		"full_name": "a",
+		"address": {"postal_code": "123", "city": "b"},
+		"meta": {"tags": ["t"]}
+	}`, string(doc))
+
+	_, err = r.Apply([]byte(`{"name":"a","full_name":"b"}`))
+	require.ErrorIs(t, err, ErrRenameConflict)
+
+	_, err = r.Apply([]byte(`{"tags":["a"],"meta":1}`))
+	require.ErrorIs(t, err, ErrRenameConflict)
+
+	_, err = ParseRenames([]string{"a=c", "b=c"})
+	require.ErrorIs(t, err, ErrRenameConflict)
+
+	for _, def := range []string{"a", "=b", "a=", "a=a"} {
+		_, err = ParseRenames([]string{def})
+		require.ErrorIs(t, err, ErrInvalidRename, def)
+	}
+}
@@ -0,0 +1,38 @@
+// Copyright 2022-2023 Tigris Data, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package util
+
+import (
+	"fmt"
+)
+
+var (
+	// ShardKey is the field of the documents to route the inserts by,
+	// for better distribution of the writes of large imports.
+	ShardKey string
+
+	ErrShardKeyNotSupported = fmt.Errorf("the server doesn't support shard routing, --shard-key is ignored")
+)
+
+// CheckShardKey warns that --shard-key is not supported by the server.
+// The insert API doesn't accept routing metadata, neither per document nor per batch,
+// so it is checked before the import, in order to fail early in --strict mode.
+func CheckShardKey() error {
+	if ShardKey == "" {
+		return nil
+	}
+
+	return Warning(fmt.Errorf("%w: %s", ErrShardKeyNotSupported, ShardKey))
+}
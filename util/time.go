@@ -0,0 +1,37 @@
+// Copyright 2022-2023 Tigris Data, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package util
+
+import (
+	"fmt"
+	"time"
+)
+
+var ErrInvalidTime = fmt.Errorf("invalid time. expected RFC3339 time, like 2023-01-02T15:04:05Z, " +
+	"or duration relative to the current time, like -24h")
+
+// ParseTime parses the time in RFC3339 format or the duration relative to now,
+// so "-24h" is 24 hours before now.
+func ParseTime(s string, now time.Time) (time.Time, error) {
+	if t, err := time.Parse(time.RFC3339Nano, s); err == nil {
+		return t, nil
+	}
+
+	if d, err := time.ParseDuration(s); err == nil {
+		return now.Add(d), nil
+	}
+
+	return time.Time{}, fmt.Errorf("%w: %s", ErrInvalidTime, s)
+}
@@ -0,0 +1,38 @@
+// Copyright 2022-2023 Tigris Data, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package util
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseTime(t *testing.T) {
+	now := time.Date(2023, 5, 10, 12, 0, 0, 0, time.UTC)
+
+	tm, err := ParseTime("2023-01-02T15:04:05Z", now)
+	require.NoError(t, err)
+	assert.Equal(t, time.Date(2023, 1, 2, 15, 4, 5, 0, time.UTC), tm)
+
+	tm, err = ParseTime("-24h", now)
+	require.NoError(t, err)
+	assert.Equal(t, time.Date(2023, 5, 9, 12, 0, 0, 0, time.UTC), tm)
+
+	_, err = ParseTime("yesterday", now)
+	require.ErrorIs(t, err, ErrInvalidTime)
+}
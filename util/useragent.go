@@ -0,0 +1,71 @@
+// Copyright 2022-2023 Tigris Data, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package util
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/tigrisdata/tigris-cli/config"
+)
+
+var ErrUserAgentNotSupported = fmt.Errorf("the client library sets its own user agent of the requests to the server, " +
+	"--user-agent is only sent with the HTTP requests of the CLI: http(s):// and gs:// sources, webhooks and login")
+
+// UserAgent returns the user agent of the HTTP requests of the CLI:
+// configured by --user-agent or the user_agent config field, or the CLI name and version.
+func UserAgent() string {
+	if config.DefaultConfig.UserAgent != "" {
+		return config.DefaultConfig.UserAgent
+	}
+
+	if Version == "" {
+		return "tigris-cli/dev"
+	}
+
+	return "tigris-cli/" + Version
+}
+
+// CheckUserAgent warns that the custom user agent is not sent to the server.
+// The client library doesn't allow to override the user agent of the gRPC and HTTP transports.
+func CheckUserAgent() error {
+	if config.DefaultConfig.UserAgent == "" {
+		return nil
+	}
+
+	return Warning(ErrUserAgentNotSupported)
+}
+
+// userAgentTransport sets the user agent of the requests made by the libraries,
+// which don't allow to set the headers of the request, like oauth2.
+type userAgentTransport struct {
+	base http.RoundTripper
+}
+
+func (t *userAgentTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	req = req.Clone(req.Context())
+	req.Header.Set("User-Agent", UserAgent())
+
+	return t.base.RoundTrip(req)
+}
+
+// HTTPClient returns the HTTP client, which sends the UserAgent with every request.
+func HTTPClient(timeout time.Duration) *http.Client {
+	return &http.Client{
+		Timeout:   timeout,
+		Transport: &userAgentTransport{base: http.DefaultTransport},
+	}
+}
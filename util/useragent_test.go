@@ -0,0 +1,61 @@
+// Copyright 2022-2023 Tigris Data, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package util
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tigrisdata/tigris-cli/config"
+)
+
+func TestUserAgent(t *testing.T) {
+	defer func() {
+		Version = ""
+		config.DefaultConfig.UserAgent = ""
+	}()
+
+	assert.Equal(t, "tigris-cli/dev", UserAgent())
+	require.NoError(t, CheckUserAgent())
+
+	Version = "v1.2.3"
+	assert.Equal(t, "tigris-cli/v1.2.3", UserAgent())
+
+	config.DefaultConfig.UserAgent = "nightly-etl/1.0"
+	assert.Equal(t, "nightly-etl/1.0", UserAgent())
+}
+
+func TestHTTPClientUserAgent(t *testing.T) {
+	defer func() { config.DefaultConfig.UserAgent = "" }()
+
+	config.DefaultConfig.UserAgent = "nightly-etl/1.0"
+
+	var ua string
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ua = r.UserAgent()
+	}))
+	defer srv.Close()
+
+	resp, err := HTTPClient(time.Second).Get(srv.URL)
+	require.NoError(t, err)
+	require.NoError(t, resp.Body.Close())
+
+	assert.Equal(t, "nightly-etl/1.0", ua)
+}
@@ -37,6 +37,9 @@ var (
 	DefaultTimeout = 5 * time.Second
 
 	Quiet bool
+
+	// Strict turns warnings into errors.
+	Strict bool
 )
 
 func IsTTY(f *os.File) bool {
@@ -49,13 +52,17 @@ func LogConfigure(cfg *config.Log) {
 	zerolog.ErrorStackMarshaler = pkgerrors.MarshalStack
 	zerolog.TimeFieldFormat = zerolog.TimeFormatUnix
 
-	// Colored output to terminal and just JSON output to pipe
+	// Human-readable output to terminal and just JSON output to pipe,
+	// unless the format is set explicitly by --log-format.
+	// Colors are controlled by --color flag and NO_COLOR environment variable.
 	var output io.Writer = os.Stderr
 
-	if IsTTY(os.Stdout) {
-		output = zerolog.ConsoleWriter{Out: os.Stderr, TimeFormat: time.RFC3339}
+	if cfg.Format == LogFormatConsole || cfg.Format == "" && (IsTTY(os.Stdout) || Color == ColorAlways) {
+		output = zerolog.ConsoleWriter{Out: os.Stderr, TimeFormat: time.RFC3339, NoColor: !ColorEnabled(os.Stderr)}
 	}
 
+	jsonErrors = cfg.Format == LogFormatJSON
+
 	level := cfg.Level
 	if cfg.Level == "" {
 		level = "disabled"
@@ -113,6 +120,18 @@ func Infof(format string, args ...any) {
 	}
 }
 
+// Warning prints the warning to stderr.
+// In strict mode the warning is returned as an error instead.
+func Warning(err error) error {
+	if Strict {
+		return err
+	}
+
+	Stderrf("warning: %s\n", err.Error())
+
+	return nil
+}
+
 func Error(err error, msg string, args ...any) error {
 	log.Err(err).CallerSkipFrame(2).Msgf(msg, args...)
 
@@ -129,10 +148,16 @@ func Fatal(err error, msg string, args ...any) {
 		return
 	}
 
-	PrintError(err)
+	if jsonErrors {
+		printErrorJSON(os.Stderr, err, fmt.Sprintf(msg, args...))
+	} else {
+		PrintError(err)
+	}
 
 	_ = Error(err, msg, args...)
 
+	RunExitHooks(err)
+
 	os.Exit(1) //nolint:revive
 }
 
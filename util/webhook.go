@@ -0,0 +1,108 @@
+// Copyright 2022-2023 Tigris Data, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package util
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+)
+
+const (
+	WebhookSignatureHeader = "X-Tigris-Signature"
+
+	webhookAttempts = 3
+	webhookTimeout  = 10 * time.Second
+)
+
+var (
+	// webhookBackoff is the delay before the first retry, doubled on every next retry.
+	webhookBackoff = time.Second
+
+	ErrWebhookStatus = fmt.Errorf("webhook responded with error status")
+)
+
+// WebhookSignature returns the signature of the payload: "sha256=" followed by hex encoded HMAC-SHA256.
+func WebhookSignature(secret string, payload []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(payload)
+
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+func postWebhook(ctx context.Context, url string, secret string, payload []byte) (bool, error) {
+	ctx, cancel := context.WithTimeout(ctx, webhookTimeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(payload))
+	if err != nil {
+		return false, err
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", UserAgent())
+
+	if secret != "" {
+		req.Header.Set(WebhookSignatureHeader, WebhookSignature(secret, payload))
+	}
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return true, err
+	}
+
+	_ = resp.Body.Close()
+
+	if resp.StatusCode >= http.StatusMultipleChoices {
+		retry := resp.StatusCode >= http.StatusInternalServerError || resp.StatusCode == http.StatusTooManyRequests
+
+		return retry, fmt.Errorf("%w: %s", ErrWebhookStatus, resp.Status)
+	}
+
+	return false, nil
+}
+
+// PostWebhook posts the payload as JSON to the URL.
+// Network errors, server errors and throttling are retried with backoff.
+// When the secret is not empty, the payload is signed, see WebhookSignature.
+func PostWebhook(ctx context.Context, url string, secret string, payload any) error {
+	b, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+
+	backoff := webhookBackoff
+
+	for i := 1; ; i++ {
+		retry, err := postWebhook(ctx, url, secret, b)
+		if err == nil || !retry || i == webhookAttempts {
+			return err
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(backoff):
+		}
+
+		backoff *= 2
+	}
+}
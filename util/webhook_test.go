@@ -0,0 +1,73 @@
+// Copyright 2022-2023 Tigris Data, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package util
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPostWebhook(t *testing.T) {
+	webhookBackoff = time.Millisecond
+
+	defer func() { webhookBackoff = time.Second }()
+
+	var (
+		calls int
+		body  []byte
+		sig   string
+	)
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+
+		if calls == 1 {
+			w.WriteHeader(http.StatusBadGateway)
+			return
+		}
+
+		body, _ = io.ReadAll(r.Body)
+		sig = r.Header.Get(WebhookSignatureHeader)
+	}))
+	defer srv.Close()
+
+	err := PostWebhook(context.Background(), srv.URL, "secret", map[string]any{"inserted": 10})
+	require.NoError(t, err)
+
+	assert.Equal(t, 2, calls)
+	assert.JSONEq(t, `{"inserted":10}`, string(body))
+	assert.Equal(t, WebhookSignature("secret", body), sig)
+
+	// client errors are not retried
+	calls = 0
+
+	srv400 := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+
+		w.WriteHeader(http.StatusBadRequest)
+	}))
+	defer srv400.Close()
+
+	err = PostWebhook(context.Background(), srv400.URL, "", map[string]any{})
+	require.ErrorIs(t, err, ErrWebhookStatus)
+	assert.Equal(t, 1, calls)
+}